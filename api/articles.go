@@ -0,0 +1,310 @@
+package api
+
+import (
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/policy"
+	"go-articles-app/repository"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type createArticleRequest struct {
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+	AuthorID int    `json:"author_id"`
+}
+
+func (req *createArticleRequest) validate() error {
+	if req.AuthorID <= 0 {
+		return errors.New("author_id must be a positive integer")
+	}
+	return validateArticleText(&req.Title, req.Content)
+}
+
+type updateArticleRequest struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	// AuthorID меняет автора статьи; доступно только редакторам
+	AuthorID *int `json:"author_id"`
+}
+
+func (req *updateArticleRequest) validate() error {
+	if req.AuthorID != nil && *req.AuthorID <= 0 {
+		return errors.New("author_id must be a positive integer")
+	}
+	return validateArticleText(&req.Title, req.Content)
+}
+
+func validateArticleText(title *string, content string) error {
+	*title = strings.TrimSpace(*title)
+	if *title == "" {
+		return errors.New("title is required")
+	}
+	if utf8.RuneCountInString(*title) > 255 {
+		return errors.New("title must not exceed 255 characters")
+	}
+	if strings.TrimSpace(content) == "" {
+		return errors.New("content is required")
+	}
+	return nil
+}
+
+func (s *Server) listArticles(w http.ResponseWriter, r *http.Request) {
+	raw := r.URL.Query().Get("author_id")
+	if raw == "" {
+		writeError(w, http.StatusBadRequest, "author_id query parameter is required")
+		return
+	}
+	authorID, err := strconv.Atoi(raw)
+	if err != nil || authorID <= 0 {
+		writeError(w, http.StatusBadRequest, "author_id must be a positive integer")
+		return
+	}
+
+	page, err := pageRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Неопубликованные статьи автора видны ему самому и редакторам. Фильтр
+	// применяется в запросе, чтобы limit и курсор считались только по видимым
+	// статьям, а по пропускам нельзя было догадаться о скрытых.
+	unpublished := &models.Article{AuthorID: authorID, Status: models.ArticleDraft}
+	includeDrafts := policy.CanArticle(currentUser(r), policy.View, unpublished)
+	articles, err := s.articles.GetByAuthorIDPage(r.Context(), authorID, includeDrafts, page)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	views, err := s.articleViewPage(r.Context(), articles)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+func (s *Server) listPublished(w http.ResponseWriter, r *http.Request) {
+	page, err := pageRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	articles, err := s.articles.GetPublishedPage(r.Context(), page)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	views, err := s.articleViewPage(r.Context(), articles)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+// createArticle: POST /articles. Без author_id автором становится текущий
+// пользователь.
+func (s *Server) createArticle(w http.ResponseWriter, r *http.Request) {
+	var req createArticleRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	user := currentUser(r)
+	if req.AuthorID == 0 && user != nil {
+		req.AuthorID = user.ID
+	}
+	if !allow(w, r, policy.CanCreateArticle(user, req.AuthorID)) {
+		return
+	}
+	if err := req.validate(); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	article := &models.Article{
+		Title:    req.Title,
+		Content:  req.Content,
+		AuthorID: req.AuthorID,
+	}
+	if err := s.articles.Create(r.Context(), article); err != nil {
+		// Единственный внешний ключ статьи — author_id
+		if errors.Is(err, repository.ErrConflict) {
+			writeError(w, http.StatusUnprocessableEntity, "author does not exist")
+			return
+		}
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, article)
+}
+
+func (s *Server) getArticle(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	article, ok := s.authorizeArticle(w, r, id, policy.View)
+	if !ok {
+		return
+	}
+	views, err := s.articleViews(r.Context(), []*models.Article{article})
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, views[0])
+}
+
+// getArticleBySlug: GET /slugs/{slug}. Прежний слаг статьи
+// перенаправляет (301) на канонический адрес.
+func (s *Server) getArticleBySlug(w http.ResponseWriter, r *http.Request) {
+	requested := r.PathValue("slug")
+	article, err := s.articles.GetBySlug(r.Context(), requested)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	if !policy.CanArticle(currentUser(r), policy.View, article) {
+		writeError(w, http.StatusNotFound, "article not found")
+		return
+	}
+	if article.Slug != requested {
+		http.Redirect(w, r, "/slugs/"+article.Slug, http.StatusMovedPermanently)
+		return
+	}
+	views, err := s.articleViews(r.Context(), []*models.Article{article})
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, views[0])
+}
+
+// getRenderedArticle: GET /articles/{id}/rendered — содержание статьи в
+// санитизированном HTML и оглавление
+func (s *Server) getRenderedArticle(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.View); !ok {
+		return
+	}
+	doc, err := s.articles.GetRendered(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *Server) updateArticle(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req updateArticleRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	article, ok := s.authorizeArticle(w, r, id, policy.Edit)
+	if !ok {
+		return
+	}
+	if req.AuthorID != nil && *req.AuthorID != article.AuthorID {
+		if !allow(w, r, policy.CanArticle(currentUser(r), policy.Reassign, article)) {
+			return
+		}
+		article.AuthorID = *req.AuthorID
+	}
+	article.Title = req.Title
+	article.Content = req.Content
+
+	if err := s.articles.Update(r.Context(), article); err != nil {
+		writeStateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, article)
+}
+
+func (s *Server) deleteArticle(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.Delete); !ok {
+		return
+	}
+	if err := s.articles.Delete(r.Context(), id); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) publishArticle(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.Publish); !ok {
+		return
+	}
+	if err := s.articles.Publish(r.Context(), id); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+
+	article, err := s.articles.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, article)
+}
+
+func (s *Server) incrementViews(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.View); !ok {
+		return
+	}
+	if err := s.articles.IncrementViews(r.Context(), id); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+
+	article, err := s.articles.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, article)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// withRequestInfo передает репозиториям метаданные запроса для журнала
+// аудита. ID запроса берется из X-Request-ID, если его выставил прокси, иначе
+// генерируется; в обоих случаях он возвращается в ответе.
+func withRequestInfo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+
+		client := clientInfo(r)
+		ctx := repository.WithRequestInfo(r.Context(), repository.RequestInfo{
+			ID:        id,
+			IP:        client.IP,
+			UserAgent: client.UserAgent,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// validRequestID принимает короткие ID из букв, цифр, '-', '_' и '.'
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// listAudit: GET /audit?entity_type=article&entity_id=1&actor_id=2&action=publish&since=...&until=...
+func (s *Server) listAudit(w http.ResponseWriter, r *http.Request) {
+	page, err := pageRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	q := r.URL.Query()
+	query := repository.AuditQuery{
+		EntityType: models.AuditEntity(q.Get("entity_type")),
+		Action:     models.AuditAction(q.Get("action")),
+		Page:       page,
+	}
+	for name, dst := range map[string]*int{"entity_id": &query.EntityID, "actor_id": &query.ActorID} {
+		raw := q.Get(name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, name+" must be a positive integer")
+			return
+		}
+		*dst = n
+	}
+	for name, dst := range map[string]*time.Time{"since": &query.Since, "until": &query.Until} {
+		raw := q.Get(name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, name+" must be an RFC 3339 timestamp")
+			return
+		}
+		*dst = t
+	}
+
+	events, err := s.audit.List(r.Context(), query)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, events)
+}
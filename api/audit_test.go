@@ -0,0 +1,72 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"go-articles-app/auth"
+	"go-articles-app/models"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	srv, store := newTestServer(t, auth.SessionConfig{})
+	mustRegister(t, srv, "author@example.com", "correct horse")
+	mustRegister(t, srv, "admin@example.com", "correct horse")
+	author := mustLogin(t, srv, "author@example.com", "correct horse")
+	admin := mustLogin(t, srv, "admin@example.com", "correct horse")
+	if err := store.Users().SetRole(context.Background(), admin.User.ID, models.RoleAdmin); err != nil {
+		t.Fatalf("SetRole: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/articles", strings.NewReader(`{"title":"Hello","content":"text"}`))
+	req.Header.Set("Authorization", "Bearer "+author.Token)
+	req.Header.Set("X-Request-ID", "trace-42")
+	req.Header.Set("User-Agent", "audit-test")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create article = %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("X-Request-ID"); got != "trace-42" {
+		t.Fatalf("X-Request-ID = %q, want trace-42", got)
+	}
+	var article models.Article
+	if err := json.NewDecoder(rec.Body).Decode(&article); err != nil {
+		t.Fatalf("decode article: %v", err)
+	}
+
+	path := "/audit?entity_type=article&entity_id=" + strconv.Itoa(article.ID)
+	if rec := sendJSON(srv, http.MethodGet, path, author.Token, ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("author reads audit = %d, want 403", rec.Code)
+	}
+	rec = sendJSON(srv, http.MethodGet, path, admin.Token, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("admin reads audit = %d: %s", rec.Code, rec.Body)
+	}
+	var page struct {
+		Items []models.AuditEvent `json:"items"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+		t.Fatalf("decode audit page: %v", err)
+	}
+	if len(page.Items) != 1 {
+		t.Fatalf("got %d events, want 1", len(page.Items))
+	}
+	e := page.Items[0]
+	if e.Action != models.AuditCreate || e.ActorID == nil || *e.ActorID != author.User.ID {
+		t.Fatalf("event = %+v, want create by %d", e, author.User.ID)
+	}
+	if e.RequestID != "trace-42" || e.UserAgent != "audit-test" || e.IP == "" {
+		t.Fatalf("request metadata = %q %q %q", e.RequestID, e.UserAgent, e.IP)
+	}
+
+	for _, bad := range []string{"/audit?entity_id=1", "/audit?since=yesterday", "/audit?actor_id=x"} {
+		if rec := sendJSON(srv, http.MethodGet, bad, admin.Token, ""); rec.Code != http.StatusBadRequest {
+			t.Fatalf("GET %s = %d, want 400", bad, rec.Code)
+		}
+	}
+}
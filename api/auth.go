@@ -0,0 +1,109 @@
+package api
+
+import (
+	"errors"
+	"go-articles-app/auth"
+	"go-articles-app/models"
+	"net/http"
+	"strings"
+)
+
+type registerRequest struct {
+	userRequest
+	Password string `json:"password"`
+}
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type passwordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// writeAuthError отвечает 401 на неудачную проверку пароля или токена, не
+// уточняя причину
+func writeAuthError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		writeError(w, http.StatusUnauthorized, auth.ErrInvalidCredentials.Error())
+	case errors.Is(err, auth.ErrUnauthenticated):
+		writeUnauthorized(w, auth.ErrUnauthenticated.Error())
+	default:
+		writeRepoError(w, err)
+	}
+}
+
+// register: POST /auth/register
+func (s *Server) register(w http.ResponseWriter, r *http.Request) {
+	var req registerRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	user := &models.User{Email: req.Email, Name: req.Name}
+	if err := s.auth.Register(r.Context(), user, req.Password); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, user)
+}
+
+// login: POST /auth/login. Неизвестный email и неверный пароль дают
+// одинаковый ответ за одинаковое время. Успешный вход открывает сессию.
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := s.auth.Login(r.Context(), strings.TrimSpace(req.Email), req.Password)
+	if err != nil {
+		writeAuthError(w, err)
+		return
+	}
+	tokens, err := s.sessions.Start(r.Context(), user.ID, clientInfo(r))
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, loginResponse{User: user, Tokens: tokens})
+}
+
+// changePassword: PUT /users/{id}/password. Пароль меняет только сам
+// пользователь; все его сессии после этого завершаются.
+func (s *Server) changePassword(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if currentPrincipal(r).User.ID != id {
+		writeError(w, http.StatusForbidden, "cannot change another user's password")
+		return
+	}
+
+	var req passwordRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.auth.ChangePassword(r.Context(), id, req.CurrentPassword, req.NewPassword); err != nil {
+		writeAuthError(w, err)
+		return
+	}
+	if _, err := s.sessions.RevokeAll(r.Context(), id); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
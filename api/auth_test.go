@@ -0,0 +1,170 @@
+package api_test
+
+import (
+	"encoding/json"
+	"go-articles-app/api"
+	"go-articles-app/auth"
+	"go-articles-app/repository/memory"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newAuthServer(t *testing.T, sessions auth.SessionConfig) *api.Server {
+	t.Helper()
+	srv, _ := newTestServer(t, sessions)
+	return srv
+}
+
+// newTestServer возвращает сервер вместе с хранилищем, чтобы тест мог
+// подготовить данные в обход API
+func newTestServer(t *testing.T, sessions auth.SessionConfig) (*api.Server, *memory.Store) {
+	t.Helper()
+	store := memory.NewStore()
+	return api.NewServer(api.Stores{
+		Users:      store.Users(),
+		Articles:   store.Articles(),
+		Tags:       store.Tags(),
+		Categories: store.Categories(),
+		Comments:   store.Comments(),
+		Sessions:   store.Sessions(),
+		Audit:      store.Audit(),
+	}, api.Config{
+		PasswordParams: auth.Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		Sessions:       sessions,
+	}), store
+}
+
+// sendJSON выполняет запрос; непустой token передается как Bearer
+func sendJSON(srv http.Handler, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+type loginResult struct {
+	User struct {
+		ID int `json:"id"`
+	} `json:"user"`
+	SessionID   int    `json:"session_id"`
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+}
+
+func mustLogin(t *testing.T, srv http.Handler, email, password string) loginResult {
+	t.Helper()
+	rec := sendJSON(srv, http.MethodPost, "/auth/login", "", `{"email":"`+email+`","password":"`+password+`"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login = %d: %s", rec.Code, rec.Body)
+	}
+	var res loginResult
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+		t.Fatalf("decode login response: %v", err)
+	}
+	return res
+}
+
+func mustRegister(t *testing.T, srv http.Handler, email, password string) {
+	t.Helper()
+	rec := sendJSON(srv, http.MethodPost, "/auth/register", "", `{"email":"`+email+`","name":"Alice","password":"`+password+`"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("register = %d: %s", rec.Code, rec.Body)
+	}
+	if strings.Contains(rec.Body.String(), "argon2") || strings.Contains(rec.Body.String(), "password") {
+		t.Fatalf("register response leaks the password: %s", rec.Body)
+	}
+}
+
+func TestRegisterLoginChangePassword(t *testing.T) {
+	srv := newAuthServer(t, auth.SessionConfig{})
+	mustRegister(t, srv, "alice@example.com", "correct horse")
+	mustRegister(t, srv, "bob@example.com", "battery staple")
+	alice := mustLogin(t, srv, "alice@example.com", "correct horse")
+	bob := mustLogin(t, srv, "bob@example.com", "battery staple")
+
+	steps := []struct {
+		method, path, token, body string
+		want                      int
+	}{
+		{http.MethodPost, "/auth/register", "", `{"email":"carol@example.com","name":"Carol","password":"short"}`, http.StatusUnprocessableEntity},
+		{http.MethodPut, "/users/1/password", "", `{"current_password":"correct horse","new_password":"new password"}`, http.StatusUnauthorized},
+		{http.MethodPut, "/users/1/password", bob.Token, `{"current_password":"correct horse","new_password":"new password"}`, http.StatusForbidden},
+		{http.MethodPut, "/users/1/password", alice.Token, `{"current_password":"wrong horse","new_password":"new password"}`, http.StatusUnauthorized},
+		{http.MethodPut, "/users/1/password", alice.Token, `{"current_password":"correct horse","new_password":"new password"}`, http.StatusNoContent},
+		// Смена пароля завершает все сессии
+		{http.MethodGet, "/auth/me", alice.Token, "", http.StatusUnauthorized},
+		{http.MethodPost, "/auth/login", "", `{"email":"alice@example.com","password":"correct horse"}`, http.StatusUnauthorized},
+		{http.MethodPost, "/auth/login", "", `{"email":"alice@example.com","password":"new password"}`, http.StatusOK},
+	}
+	for _, st := range steps {
+		if rec := sendJSON(srv, st.method, st.path, st.token, st.body); rec.Code != st.want {
+			t.Fatalf("%s %s %s = %d, want %d: %s", st.method, st.path, st.body, rec.Code, st.want, rec.Body)
+		}
+	}
+}
+
+func TestLoginDoesNotRevealUsers(t *testing.T) {
+	srv := newAuthServer(t, auth.SessionConfig{})
+	mustRegister(t, srv, "alice@example.com", "correct horse")
+
+	wrong := sendJSON(srv, http.MethodPost, "/auth/login", "", `{"email":"alice@example.com","password":"wrong horse"}`)
+	unknown := sendJSON(srv, http.MethodPost, "/auth/login", "", `{"email":"nobody@example.com","password":"wrong horse"}`)
+	if wrong.Code != http.StatusUnauthorized || unknown.Code != http.StatusUnauthorized {
+		t.Fatalf("login codes = %d, %d, want 401", wrong.Code, unknown.Code)
+	}
+	if wrong.Body.String() != unknown.Body.String() {
+		t.Fatalf("responses differ: %q vs %q", wrong.Body, unknown.Body)
+	}
+}
+
+func TestSessionsEndpoints(t *testing.T) {
+	srv := newAuthServer(t, auth.SessionConfig{JWTKey: []byte("test key")})
+	mustRegister(t, srv, "alice@example.com", "correct horse")
+	first := mustLogin(t, srv, "alice@example.com", "correct horse")
+	second := mustLogin(t, srv, "alice@example.com", "correct horse")
+
+	if rec := sendJSON(srv, http.MethodGet, "/auth/me", first.AccessToken, ""); rec.Code != http.StatusOK {
+		t.Fatalf("me with access token = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := sendJSON(srv, http.MethodGet, "/auth/me", "garbage", ""); rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("me with bad token = %d, WWW-Authenticate %q", rec.Code, rec.Header().Get("WWW-Authenticate"))
+	}
+
+	rec := sendJSON(srv, http.MethodGet, "/auth/sessions", first.Token, "")
+	var sessions []struct {
+		ID int `json:"id"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&sessions); err != nil || len(sessions) != 2 {
+		t.Fatalf("sessions = %v, %v, want 2", sessions, err)
+	}
+
+	// Ротация: старый токен больше не действует
+	rec = sendJSON(srv, http.MethodPost, "/auth/refresh", "", `{"token":"`+second.Token+`"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("refresh = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := sendJSON(srv, http.MethodGet, "/auth/me", second.Token, ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("me with rotated token = %d, want 401", rec.Code)
+	}
+
+	path := "/auth/sessions/" + strconv.Itoa(second.SessionID)
+	if rec := sendJSON(srv, http.MethodDelete, path, first.Token, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("revoke = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := sendJSON(srv, http.MethodDelete, path, first.Token, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("revoke twice = %d, want 404", rec.Code)
+	}
+	if rec := sendJSON(srv, http.MethodPost, "/auth/logout", first.Token, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("logout = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := sendJSON(srv, http.MethodGet, "/auth/sessions", first.Token, ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("sessions after logout = %d, want 401", rec.Code)
+	}
+}
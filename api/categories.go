@@ -0,0 +1,153 @@
+package api
+
+import (
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/policy"
+	"go-articles-app/repository"
+	"net/http"
+)
+
+// categoryRequest — тело POST /categories и PUT /categories/{id}; пустой
+// slug строится из имени
+type categoryRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// setCategoryRequest — тело PUT /articles/{id}/category; null убирает статью
+// из рубрики
+type setCategoryRequest struct {
+	CategoryID *int `json:"category_id"`
+}
+
+// articleCategoryResponse — рубрика статьи; null, если ее нет
+type articleCategoryResponse struct {
+	Category *models.Category `json:"category"`
+}
+
+func (s *Server) listCategories(w http.ResponseWriter, r *http.Request) {
+	usages, err := s.categories.List(r.Context())
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, usages)
+}
+
+func (s *Server) createCategory(w http.ResponseWriter, r *http.Request) {
+	var req categoryRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	category := &models.Category{Name: req.Name, Slug: req.Slug}
+	if err := s.categories.Create(r.Context(), category); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, category)
+}
+
+func (s *Server) updateCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req categoryRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	category := &models.Category{ID: id, Name: req.Name, Slug: req.Slug}
+	if err := s.categories.Update(r.Context(), category); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, category)
+}
+
+func (s *Server) deleteCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.categories.Delete(r.Context(), id); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listArticlesByCategory: GET /categories/{slug}/articles
+func (s *Server) listArticlesByCategory(w http.ResponseWriter, r *http.Request) {
+	category, err := s.categories.GetBySlug(r.Context(), r.PathValue("slug"))
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+
+	articles, err := s.categories.GetArticlesByCategory(r.Context(), category.ID)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	views, err := s.articleViews(r.Context(), visibleArticles(r, articles))
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+func (s *Server) getArticleCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.View); !ok {
+		return
+	}
+	category, err := s.categories.GetArticleCategory(r.Context(), id)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, articleCategoryResponse{Category: category})
+}
+
+func (s *Server) setArticleCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req setCategoryRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.Edit); !ok {
+		return
+	}
+	category, err := s.categories.SetArticleCategory(r.Context(), id, req.CategoryID)
+	if errors.Is(err, repository.ErrConflict) {
+		writeError(w, http.StatusUnprocessableEntity, "category does not exist")
+		return
+	}
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, articleCategoryResponse{Category: category})
+}
@@ -0,0 +1,95 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"go-articles-app/auth"
+	"go-articles-app/models"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestCategories(t *testing.T) {
+	srv, store := newTestServer(t, auth.SessionConfig{})
+	mustRegister(t, srv, "author@example.com", "correct horse")
+	mustRegister(t, srv, "editor@example.com", "correct horse")
+	author := mustLogin(t, srv, "author@example.com", "correct horse")
+	editor := mustLogin(t, srv, "editor@example.com", "correct horse")
+	if err := store.Users().SetRole(context.Background(), editor.User.ID, models.RoleEditor); err != nil {
+		t.Fatalf("SetRole: %v", err)
+	}
+
+	// Рубрики заводят редакторы
+	if rec := sendJSON(srv, http.MethodPost, "/categories", author.Token, `{"name":"Go"}`); rec.Code != http.StatusForbidden {
+		t.Fatalf("author create category = %d, want 403", rec.Code)
+	}
+	rec := sendJSON(srv, http.MethodPost, "/categories", editor.Token, `{"name":"C++","slug":"cpp"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("editor create category = %d: %s", rec.Code, rec.Body)
+	}
+	var category models.Category
+	if err := json.NewDecoder(rec.Body).Decode(&category); err != nil {
+		t.Fatalf("decode category: %v", err)
+	}
+	if rec := sendJSON(srv, http.MethodPost, "/categories", editor.Token, `{"name":"c++"}`); rec.Code != http.StatusConflict {
+		t.Fatalf("duplicate category = %d, want 409", rec.Code)
+	}
+
+	rec = sendJSON(srv, http.MethodPost, "/articles", author.Token, `{"title":"Templates","content":"text"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create article = %d: %s", rec.Code, rec.Body)
+	}
+	var article models.Article
+	if err := json.NewDecoder(rec.Body).Decode(&article); err != nil {
+		t.Fatalf("decode article: %v", err)
+	}
+	path := "/articles/" + strconv.Itoa(article.ID) + "/category"
+
+	// Автор переносит свою статью в рубрику; несуществующая рубрика — 422
+	if rec := sendJSON(srv, http.MethodPut, path, author.Token, `{"category_id":999}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("set unknown category = %d, want 422", rec.Code)
+	}
+	set := `{"category_id":` + strconv.Itoa(category.ID) + `}`
+	if rec := sendJSON(srv, http.MethodPut, path, author.Token, set); rec.Code != http.StatusOK {
+		t.Fatalf("set category = %d: %s", rec.Code, rec.Body)
+	}
+	var got struct {
+		Category *models.Category `json:"category"`
+	}
+	rec = sendJSON(srv, http.MethodGet, path, author.Token, "")
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("decode article category: %v", err)
+	}
+	if rec.Code != http.StatusOK || got.Category == nil || got.Category.Slug != "cpp" {
+		t.Fatalf("get category = %d, %+v", rec.Code, got.Category)
+	}
+
+	// Черновик не виден в рубрике посторонним
+	countIn := func(token string) int {
+		t.Helper()
+		rec := sendJSON(srv, http.MethodGet, "/categories/cpp/articles", token, "")
+		var items []json.RawMessage
+		if err := json.NewDecoder(rec.Body).Decode(&items); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("list category articles = %d, %v", rec.Code, err)
+		}
+		return len(items)
+	}
+	if n := countIn(author.Token); n != 1 {
+		t.Fatalf("author sees %d articles in category, want 1", n)
+	}
+	if n := countIn(""); n != 0 {
+		t.Fatalf("anonymous sees %d articles in category, want 0", n)
+	}
+	if rec := sendJSON(srv, http.MethodGet, "/categories/missing/articles", "", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown category = %d, want 404", rec.Code)
+	}
+
+	if rec := sendJSON(srv, http.MethodPut, path, author.Token, `{"category_id":null}`); rec.Code != http.StatusOK {
+		t.Fatalf("clear category = %d: %s", rec.Code, rec.Body)
+	}
+	rec = sendJSON(srv, http.MethodGet, path, author.Token, "")
+	if body := rec.Body.String(); rec.Code != http.StatusOK || body != "{\"category\":null}\n" {
+		t.Fatalf("get cleared category = %d: %q", rec.Code, body)
+	}
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/policy"
+	"go-articles-app/repository"
+	"net/http"
+	"strconv"
+)
+
+type createCommentRequest struct {
+	AuthorID int    `json:"author_id"`
+	ParentID *int   `json:"parent_id"`
+	Body     string `json:"body"`
+}
+
+func (req *createCommentRequest) validate() error {
+	if req.AuthorID <= 0 {
+		return errors.New("author_id must be a positive integer")
+	}
+	if req.ParentID != nil && *req.ParentID <= 0 {
+		return errors.New("parent_id must be a positive integer")
+	}
+	return nil
+}
+
+type moderateCommentRequest struct {
+	Status models.CommentStatus `json:"status"`
+}
+
+// articleView — статья в ответах API вместе с числом одобренных комментариев
+type articleView struct {
+	*models.Article
+	CommentCount int `json:"comment_count"`
+}
+
+// articleViews дополняет статьи числом комментариев одним запросом на весь список
+func (s *Server) articleViews(ctx context.Context, articles []*models.Article) ([]articleView, error) {
+	ids := make([]int, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+	counts, err := s.comments.CountByArticles(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]articleView, len(articles))
+	for i, a := range articles {
+		views[i] = articleView{Article: a, CommentCount: counts[a.ID]}
+	}
+	return views, nil
+}
+
+func (s *Server) articleViewPage(ctx context.Context, page *repository.Page[*models.Article]) (*repository.Page[articleView], error) {
+	views, err := s.articleViews(ctx, page.Items)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.Page[articleView]{Items: views, NextCursor: page.NextCursor, HasMore: page.HasMore}, nil
+}
+
+// getComments: GET /articles/{id}/comments?all=true
+// Без all возвращается публичная ветка: одобренные комментарии и заглушки удаленных.
+func (s *Server) getComments(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	includeHidden := false
+	if raw := r.URL.Query().Get("all"); raw != "" {
+		includeHidden, err = strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "all must be a boolean")
+			return
+		}
+	}
+
+	if includeHidden && !allow(w, r, policy.CanModerate(currentUser(r))) {
+		return
+	}
+	if _, ok := s.authorizeArticle(w, r, id, policy.View); !ok {
+		return
+	}
+	thread, err := s.comments.GetThread(r.Context(), id, includeHidden)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, thread)
+}
+
+func (s *Server) createComment(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req createCommentRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	user := currentUser(r)
+	if req.AuthorID == 0 && user != nil {
+		req.AuthorID = user.ID
+	}
+	if !allow(w, r, policy.CanComment(user, req.AuthorID)) {
+		return
+	}
+	if err := req.validate(); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if _, ok := s.authorizeArticle(w, r, id, policy.View); !ok {
+		return
+	}
+
+	if _, err := s.users.GetByID(r.Context(), req.AuthorID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusUnprocessableEntity, "author does not exist")
+			return
+		}
+		writeRepoError(w, err)
+		return
+	}
+
+	comment := &models.Comment{
+		ArticleID: id,
+		AuthorID:  req.AuthorID,
+		ParentID:  req.ParentID,
+		Body:      req.Body,
+	}
+	if err := s.comments.Create(r.Context(), comment); err != nil {
+		writeStateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, comment)
+}
+
+// listComments: GET /comments?status=pending&limit=50 — очередь модерации
+func (s *Server) listComments(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	status := models.CommentStatus(q.Get("status"))
+	if status == "" {
+		status = models.CommentPending
+	}
+
+	limit := 0
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	comments, err := s.comments.ListByStatus(r.Context(), status, limit)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, comments)
+}
+
+func (s *Server) moderateComment(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req moderateCommentRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comment, err := s.comments.Moderate(r.Context(), id, req.Status)
+	if err != nil {
+		writeStateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, comment)
+}
+
+func (s *Server) deleteComment(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comment, err := s.comments.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	if !allow(w, r, policy.CanDeleteComment(currentUser(r), comment)) {
+		return
+	}
+	if err := s.comments.Delete(r.Context(), id); err != nil {
+		writeStateError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"go-articles-app/feed"
+	"go-articles-app/render"
+	"go-articles-app/repository"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	feedSize      = 20
+	excerptLength = 300
+)
+
+// siteFeed: GET /feed/{format}, format — rss или atom. Пока статей нет,
+// временем обновления служит запуск сервера.
+func (s *Server) siteFeed(w http.ResponseWriter, r *http.Request) {
+	s.serveFeed(w, r, s.cfg.SiteTitle, "/feed", repository.FeedQuery{}, s.started)
+}
+
+// authorFeed: GET /users/{id}/feed/{format}
+func (s *Server) authorFeed(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	author, err := s.users.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	title := s.cfg.SiteTitle + ": " + author.Name
+	s.serveFeed(w, r, title, "/users/"+strconv.Itoa(id)+"/feed", repository.FeedQuery{AuthorID: id}, author.CreatedAt)
+}
+
+// tagFeed: GET /tags/{name}/feed/{format}
+func (s *Server) tagFeed(w http.ResponseWriter, r *http.Request) {
+	tag, err := s.tags.GetByName(r.Context(), r.PathValue("name"))
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	title := s.cfg.SiteTitle + ": #" + tag.Name
+	s.serveFeed(w, r, title, "/tags/"+url.PathEscape(tag.Name)+"/feed", repository.FeedQuery{TagID: tag.ID}, tag.CreatedAt)
+}
+
+// serveFeed отдает ленту в формате из пути. path — адрес ленты без формата,
+// он же служит постоянным идентификатором Atom-ленты; since — время
+// обновления пустой ленты. Условные запросы (If-None-Match,
+// If-Modified-Since) обрабатывает http.ServeContent.
+func (s *Server) serveFeed(w http.ResponseWriter, r *http.Request, title, path string, q repository.FeedQuery, since time.Time) {
+	format := r.PathValue("format")
+	if format != "rss" && format != "atom" {
+		writeError(w, http.StatusNotFound, "unknown feed format "+strconv.Quote(format))
+		return
+	}
+
+	q.Limit = feedSize
+	articles, err := s.articles.ListFeed(r.Context(), q)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+
+	items := make([]feed.Item, 0, len(articles))
+	for _, a := range articles {
+		items = append(items, s.feedItem(a))
+	}
+	f := &feed.Feed{
+		ID:          s.cfg.SiteURL + path,
+		Title:       title,
+		Description: "Published articles of " + title,
+		Link:        s.cfg.SiteURL + "/",
+		Self:        s.cfg.SiteURL + path + "/" + format,
+		Updated:     feed.LastUpdated(items, since),
+		Items:       items,
+	}
+
+	var body []byte
+	if format == "rss" {
+		body, err = f.RSS()
+		w.Header().Set("Content-Type", feed.RSSContentType)
+	} else {
+		body, err = f.Atom()
+		w.Header().Set("Content-Type", feed.AtomContentType)
+	}
+	if err != nil {
+		log.Printf("feed error: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal server error")
+		return
+	}
+
+	// ETag меняется и тогда, когда статья уходит из ленты, а время
+	// обновления остается прежним
+	sum := sha256.Sum256(body)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	http.ServeContent(w, r, "", f.Updated, bytes.NewReader(body))
+}
+
+// feedItem строит запись ленты. GUID зависит только от домена сайта, даты
+// создания и ID статьи, поэтому не меняется при смене заголовка и слага.
+func (s *Server) feedItem(a *repository.ArticleWithAuthor) feed.Item {
+	published := a.Article.CreatedAt
+	if a.Article.PublishedAt != nil {
+		published = *a.Article.PublishedAt
+	}
+	// Публикация — тоже обновление записи: updated не раньше published
+	updated := a.Article.UpdatedAt
+	if published.After(updated) {
+		updated = published
+	}
+	return feed.Item{
+		ID:        feed.TagURI(s.feedAuthority(), a.Article.CreatedAt, "articles/"+strconv.Itoa(a.Article.ID)),
+		Title:     a.Article.Title,
+		Link:      s.cfg.SiteURL + "/slugs/" + a.Article.Slug,
+		Author:    a.AuthorName,
+		Summary:   render.Excerpt(a.Article.Content, excerptLength),
+		Published: published,
+		Updated:   updated,
+	}
+}
+
+// feedAuthority — домен сайта для tag URI (без схемы и порта)
+func (s *Server) feedAuthority() string {
+	if u, err := url.Parse(s.cfg.SiteURL); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return "localhost"
+}
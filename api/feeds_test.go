@@ -0,0 +1,117 @@
+package api_test
+
+import (
+	"context"
+	"go-articles-app/api"
+	"go-articles-app/models"
+	"go-articles-app/repository/memory"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newFeedServer(t *testing.T) *api.Server {
+	t.Helper()
+	ctx := context.Background()
+	store := memory.NewStore()
+	author := &models.User{Name: "Alice", Email: "alice@example.com"}
+	if err := store.Users().Create(ctx, author); err != nil {
+		t.Fatalf("Create user: %v", err)
+	}
+	article := &models.Article{Title: "Привет, мир", Content: "Первый **абзац**.", AuthorID: author.ID}
+	if err := store.Articles().Create(ctx, article); err != nil {
+		t.Fatalf("Create article: %v", err)
+	}
+	if err := store.Articles().Publish(ctx, article.ID); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	return api.NewServer(api.Stores{
+		Users:    store.Users(),
+		Articles: store.Articles(),
+		Tags:     store.Tags(),
+		Comments: store.Comments(),
+	}, api.Config{SiteURL: "https://blog.example.com/"})
+}
+
+func getFeed(srv http.Handler, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestFeedFormats(t *testing.T) {
+	srv := newFeedServer(t)
+	tests := []struct {
+		path, contentType, want string
+	}{
+		{"/feed/rss", "application/rss+xml", `<link>https://blog.example.com/slugs/privet-mir</link>`},
+		{"/feed/atom", "application/atom+xml", `<summary type="text">Первый абзац.</summary>`},
+		{"/users/1/feed/atom", "application/atom+xml", `<id>https://blog.example.com/users/1/feed</id>`},
+	}
+	for _, tt := range tests {
+		rec := getFeed(srv, tt.path, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", tt.path, rec.Code, rec.Body)
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.contentType) {
+			t.Fatalf("GET %s Content-Type = %q", tt.path, ct)
+		}
+		if !strings.Contains(rec.Body.String(), tt.want) {
+			t.Fatalf("GET %s body does not contain %s:\n%s", tt.path, tt.want, rec.Body)
+		}
+	}
+
+	for _, path := range []string{"/feed/json", "/users/99/feed/rss", "/tags/missing/feed/rss"} {
+		if rec := getFeed(srv, path, nil); rec.Code != http.StatusNotFound {
+			t.Fatalf("GET %s = %d, want 404", path, rec.Code)
+		}
+	}
+}
+
+func TestFeedConditionalGet(t *testing.T) {
+	srv := newFeedServer(t)
+	first := getFeed(srv, "/feed/rss", nil)
+	etag, modified := first.Header().Get("ETag"), first.Header().Get("Last-Modified")
+	if etag == "" || modified == "" {
+		t.Fatalf("ETag = %q, Last-Modified = %q", etag, modified)
+	}
+
+	if rec := getFeed(srv, "/feed/rss", http.Header{"If-None-Match": {etag}}); rec.Code != http.StatusNotModified {
+		t.Fatalf("If-None-Match: status = %d, want 304", rec.Code)
+	}
+	if rec := getFeed(srv, "/feed/rss", http.Header{"If-Modified-Since": {modified}}); rec.Code != http.StatusNotModified {
+		t.Fatalf("If-Modified-Since: status = %d, want 304", rec.Code)
+	}
+	if rec := getFeed(srv, "/feed/rss", http.Header{"If-None-Match": {`"stale"`}}); rec.Code != http.StatusOK {
+		t.Fatalf("stale If-None-Match: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestEmptySiteFeedUpdated(t *testing.T) {
+	store := memory.NewStore()
+	srv := api.NewServer(api.Stores{
+		Users:    store.Users(),
+		Articles: store.Articles(),
+		Tags:     store.Tags(),
+		Comments: store.Comments(),
+	}, api.Config{})
+
+	rec := getFeed(srv, "/feed/atom", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /feed/atom = %d: %s", rec.Code, rec.Body)
+	}
+	modified, err := http.ParseTime(rec.Header().Get("Last-Modified"))
+	if err != nil || time.Since(modified) > time.Minute {
+		t.Fatalf("Last-Modified = %q, want the server start time", rec.Header().Get("Last-Modified"))
+	}
+	if strings.Contains(rec.Body.String(), "0001-01-01") {
+		t.Fatalf("empty feed has a zero updated time:\n%s", rec.Body)
+	}
+}
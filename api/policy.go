@@ -0,0 +1,73 @@
+package api
+
+import (
+	"go-articles-app/models"
+	"go-articles-app/policy"
+	"net/http"
+)
+
+// currentUser — пользователь запроса или nil для анонимного
+func currentUser(r *http.Request) *models.User {
+	if p := currentPrincipal(r); p != nil {
+		return p.User
+	}
+	return nil
+}
+
+// writeForbidden отвечает 401 анонимному клиенту и 403 вошедшему
+func writeForbidden(w http.ResponseWriter, r *http.Request) {
+	if currentUser(r) == nil {
+		writeUnauthorized(w, "authentication required")
+		return
+	}
+	writeError(w, http.StatusForbidden, policy.ErrForbidden.Error())
+}
+
+// allow проверяет решение политики; при отказе пишет ответ и возвращает false
+func allow(w http.ResponseWriter, r *http.Request, ok bool) bool {
+	if !ok {
+		writeForbidden(w, r)
+	}
+	return ok
+}
+
+// authorizeArticle загружает статью и проверяет право action на нее. Статью,
+// которую пользователь не может даже прочитать, он не отличит от
+// несуществующей: ответ 404.
+func (s *Server) authorizeArticle(w http.ResponseWriter, r *http.Request, id int, action policy.Action) (*models.Article, bool) {
+	article, err := s.articles.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return nil, false
+	}
+	user := currentUser(r)
+	if !policy.CanArticle(user, policy.View, article) {
+		writeError(w, http.StatusNotFound, "article not found")
+		return nil, false
+	}
+	if !allow(w, r, policy.CanArticle(user, action, article)) {
+		return nil, false
+	}
+	return article, true
+}
+
+// visibleArticles убирает из списка статьи, которые пользователь не может читать
+func visibleArticles(r *http.Request, articles []*models.Article) []*models.Article {
+	user := currentUser(r)
+	visible := articles[:0:0]
+	for _, a := range articles {
+		if policy.CanArticle(user, policy.View, a) {
+			visible = append(visible, a)
+		}
+	}
+	return visible
+}
+
+// requireRole пропускает пользователей с ролью не ниже role
+func requireRole(role models.Role, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if allow(w, r, policy.AtLeast(currentUser(r), role)) {
+			next(w, r)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"go-articles-app/auth"
+	"go-articles-app/models"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRolePolicy(t *testing.T) {
+	srv, store := newTestServer(t, auth.SessionConfig{})
+	mustRegister(t, srv, "author@example.com", "correct horse")
+	mustRegister(t, srv, "other@example.com", "correct horse")
+	mustRegister(t, srv, "editor@example.com", "correct horse")
+	mustRegister(t, srv, "admin@example.com", "correct horse")
+
+	author := mustLogin(t, srv, "author@example.com", "correct horse")
+	other := mustLogin(t, srv, "other@example.com", "correct horse")
+	editor := mustLogin(t, srv, "editor@example.com", "correct horse")
+	admin := mustLogin(t, srv, "admin@example.com", "correct horse")
+
+	ctx := context.Background()
+	if err := store.Users().SetRole(ctx, editor.User.ID, models.RoleEditor); err != nil {
+		t.Fatalf("SetRole(editor): %v", err)
+	}
+	if err := store.Users().SetRole(ctx, admin.User.ID, models.RoleAdmin); err != nil {
+		t.Fatalf("SetRole(admin): %v", err)
+	}
+
+	// Анонимный клиент не может писать
+	if rec := sendJSON(srv, http.MethodPost, "/articles", "", `{"title":"Draft","content":"text"}`); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous create = %d, want 401", rec.Code)
+	}
+
+	rec := sendJSON(srv, http.MethodPost, "/articles", author.Token, `{"title":"Draft","content":"text"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("author create = %d: %s", rec.Code, rec.Body)
+	}
+	var article models.Article
+	if err := json.NewDecoder(rec.Body).Decode(&article); err != nil {
+		t.Fatalf("decode article: %v", err)
+	}
+	if article.AuthorID != author.User.ID {
+		t.Fatalf("author_id = %d, want %d", article.AuthorID, author.User.ID)
+	}
+	path := "/articles/" + strconv.Itoa(article.ID)
+
+	// Черновик чужого автора неотличим от несуществующей статьи
+	if rec := sendJSON(srv, http.MethodGet, path, other.Token, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("other get draft = %d, want 404", rec.Code)
+	}
+	if rec := sendJSON(srv, http.MethodGet, path, "", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("anonymous get draft = %d, want 404", rec.Code)
+	}
+	if rec := sendJSON(srv, http.MethodGet, path, editor.Token, ""); rec.Code != http.StatusOK {
+		t.Fatalf("editor get draft = %d, want 200", rec.Code)
+	}
+
+	// В списке статей автора черновик видят только он сам и редакторы
+	list := "/articles?author_id=" + strconv.Itoa(author.User.ID)
+	for _, tc := range []struct {
+		who   string
+		token string
+		want  int
+	}{{"author", author.Token, 1}, {"editor", editor.Token, 1}, {"other", other.Token, 0}, {"anonymous", "", 0}} {
+		rec := sendJSON(srv, http.MethodGet, list, tc.token, "")
+		var page struct {
+			Items   []json.RawMessage `json:"items"`
+			HasMore bool              `json:"has_more"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+			t.Fatalf("%s list: decode: %v", tc.who, err)
+		}
+		if rec.Code != http.StatusOK || len(page.Items) != tc.want || page.HasMore {
+			t.Fatalf("%s list = %d, %d items (has_more=%v), want %d", tc.who, rec.Code, len(page.Items), page.HasMore, tc.want)
+		}
+	}
+
+	// Автор правит свою статью, но не публикует ее и не меняет автора
+	if rec := sendJSON(srv, http.MethodPut, path, author.Token, `{"title":"Draft 2","content":"text"}`); rec.Code != http.StatusOK {
+		t.Fatalf("author update = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := sendJSON(srv, http.MethodPost, path+"/publish", author.Token, ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("author publish = %d, want 403", rec.Code)
+	}
+	reassign := `{"title":"Draft 2","content":"text","author_id":` + strconv.Itoa(other.User.ID) + `}`
+	if rec := sendJSON(srv, http.MethodPut, path, author.Token, reassign); rec.Code != http.StatusForbidden {
+		t.Fatalf("author reassign = %d, want 403", rec.Code)
+	}
+
+	if rec := sendJSON(srv, http.MethodPost, path+"/publish", editor.Token, ""); rec.Code != http.StatusOK {
+		t.Fatalf("editor publish = %d: %s", rec.Code, rec.Body)
+	}
+	if rec := sendJSON(srv, http.MethodGet, path, other.Token, ""); rec.Code != http.StatusOK {
+		t.Fatalf("other get published = %d, want 200", rec.Code)
+	}
+	if rec := sendJSON(srv, http.MethodPut, path, other.Token, `{"title":"Mine","content":"text"}`); rec.Code != http.StatusForbidden {
+		t.Fatalf("other update = %d, want 403", rec.Code)
+	}
+	if rec := sendJSON(srv, http.MethodPut, path, editor.Token, reassign); rec.Code != http.StatusOK {
+		t.Fatalf("editor reassign = %d: %s", rec.Code, rec.Body)
+	}
+
+	// Назначение ролей — только администратору и не самому себе
+	role := `{"role":"editor"}`
+	otherRole := "/users/" + strconv.Itoa(other.User.ID) + "/role"
+	if rec := sendJSON(srv, http.MethodPut, otherRole, editor.Token, role); rec.Code != http.StatusForbidden {
+		t.Fatalf("editor set role = %d, want 403", rec.Code)
+	}
+	if rec := sendJSON(srv, http.MethodPut, otherRole, admin.Token, `{"role":"root"}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("admin set unknown role = %d, want 422", rec.Code)
+	}
+	if rec := sendJSON(srv, http.MethodPut, otherRole, admin.Token, role); rec.Code != http.StatusOK {
+		t.Fatalf("admin set role = %d: %s", rec.Code, rec.Body)
+	}
+	selfRole := "/users/" + strconv.Itoa(admin.User.ID) + "/role"
+	if rec := sendJSON(srv, http.MethodPut, selfRole, admin.Token, `{"role":"reader"}`); rec.Code != http.StatusForbidden {
+		t.Fatalf("admin demote self = %d, want 403", rec.Code)
+	}
+
+	// Корзина статей — для редакторов
+	if rec := sendJSON(srv, http.MethodGet, "/trash/articles", author.Token, ""); rec.Code != http.StatusForbidden {
+		t.Fatalf("author trash = %d, want 403", rec.Code)
+	}
+	if rec := sendJSON(srv, http.MethodGet, "/trash/articles", editor.Token, ""); rec.Code != http.StatusOK {
+		t.Fatalf("editor trash = %d: %s", rec.Code, rec.Body)
+	}
+
+	// Пользователей видят только вошедшие, а email — сам пользователь и администратор
+	authorPath := "/users/" + strconv.Itoa(author.User.ID)
+	for _, path := range []string{"/users", authorPath} {
+		rec := sendJSON(srv, http.MethodGet, path, "", "")
+		if rec.Code != http.StatusUnauthorized || strings.Contains(rec.Body.String(), "@example.com") {
+			t.Fatalf("anonymous GET %s = %d: %s", path, rec.Code, rec.Body)
+		}
+	}
+	all := []string{"admin@example.com", "author@example.com", "editor@example.com", "other@example.com"}
+	for _, tc := range []struct {
+		who   string
+		token string
+		want  []string
+	}{{"author", author.Token, []string{"author@example.com"}}, {"editor", editor.Token, []string{"editor@example.com"}}, {"admin", admin.Token, all}} {
+		rec := sendJSON(srv, http.MethodGet, "/users?limit=100", tc.token, "")
+		var page struct {
+			Items []struct {
+				Email string `json:"email"`
+			} `json:"items"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&page); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("%s list users = %d, %v", tc.who, rec.Code, err)
+		}
+		var emails []string
+		for _, u := range page.Items {
+			if u.Email != "" {
+				emails = append(emails, u.Email)
+			}
+		}
+		slices.Sort(emails)
+		if len(page.Items) != len(all) || !slices.Equal(emails, tc.want) {
+			t.Fatalf("%s sees %d users with emails %v, want %v", tc.who, len(page.Items), emails, tc.want)
+		}
+	}
+	for _, tc := range []struct {
+		who   string
+		token string
+		email bool
+	}{{"author", author.Token, true}, {"other", other.Token, false}, {"admin", admin.Token, true}} {
+		rec := sendJSON(srv, http.MethodGet, authorPath, tc.token, "")
+		if got := strings.Contains(rec.Body.String(), "author@example.com"); rec.Code != http.StatusOK || got != tc.email {
+			t.Fatalf("%s GET %s = %d, email shown = %v", tc.who, authorPath, rec.Code, got)
+		}
+	}
+}
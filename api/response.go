@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-articles-app/repository"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const maxBodySize = 1 << 20
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("failed to encode response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
+
+// writeRepoError переводит ошибку репозитория в HTTP-ответ
+func writeRepoError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrAlreadyExists):
+		writeError(w, http.StatusConflict, "resource already exists")
+	case errors.Is(err, repository.ErrAlreadyPublished):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, repository.ErrInvalidCursor), errors.Is(err, repository.ErrInvalidQuery):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, repository.ErrInvalidInput):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, repository.ErrConflict):
+		writeError(w, http.StatusConflict, "request conflicts with current state")
+	default:
+		log.Printf("repository error: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+// writeStateError показывает клиенту причину конфликта: недопустимый переход
+// статуса статьи или комментария, ответ на скрытый комментарий
+func writeStateError(w http.ResponseWriter, err error) {
+	var pqErr interface{ SQLState() string }
+	if errors.Is(err, repository.ErrConflict) && !errors.As(err, &pqErr) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	writeRepoError(w, err)
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	if ct := r.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "application/json") {
+		return errors.New("content type must be application/json")
+	}
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		var maxErr *http.MaxBytesError
+		switch {
+		case errors.Is(err, io.EOF):
+			return errors.New("request body is empty")
+		case errors.As(err, &maxErr):
+			return fmt.Errorf("request body must not exceed %d bytes", maxErr.Limit)
+		default:
+			return fmt.Errorf("invalid JSON body: %w", err)
+		}
+	}
+
+	if dec.More() {
+		return errors.New("request body must contain a single JSON object")
+	}
+	return nil
+}
+
+func pathID(r *http.Request) (int, error) {
+	return pathInt(r, "id")
+}
+
+// pathInt читает положительное целое из сегмента пути {name}
+func pathInt(r *http.Request, name string) (int, error) {
+	n, err := strconv.Atoi(r.PathValue(name))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, r.PathValue(name))
+	}
+	return n, nil
+}
+
+// pageRequest читает параметры пагинации limit и cursor из query string
+func pageRequest(r *http.Request) (repository.PageRequest, error) {
+	q := r.URL.Query()
+	page := repository.PageRequest{Cursor: q.Get("cursor")}
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return page, errors.New("limit must be a positive integer")
+		}
+		page.Limit = limit
+	}
+	return page, nil
+}
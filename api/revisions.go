@@ -0,0 +1,100 @@
+package api
+
+import (
+	"go-articles-app/policy"
+	"net/http"
+	"strconv"
+)
+
+// listRevisions: историю правок видят те, кто может править статью: в ней
+// остаются неопубликованные варианты текста
+func (s *Server) listRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.Edit); !ok {
+		return
+	}
+	revisions, err := s.articles.ListRevisions(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, revisions)
+}
+
+func (s *Server) getRevision(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	rev, err := pathInt(r, "rev")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.Edit); !ok {
+		return
+	}
+	revision, err := s.articles.GetRevision(r.Context(), id, rev)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, revision)
+}
+
+// diffRevisions: GET /articles/{id}/diff?from=1&to=3
+func (s *Server) diffRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	q := r.URL.Query()
+	from, errFrom := strconv.Atoi(q.Get("from"))
+	to, errTo := strconv.Atoi(q.Get("to"))
+	if errFrom != nil || errTo != nil || from <= 0 || to <= 0 {
+		writeError(w, http.StatusBadRequest, "from and to must be positive revision numbers")
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.Edit); !ok {
+		return
+	}
+	d, err := s.articles.DiffRevisions(r.Context(), id, from, to)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+func (s *Server) restoreRevision(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	rev, err := pathInt(r, "rev")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.Edit); !ok {
+		return
+	}
+	article, err := s.articles.RestoreRevision(r.Context(), id, rev)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, article)
+}
@@ -0,0 +1,118 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"go-articles-app/auth"
+	"go-articles-app/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestRoutes собирает сервер (ServeMux паникует на конфликтующих
+// шаблонах) и один раз запрашивает каждый маршрут: запрос должен дойти до
+// обработчика, а не получить 404/405 от маршрутизатора или 5xx (кроме 501:
+// in-memory хранилище не умеет полнотекстовый поиск).
+func TestRoutes(t *testing.T) {
+	srv, store := newTestServer(t, auth.SessionConfig{})
+	mustRegister(t, srv, "admin@example.com", "correct horse")
+	admin := mustLogin(t, srv, "admin@example.com", "correct horse")
+	if err := store.Users().SetRole(context.Background(), admin.User.ID, models.RoleAdmin); err != nil {
+		t.Fatalf("SetRole: %v", err)
+	}
+
+	rec := sendJSON(srv, http.MethodPost, "/articles", admin.Token, `{"title":"Routes","content":"text"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create article = %d: %s", rec.Code, rec.Body)
+	}
+	var article models.Article
+	if err := json.NewDecoder(rec.Body).Decode(&article); err != nil {
+		t.Fatalf("decode article: %v", err)
+	}
+	if rec := sendJSON(srv, http.MethodPut, "/articles/"+strconv.Itoa(article.ID)+"/tags", admin.Token, `{"tags":["go"]}`); rec.Code != http.StatusOK {
+		t.Fatalf("set tags = %d: %s", rec.Code, rec.Body)
+	}
+
+	user := strconv.Itoa(admin.User.ID)
+	a := "/articles/" + strconv.Itoa(article.ID)
+	routes := []struct{ method, path, body string }{
+		{"GET", "/users", ""},
+		{"POST", "/users", `{"email":"new@example.com","name":"New"}`},
+		{"GET", "/users/" + user, ""},
+		{"PUT", "/users/" + user, `{"email":"admin@example.com","name":"Admin"}`},
+		{"PUT", "/users/" + user + "/role", `{"role":"admin"}`},
+		{"PUT", "/users/" + user + "/password", `{"current_password":"wrong","new_password":"another horse"}`},
+		{"GET", "/users/" + user + "/feed/rss", ""},
+
+		{"POST", "/auth/register", `{"email":"reader@example.com","name":"Reader","password":"correct horse"}`},
+		{"POST", "/auth/login", `{"email":"admin@example.com","password":"wrong"}`},
+		{"POST", "/auth/refresh", `{"refresh_token":"bogus"}`},
+		{"GET", "/auth/me", ""},
+		{"GET", "/auth/sessions", ""},
+
+		{"GET", "/articles", ""},
+		{"GET", "/articles/published", ""},
+		{"GET", "/articles/search?q=routes", ""},
+		{"GET", a, ""},
+		{"GET", a + "/rendered", ""},
+		{"PUT", a, `{"title":"Routes","content":"text 2"}`},
+		{"POST", a + "/publish", ""},
+		{"POST", a + "/status", `{"status":"archived"}`},
+		{"GET", a + "/transitions", ""},
+		{"POST", a + "/schedule", `{"publish_at":"2100-01-01T00:00:00Z"}`},
+		{"DELETE", a + "/schedule/publish", ""},
+		{"POST", a + "/views", ""},
+		{"GET", a + "/tags", ""},
+		{"PUT", a + "/tags", `{"tags":["go","sql"]}`},
+		{"GET", a + "/comments", ""},
+		{"POST", a + "/comments", `{"body":"hello"}`},
+		{"GET", a + "/revisions", ""},
+		{"GET", a + "/revisions/1", ""},
+		{"POST", a + "/revisions/1/restore", ""},
+		{"GET", a + "/diff?from=1&to=2", ""},
+		{"GET", "/slugs/" + article.Slug, ""},
+
+		{"GET", "/comments", ""},
+		{"POST", "/comments/1/moderate", `{"status":"approved"}`},
+		{"DELETE", "/comments/1", ""},
+
+		{"GET", "/tags", ""},
+		{"GET", "/tags/articles?tags=go", ""},
+		{"PUT", "/tags/1", `{"name":"golang"}`},
+		{"POST", "/tags/2/merge", `{"into":1}`},
+		{"DELETE", "/tags/1", ""},
+		{"GET", "/tags/golang/feed/atom", ""},
+
+		{"POST", "/categories", `{"name":"Backend"}`},
+		{"GET", "/categories", ""},
+		{"PUT", a + "/category", `{"category_id":1}`},
+		{"GET", a + "/category", ""},
+		{"GET", "/categories/backend/articles", ""},
+		{"PUT", "/categories/1", `{"name":"Server side"}`},
+		{"DELETE", "/categories/1", ""},
+
+		{"GET", "/feed/rss", ""},
+		{"GET", "/audit", ""},
+
+		{"DELETE", a, ""},
+		{"GET", "/trash/articles", ""},
+		{"POST", a + "/restore", ""},
+		{"DELETE", "/users/" + user, ""},
+		{"GET", "/trash/users", ""},
+		{"POST", "/users/" + user + "/restore", ""},
+
+		{"DELETE", "/auth/sessions/999", ""},
+		{"POST", "/auth/logout", ""},
+		{"DELETE", "/auth/sessions", ""},
+	}
+	for _, r := range routes {
+		rec := sendJSON(srv, r.method, r.path, admin.Token, r.body)
+		routed := rec.Code != http.StatusMethodNotAllowed &&
+			!(rec.Code == http.StatusNotFound && strings.HasPrefix(rec.Body.String(), "404 page not found"))
+		if !routed || rec.Code >= 500 && rec.Code != http.StatusNotImplemented {
+			t.Errorf("%s %s = %d: %s", r.method, r.path, rec.Code, rec.Body)
+		}
+	}
+}
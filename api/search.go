@@ -0,0 +1,44 @@
+package api
+
+import (
+	"go-articles-app/repository"
+	"net/http"
+	"strconv"
+)
+
+func (s *Server) searchArticles(w http.ResponseWriter, r *http.Request) {
+	searcher, ok := s.articles.(repository.ArticleSearcher)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, "search is not supported by this store")
+		return
+	}
+
+	q := r.URL.Query()
+	query := repository.SearchQuery{
+		Query: q.Get("q"),
+		Mode:  repository.SearchMode(q.Get("mode")),
+	}
+	if query.Query == "" {
+		writeError(w, http.StatusBadRequest, "q query parameter is required")
+		return
+	}
+	for name, dst := range map[string]*int{"limit": &query.Limit, "offset": &query.Offset} {
+		raw := q.Get(name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, name+" must be a non-negative integer")
+			return
+		}
+		*dst = n
+	}
+
+	results, err := searcher.Search(r.Context(), query)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
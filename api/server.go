@@ -0,0 +1,177 @@
+package api
+
+import (
+	"go-articles-app/auth"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Stores — хранилища, на которых построен API
+type Stores struct {
+	Users      repository.UserStore
+	Articles   repository.ArticleStore
+	Tags       repository.TagStore
+	Categories repository.CategoryStore
+	Comments   repository.CommentStore
+	Sessions   repository.SessionStore
+	Audit      repository.AuditStore
+}
+
+// Config — настройки API, не связанные с хранилищами
+type Config struct {
+	// SiteURL — внешний адрес сайта без завершающего /. Из него строятся
+	// ссылки и постоянные идентификаторы лент: после запуска его домен не
+	// должен меняться.
+	SiteURL   string
+	SiteTitle string
+	// PasswordParams — параметры argon2id для новых хэшей паролей
+	PasswordParams auth.Params
+	Sessions       auth.SessionConfig
+}
+
+const (
+	DefaultSiteURL   = "http://localhost:8080"
+	DefaultSiteTitle = "Go Articles"
+)
+
+type Server struct {
+	users      repository.UserStore
+	articles   repository.ArticleStore
+	tags       repository.TagStore
+	categories repository.CategoryStore
+	comments   repository.CommentStore
+	audit      repository.AuditStore
+	auth       *auth.Service
+	sessions   *auth.Sessions
+	cfg        Config
+	mux        *http.ServeMux
+	handler    http.Handler
+	// started — время обновления пустой ленты сайта
+	started time.Time
+}
+
+// NewServer создает API; пустые поля cfg заменяются значениями по умолчанию
+func NewServer(stores Stores, cfg Config) *Server {
+	cfg.SiteURL = strings.TrimRight(cfg.SiteURL, "/")
+	if cfg.SiteURL == "" {
+		cfg.SiteURL = DefaultSiteURL
+	}
+	if cfg.SiteTitle == "" {
+		cfg.SiteTitle = DefaultSiteTitle
+	}
+	if cfg.PasswordParams == (auth.Params{}) {
+		cfg.PasswordParams = auth.DefaultParams
+	}
+
+	s := &Server{
+		users:      stores.Users,
+		articles:   stores.Articles,
+		tags:       stores.Tags,
+		categories: stores.Categories,
+		comments:   stores.Comments,
+		audit:      stores.Audit,
+		auth:       auth.NewService(stores.Users, cfg.PasswordParams),
+		sessions:   auth.NewSessions(stores.Sessions, stores.Users, cfg.Sessions),
+		cfg:        cfg,
+		mux:        http.NewServeMux(),
+		started:    time.Now().UTC().Truncate(time.Second),
+	}
+	s.routes()
+	s.handler = withRequestInfo(s.authenticate(s.mux))
+	return s
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /users", s.requireUser(s.listUsers))
+	s.mux.HandleFunc("POST /users", requireRole(models.RoleAdmin, s.createUser))
+	s.mux.HandleFunc("GET /users/{id}", s.requireUser(s.getUser))
+	s.mux.HandleFunc("PUT /users/{id}", s.updateUser)
+	s.mux.HandleFunc("DELETE /users/{id}", s.deleteUser)
+	s.mux.HandleFunc("POST /users/{id}/restore", requireRole(models.RoleAdmin, s.restoreUser))
+	s.mux.HandleFunc("PUT /users/{id}/role", requireRole(models.RoleAdmin, s.setUserRole))
+	s.mux.HandleFunc("PUT /users/{id}/password", s.requireUser(s.changePassword))
+	s.mux.HandleFunc("GET /users/{id}/feed/{format}", s.authorFeed)
+
+	s.mux.HandleFunc("POST /auth/register", s.register)
+	s.mux.HandleFunc("POST /auth/login", s.login)
+	s.mux.HandleFunc("POST /auth/refresh", s.refresh)
+	s.mux.HandleFunc("POST /auth/logout", s.requireUser(s.logout))
+	s.mux.HandleFunc("GET /auth/me", s.requireUser(s.me))
+	s.mux.HandleFunc("GET /auth/sessions", s.requireUser(s.listSessions))
+	s.mux.HandleFunc("DELETE /auth/sessions", s.requireUser(s.revokeAllSessions))
+	s.mux.HandleFunc("DELETE /auth/sessions/{id}", s.requireUser(s.revokeSession))
+
+	s.mux.HandleFunc("GET /articles", s.listArticles)
+	s.mux.HandleFunc("GET /articles/published", s.listPublished)
+	s.mux.HandleFunc("GET /articles/search", s.searchArticles)
+	s.mux.HandleFunc("POST /articles", s.createArticle)
+	s.mux.HandleFunc("GET /articles/{id}", s.getArticle)
+	s.mux.HandleFunc("GET /articles/{id}/rendered", s.getRenderedArticle)
+	s.mux.HandleFunc("PUT /articles/{id}", s.updateArticle)
+	s.mux.HandleFunc("DELETE /articles/{id}", s.deleteArticle)
+	s.mux.HandleFunc("POST /articles/{id}/restore", requireRole(models.RoleEditor, s.restoreArticle))
+	s.mux.HandleFunc("POST /articles/{id}/publish", s.publishArticle)
+	s.mux.HandleFunc("POST /articles/{id}/status", s.changeArticleStatus)
+	s.mux.HandleFunc("GET /articles/{id}/transitions", s.listTransitions)
+	s.mux.HandleFunc("POST /articles/{id}/schedule", s.scheduleArticle)
+	s.mux.HandleFunc("DELETE /articles/{id}/schedule/{action}", s.cancelSchedule)
+	s.mux.HandleFunc("POST /articles/{id}/views", s.incrementViews)
+	s.mux.HandleFunc("GET /articles/{id}/tags", s.getArticleTags)
+	s.mux.HandleFunc("PUT /articles/{id}/tags", s.setArticleTags)
+	s.mux.HandleFunc("GET /articles/{id}/category", s.getArticleCategory)
+	s.mux.HandleFunc("PUT /articles/{id}/category", s.setArticleCategory)
+	s.mux.HandleFunc("GET /articles/{id}/comments", s.getComments)
+	s.mux.HandleFunc("POST /articles/{id}/comments", s.createComment)
+	s.mux.HandleFunc("GET /articles/{id}/revisions", s.listRevisions)
+	s.mux.HandleFunc("GET /articles/{id}/revisions/{rev}", s.getRevision)
+	s.mux.HandleFunc("POST /articles/{id}/revisions/{rev}/restore", s.restoreRevision)
+	s.mux.HandleFunc("GET /articles/{id}/diff", s.diffRevisions)
+
+	s.mux.HandleFunc("GET /trash/users", requireRole(models.RoleAdmin, s.listTrashedUsers))
+	s.mux.HandleFunc("GET /trash/articles", requireRole(models.RoleEditor, s.listTrashedArticles))
+
+	// Не /articles/{slug}: слаг из одних цифр неотличим от id
+	s.mux.HandleFunc("GET /slugs/{slug}", s.getArticleBySlug)
+
+	s.mux.HandleFunc("GET /comments", requireRole(models.RoleEditor, s.listComments))
+	s.mux.HandleFunc("POST /comments/{id}/moderate", requireRole(models.RoleEditor, s.moderateComment))
+	s.mux.HandleFunc("DELETE /comments/{id}", s.deleteComment)
+
+	s.mux.HandleFunc("GET /tags", s.listTags)
+	s.mux.HandleFunc("GET /tags/articles", s.listArticlesByTags)
+	s.mux.HandleFunc("PUT /tags/{id}", requireRole(models.RoleEditor, s.renameTag))
+	s.mux.HandleFunc("DELETE /tags/{id}", requireRole(models.RoleEditor, s.deleteTag))
+	s.mux.HandleFunc("POST /tags/{id}/merge", requireRole(models.RoleEditor, s.mergeTag))
+	s.mux.HandleFunc("GET /tags/{name}/feed/{format}", s.tagFeed)
+
+	s.mux.HandleFunc("GET /categories", s.listCategories)
+	s.mux.HandleFunc("POST /categories", requireRole(models.RoleEditor, s.createCategory))
+	s.mux.HandleFunc("PUT /categories/{id}", requireRole(models.RoleEditor, s.updateCategory))
+	s.mux.HandleFunc("DELETE /categories/{id}", requireRole(models.RoleEditor, s.deleteCategory))
+	s.mux.HandleFunc("GET /categories/{slug}/articles", s.listArticlesByCategory)
+
+	s.mux.HandleFunc("GET /feed/{format}", s.siteFeed)
+
+	s.mux.HandleFunc("GET /audit", requireRole(models.RoleAdmin, s.listAudit))
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.handler.ServeHTTP(rec, r)
+	log.Printf("%s %s %d %s", r.Method, r.URL.Path, rec.status, time.Since(start))
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/auth"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type principalKey struct{}
+
+// withPrincipal кладет пользователя запроса в контекст и помечает его как
+// автора изменений для репозиториев
+func withPrincipal(ctx context.Context, p *auth.Principal) context.Context {
+	ctx = context.WithValue(ctx, principalKey{}, p)
+	return repository.WithActor(ctx, p.User.ID)
+}
+
+// currentPrincipal возвращает пользователя запроса или nil для анонимного
+func currentPrincipal(r *http.Request) *auth.Principal {
+	p, _ := r.Context().Value(principalKey{}).(*auth.Principal)
+	return p
+}
+
+// authenticate разбирает заголовок Authorization: Bearer <токен>. Запрос
+// без заголовка проходит анонимно, с неверным токеном — отклоняется.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			writeUnauthorized(w, "authorization header must be Bearer <token>")
+			return
+		}
+		p, err := s.sessions.Authenticate(r.Context(), token)
+		if err != nil {
+			writeAuthError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), p)))
+	})
+}
+
+// requireUser пропускает только запросы с действующим токеном
+func (s *Server) requireUser(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if currentPrincipal(r) == nil {
+			writeUnauthorized(w, "authentication required")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func writeUnauthorized(w http.ResponseWriter, msg string) {
+	w.Header().Set("WWW-Authenticate", `Bearer`)
+	writeError(w, http.StatusUnauthorized, msg)
+}
+
+func clientInfo(r *http.Request) auth.Client {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return auth.Client{UserAgent: r.UserAgent(), IP: ip}
+}
+
+type loginResponse struct {
+	User *models.User `json:"user"`
+	*auth.Tokens
+}
+
+type refreshRequest struct {
+	Token string `json:"token"`
+}
+
+// refresh: POST /auth/refresh, ротация токена сессии
+func (s *Server) refresh(w http.ResponseWriter, r *http.Request) {
+	var req refreshRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tokens, err := s.sessions.Refresh(r.Context(), req.Token)
+	if err != nil {
+		writeAuthError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tokens)
+}
+
+// me: GET /auth/me
+func (s *Server) me(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, currentPrincipal(r).User)
+}
+
+// logout: POST /auth/logout, завершает текущую сессию
+func (s *Server) logout(w http.ResponseWriter, r *http.Request) {
+	p := currentPrincipal(r)
+	if err := s.sessions.Revoke(r.Context(), p.User.ID, p.SessionID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		writeRepoError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listSessions: GET /auth/sessions
+func (s *Server) listSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.sessions.List(r.Context(), currentPrincipal(r).User.ID)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, sessions)
+}
+
+// revokeSession: DELETE /auth/sessions/{id}
+func (s *Server) revokeSession(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.sessions.Revoke(r.Context(), currentPrincipal(r).User.ID, id); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// revokeAllSessions: DELETE /auth/sessions, в том числе текущую
+func (s *Server) revokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	n, err := s.sessions.RevokeAll(r.Context(), currentPrincipal(r).User.ID)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"revoked": n})
+}
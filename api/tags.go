@@ -0,0 +1,151 @@
+package api
+
+import (
+	"go-articles-app/policy"
+	"go-articles-app/repository"
+	"net/http"
+)
+
+type setTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+type renameTagRequest struct {
+	Name string `json:"name"`
+}
+
+type mergeTagRequest struct {
+	TargetID int `json:"target_id"`
+}
+
+func (s *Server) listTags(w http.ResponseWriter, r *http.Request) {
+	usages, err := s.tags.List(r.Context())
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, usages)
+}
+
+// listArticlesByTags: GET /tags/articles?tag=go&tag=db&match=all
+func (s *Server) listArticlesByTags(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	names := q["tag"]
+	if len(names) == 0 {
+		writeError(w, http.StatusBadRequest, "at least one tag query parameter is required")
+		return
+	}
+
+	articles, err := s.tags.GetArticlesByTags(r.Context(), names, repository.TagMatch(q.Get("match")))
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	views, err := s.articleViews(r.Context(), visibleArticles(r, articles))
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+func (s *Server) getArticleTags(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.View); !ok {
+		return
+	}
+	tags, err := s.tags.GetArticleTags(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tags)
+}
+
+func (s *Server) setArticleTags(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req setTagsRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.Edit); !ok {
+		return
+	}
+	tags, err := s.tags.SetArticleTags(r.Context(), id, req.Tags)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tags)
+}
+
+func (s *Server) renameTag(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req renameTagRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tag, err := s.tags.Rename(r.Context(), id, req.Name)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tag)
+}
+
+func (s *Server) mergeTag(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req mergeTagRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.TargetID <= 0 {
+		writeError(w, http.StatusUnprocessableEntity, "target_id must be a positive integer")
+		return
+	}
+
+	if err := s.tags.Merge(r.Context(), id, req.TargetID); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteTag(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.tags.Delete(r.Context(), id); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
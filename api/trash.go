@@ -0,0 +1,63 @@
+package api
+
+import "net/http"
+
+func (s *Server) listTrashedUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := s.users.ListTrash(r.Context())
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *Server) listTrashedArticles(w http.ResponseWriter, r *http.Request) {
+	articles, err := s.articles.ListTrash(r.Context())
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, articles)
+}
+
+// restoreUser: POST /users/{id}/restore — возвращает пользователя из корзины
+// вместе со статьями, удаленными вместе с ним
+func (s *Server) restoreUser(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.users.Restore(r.Context(), id); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	user, err := s.users.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+// restoreArticle: POST /articles/{id}/restore. Статью автора из корзины
+// восстановить нельзя, пока не восстановлен автор (409).
+func (s *Server) restoreArticle(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.articles.Restore(r.Context(), id); err != nil {
+		writeStateError(w, err)
+		return
+	}
+	article, err := s.articles.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, article)
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/policy"
+	"go-articles-app/repository"
+	"net/http"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+)
+
+type userRequest struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+func (req *userRequest) validate() error {
+	req.Email = strings.TrimSpace(req.Email)
+	req.Name = strings.TrimSpace(req.Name)
+
+	if req.Email == "" {
+		return errors.New("email is required")
+	}
+	if utf8.RuneCountInString(req.Email) > 255 {
+		return errors.New("email must not exceed 255 characters")
+	}
+	if addr, err := mail.ParseAddress(req.Email); err != nil || addr.Address != req.Email {
+		return errors.New("email is invalid")
+	}
+	if req.Name == "" {
+		return errors.New("name is required")
+	}
+	if utf8.RuneCountInString(req.Name) > 255 {
+		return errors.New("name must not exceed 255 characters")
+	}
+	return nil
+}
+
+// createUserRequest — создание пользователя администратором: в отличие от
+// регистрации, роль можно задать сразу
+type createUserRequest struct {
+	userRequest
+	Role models.Role `json:"role"`
+}
+
+func (req *createUserRequest) validate() error {
+	if err := req.userRequest.validate(); err != nil {
+		return err
+	}
+	if req.Role != "" && !req.Role.Valid() {
+		return errors.New("role is invalid")
+	}
+	return nil
+}
+
+type roleRequest struct {
+	Role models.Role `json:"role"`
+}
+
+// userView — пользователь в публичных ответах API. Email видят только сам
+// пользователь и администраторы, остальным поле не отдается.
+type userView struct {
+	*models.User
+	Email string `json:"email,omitempty"`
+}
+
+func newUserView(viewer, user *models.User) userView {
+	v := userView{User: user}
+	if policy.CanManageUser(viewer, user.ID) {
+		v.Email = user.Email
+	}
+	return v
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request) {
+	page, err := pageRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	users, err := s.users.GetAllPage(r.Context(), page)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+
+	viewer := currentUser(r)
+	views := make([]userView, len(users.Items))
+	for i, u := range users.Items {
+		views[i] = newUserView(viewer, u)
+	}
+	writeJSON(w, http.StatusOK, &repository.Page[userView]{Items: views, NextCursor: users.NextCursor, HasMore: users.HasMore})
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request) {
+	var req createUserRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	user := &models.User{Email: req.Email, Name: req.Name, Role: req.Role}
+	if err := s.users.Create(r.Context(), user); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, user)
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := s.users.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newUserView(currentUser(r), user))
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !allow(w, r, policy.CanManageUser(currentUser(r), id)) {
+		return
+	}
+
+	var req userRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := req.validate(); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	user, err := s.users.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	user.Email = req.Email
+	user.Name = req.Name
+
+	if err := s.users.Update(r.Context(), user); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !allow(w, r, policy.CanManageUser(currentUser(r), id)) {
+		return
+	}
+
+	if err := s.users.Delete(r.Context(), id); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setUserRole меняет роль пользователя. Маршрут доступен только
+// администраторам; понизить самого себя нельзя, чтобы не остаться без
+// администратора
+func (s *Server) setUserRole(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if user := currentUser(r); user != nil && user.ID == id {
+		writeError(w, http.StatusForbidden, "cannot change own role")
+		return
+	}
+
+	var req roleRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !req.Role.Valid() {
+		writeError(w, http.StatusUnprocessableEntity, "role is invalid")
+		return
+	}
+
+	if err := s.users.SetRole(r.Context(), id, req.Role); err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	user, err := s.users.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"go-articles-app/models"
+	"go-articles-app/policy"
+	"go-articles-app/repository"
+	"net/http"
+	"time"
+)
+
+type transitionRequest struct {
+	Status models.ArticleStatus `json:"status"`
+}
+
+// changeArticleStatus: POST /articles/{id}/status {"status": "in_review"}
+func (s *Server) changeArticleStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req transitionRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Status == "" {
+		writeError(w, http.StatusUnprocessableEntity, "status is required")
+		return
+	}
+
+	current, ok := s.authorizeArticle(w, r, id, policy.View)
+	if !ok {
+		return
+	}
+	if !allow(w, r, policy.CanTransition(currentUser(r), current, req.Status)) {
+		return
+	}
+	article, err := s.articles.Transition(r.Context(), id, req.Status)
+	if err != nil {
+		writeStateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, article)
+}
+
+func (s *Server) listTransitions(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.View); !ok {
+		return
+	}
+	transitions, err := s.articles.ListTransitions(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, transitions)
+}
+
+type scheduleRequest struct {
+	Action repository.ScheduleAction `json:"action"`
+	At     time.Time                 `json:"at"`
+}
+
+// scheduleArticle: POST /articles/{id}/schedule {"action": "publish", "at": "2025-01-01T09:00:00+03:00"}
+func (s *Server) scheduleArticle(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req scheduleRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.Publish); !ok {
+		return
+	}
+	article, err := s.articles.Schedule(r.Context(), id, req.Action, req.At)
+	if err != nil {
+		writeStateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, article)
+}
+
+// cancelSchedule: DELETE /articles/{id}/schedule/{action}
+func (s *Server) cancelSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := s.authorizeArticle(w, r, id, policy.Publish); !ok {
+		return
+	}
+	article, err := s.articles.CancelSchedule(r.Context(), id, repository.ScheduleAction(r.PathValue("action")))
+	if err != nil {
+		writeRepoError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, article)
+}
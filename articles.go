@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"io"
+	"strconv"
+	"strings"
+)
+
+func runArticles(ctx context.Context, c *cli, args []string) error {
+	return subcommand(ctx, c, "articles", args, map[string]func(context.Context, *cli, []string) error{
+		"list":    runArticlesList,
+		"show":    runArticlesShow,
+		"publish": runArticlesPublish,
+		"delete":  runArticlesDelete,
+	})
+}
+
+// runArticlesList: articles list [-o json] [-author ID] [-status S,...] [-title T] [-sort F] [-asc] [-limit N] [-offset N]
+func runArticlesList(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlagSet("articles list")
+	out := formatFlag(fs)
+	author := fs.Int("author", 0, "only articles by this author ID")
+	statuses := fs.String("status", "", "comma-separated statuses: draft, in_review, published, archived")
+	title := fs.String("title", "", "title contains this text (case-insensitive)")
+	sortBy := fs.String("sort", string(repository.SortByCreatedAt), "sort by created_at, updated_at or views")
+	asc := fs.Bool("asc", false, "sort ascending")
+	limit := fs.Int("limit", repository.DefaultPageSize, "articles per page")
+	offset := fs.Int("offset", 0, "articles to skip")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	q := repository.ArticleQuery{
+		Filter:    repository.ArticleFilter{TitleContains: *title},
+		SortBy:    repository.ArticleSortField(*sortBy),
+		Ascending: *asc,
+		Limit:     *limit,
+		Offset:    *offset,
+	}
+	if *author != 0 {
+		q.Filter.AuthorIDs = []int{*author}
+	}
+	if *statuses != "" {
+		for _, st := range strings.Split(*statuses, ",") {
+			q.Filter.Statuses = append(q.Filter.Statuses, models.ArticleStatus(strings.TrimSpace(st)))
+		}
+	}
+
+	list, err := c.articles.Find(ctx, q)
+	if err != nil {
+		return err
+	}
+	err = c.print(*out, list, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tSTATUS\tVIEWS\tAUTHOR\tCREATED\tTITLE")
+		for _, a := range list.Articles {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%s\t%s\n", a.ID, a.Status, a.Views, a.AuthorID, formatTime(a.CreatedAt), a.Title)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if *out == formatTable && len(list.Articles) > 0 {
+		fmt.Fprintf(c.stderr, "articles %d-%d of %d\n", *offset+1, *offset+len(list.Articles), list.Total)
+	}
+	return nil
+}
+
+// articleView — статья с автором для вывода articles show
+type articleView struct {
+	*models.Article
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+}
+
+// runArticlesShow: articles show [-o json] ID|SLUG
+func runArticlesShow(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlagSet("articles show")
+	out := formatFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: articles show [-o json] ID|SLUG")
+	}
+
+	id, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		article, err := c.articles.GetBySlug(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		id = article.ID
+	}
+	a, err := c.articles.GetArticleWithAuthor(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	view := articleView{Article: a.Article, AuthorName: a.AuthorName, AuthorEmail: a.AuthorEmail}
+	err = c.print(*out, view, func(w io.Writer) {
+		fmt.Fprintf(w, "ID:\t%d\n", view.ID)
+		fmt.Fprintf(w, "Title:\t%s\n", view.Title)
+		fmt.Fprintf(w, "Slug:\t%s\n", view.Slug)
+		fmt.Fprintf(w, "Author:\t%s <%s> (%d)\n", view.AuthorName, view.AuthorEmail, view.AuthorID)
+		fmt.Fprintf(w, "Status:\t%s\n", view.Status)
+		fmt.Fprintf(w, "Published:\t%s\n", formatTimePtr(view.PublishedAt))
+		fmt.Fprintf(w, "Views:\t%d\n", view.Views)
+		fmt.Fprintf(w, "Created:\t%s\n", formatTime(view.CreatedAt))
+		fmt.Fprintf(w, "Updated:\t%s\n", formatTime(view.UpdatedAt))
+	})
+	if err != nil {
+		return err
+	}
+	// Текст печатается после таблицы, чтобы табуляции в нем не выравнивались
+	if *out == formatTable {
+		fmt.Fprintf(c.stdout, "\n%s\n", view.Content)
+	}
+	return nil
+}
+
+// runArticlesPublish: articles publish ID
+func runArticlesPublish(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlagSet("articles publish")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := parseID(fs, "article")
+	if err != nil {
+		return err
+	}
+	if err := c.articles.Publish(ctx, id); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "Published article %d\n", id)
+	return nil
+}
+
+// runArticlesDelete: articles delete ID — перемещает статью в корзину
+func runArticlesDelete(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlagSet("articles delete")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := parseID(fs, "article")
+	if err != nil {
+		return err
+	}
+	if err := c.articles.Delete(ctx, id); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "Moved article %d to the trash\n", id)
+	return nil
+}
@@ -0,0 +1,115 @@
+// Package auth отвечает за пароли пользователей: хэширование argon2id,
+// проверку старых bcrypt-хэшей, регистрацию, вход и смену пароля.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Params — параметры argon2id. Memory задается в КиБ.
+type Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultParams — минимальная конфигурация argon2id, рекомендованная OWASP:
+// 19 МиБ памяти, 2 прохода, 1 поток
+var DefaultParams = Params{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+var errMalformedHash = errors.New("malformed password hash")
+
+// Hasher хэширует пароли с заданными параметрами и проверяет хэши,
+// построенные с любыми параметрами
+type Hasher struct {
+	params Params
+}
+
+func NewHasher(params Params) *Hasher {
+	return &Hasher{params: params}
+}
+
+// Hash возвращает хэш в формате PHC:
+// $argon2id$v=19$m=19456,t=2,p=1$<соль>$<ключ> (base64 без выравнивания)
+func (h *Hasher) Hash(password string) string {
+	p := h.params
+	salt := make([]byte, p.SaltLength)
+	rand.Read(salt) // crypto/rand.Read не возвращает ошибок
+
+	key := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key))
+}
+
+// Verify сравнивает пароль с хэшем за время, не зависящее от того, где
+// они расходятся. rehash = true, если пароль верен, но хэш построен другим
+// алгоритмом (bcrypt) или с другими параметрами и его стоит пересчитать.
+func (h *Hasher) Verify(password, encoded string) (ok, rehash bool, err error) {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return h.verifyArgon2(password, encoded)
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+		if err != nil {
+			return false, false, fmt.Errorf("%w: %w", errMalformedHash, err)
+		}
+		return true, true, nil
+	}
+	return false, false, errMalformedHash
+}
+
+func (h *Hasher) verifyArgon2(password, encoded string) (ok, rehash bool, err error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", соль, ключ
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return false, false, errMalformedHash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false, fmt.Errorf("%w: unsupported argon2 version %q", errMalformedHash, parts[2])
+	}
+	var p Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return false, false, fmt.Errorf("%w: %w", errMalformedHash, err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false, fmt.Errorf("%w: %w", errMalformedHash, err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false, false, fmt.Errorf("%w: invalid key", errMalformedHash)
+	}
+	if p.Iterations == 0 || p.Parallelism == 0 {
+		return false, false, fmt.Errorf("%w: invalid parameters", errMalformedHash)
+	}
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+
+	got := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	if subtle.ConstantTimeCompare(got, key) != 1 {
+		return false, false, nil
+	}
+	return true, p != h.params, nil
+}
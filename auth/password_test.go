@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testParams — дешевые параметры, чтобы тесты не тратили память и время
+var testParams = Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestHashVerify(t *testing.T) {
+	h := NewHasher(testParams)
+	hash := h.Hash("correct horse")
+
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Fatalf("Hash = %q, want PHC string with test params", hash)
+	}
+	if other := h.Hash("correct horse"); other == hash {
+		t.Fatal("two hashes of one password are equal: salt is not random")
+	}
+
+	tests := []struct {
+		password string
+		ok       bool
+	}{
+		{"correct horse", true},
+		{"correct horse ", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		ok, rehash, err := h.Verify(tt.password, hash)
+		if err != nil {
+			t.Fatalf("Verify(%q): %v", tt.password, err)
+		}
+		if ok != tt.ok || rehash {
+			t.Errorf("Verify(%q) = %v, %v, want %v, false", tt.password, ok, rehash, tt.ok)
+		}
+	}
+}
+
+func TestVerifyRehash(t *testing.T) {
+	old := NewHasher(testParams).Hash("secret password")
+
+	stronger := testParams
+	stronger.Iterations = 2
+	ok, rehash, err := NewHasher(stronger).Verify("secret password", old)
+	if err != nil || !ok || !rehash {
+		t.Fatalf("Verify with changed params = %v, %v, %v, want true, true, nil", ok, rehash, err)
+	}
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("secret password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, rehash, err = NewHasher(testParams).Verify("secret password", string(legacy))
+	if err != nil || !ok || !rehash {
+		t.Fatalf("Verify bcrypt = %v, %v, %v, want true, true, nil", ok, rehash, err)
+	}
+	ok, _, err = NewHasher(testParams).Verify("wrong password", string(legacy))
+	if err != nil || ok {
+		t.Fatalf("Verify bcrypt with wrong password = %v, %v, want false, nil", ok, err)
+	}
+}
+
+func TestVerifyMalformed(t *testing.T) {
+	h := NewHasher(testParams)
+	for _, hash := range []string{
+		"",
+		"plain text",
+		"$argon2i$v=19$m=1024,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=18$m=1024,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$!!!$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA$",
+	} {
+		if ok, _, err := h.Verify("password", hash); err == nil || ok {
+			t.Errorf("Verify(%q) = %v, %v, want error", hash, ok, err)
+		}
+	}
+}
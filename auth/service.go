@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"log"
+	"unicode/utf8"
+)
+
+// ErrInvalidCredentials возвращается при любой неудачной попытке входа:
+// неизвестный email, пользователь без пароля и неверный пароль
+// неотличимы для клиента
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+const (
+	MinPasswordLength = 8
+	// MaxPasswordLength ограничивает работу хэш-функции на один запрос
+	MaxPasswordLength = 256
+)
+
+// Service — регистрация, вход и смена пароля поверх repository.UserStore
+type Service struct {
+	users  repository.UserStore
+	hasher *Hasher
+	// dummyHash проверяется, когда пользователя нет: неудачный вход занимает
+	// столько же времени, сколько вход с неверным паролем
+	dummyHash string
+}
+
+func NewService(users repository.UserStore, params Params) *Service {
+	hasher := NewHasher(params)
+	return &Service{
+		users:     users,
+		hasher:    hasher,
+		dummyHash: hasher.Hash("dummy password"),
+	}
+}
+
+// ValidatePassword проверяет длину нового пароля
+func ValidatePassword(password string) error {
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return fmt.Errorf("%w: password must be at least %d characters", repository.ErrInvalidInput, MinPasswordLength)
+	}
+	if utf8.RuneCountInString(password) > MaxPasswordLength {
+		return fmt.Errorf("%w: password must not exceed %d characters", repository.ErrInvalidInput, MaxPasswordLength)
+	}
+	return nil
+}
+
+// Register создает пользователя с паролем
+func (s *Service) Register(ctx context.Context, user *models.User, password string) error {
+	if err := ValidatePassword(password); err != nil {
+		return err
+	}
+	return s.users.CreateWithPassword(ctx, user, s.hasher.Hash(password))
+}
+
+// Login возвращает пользователя, если пароль верен. Хэш проверяется на
+// каждом пути, включая неизвестный email, и все неудачи возвращают
+// ErrInvalidCredentials. Хэш со старыми параметрами пересчитывается.
+func (s *Service) Login(ctx context.Context, email, password string) (*models.User, error) {
+	if utf8.RuneCountInString(password) > MaxPasswordLength {
+		return nil, ErrInvalidCredentials
+	}
+
+	user, hash, err := s.users.GetCredentials(ctx, email)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if hash == "" {
+		s.hasher.Verify(password, s.dummyHash)
+		return nil, ErrInvalidCredentials
+	}
+
+	ok, rehash, err := s.hasher.Verify(password, hash)
+	if err != nil {
+		return nil, fmt.Errorf("verify password of user %d: %w", user.ID, err)
+	}
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	if rehash {
+		// Вход не должен срываться из-за пересчета: попробуем в следующий раз
+		if err := s.users.SetPasswordHash(ctx, user.ID, s.hasher.Hash(password)); err != nil {
+			log.Printf("failed to rehash password of user %d: %v", user.ID, err)
+		}
+	}
+	return user, nil
+}
+
+// ChangePassword заменяет пароль после проверки текущего. Пользователь без
+// пароля сменить его не может: текущий пароль нечем подтвердить.
+func (s *Service) ChangePassword(ctx context.Context, userID int, current, next string) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.Login(ctx, user.Email, current); err != nil {
+		return err
+	}
+	if err := ValidatePassword(next); err != nil {
+		return err
+	}
+	return s.users.SetPasswordHash(ctx, userID, s.hasher.Hash(next))
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"go-articles-app/repository/memory"
+	"strings"
+	"testing"
+)
+
+func newTestService(t *testing.T) (*Service, repository.UserStore) {
+	t.Helper()
+	users := memory.NewStore().Users()
+	return NewService(users, testParams), users
+}
+
+func mustRegister(t *testing.T, s *Service, email, password string) *models.User {
+	t.Helper()
+	user := &models.User{Email: email, Name: "Alice"}
+	if err := s.Register(context.Background(), user, password); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	return user
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestService(t)
+	user := mustRegister(t, s, "alice@example.com", "correct horse")
+
+	got, err := s.Login(ctx, "alice@example.com", "correct horse")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if got.ID != user.ID {
+		t.Fatalf("Login returned user %d, want %d", got.ID, user.ID)
+	}
+
+	err = s.Register(ctx, &models.User{Email: "alice@example.com", Name: "Alice"}, "another password")
+	if !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("Register duplicate: err = %v, want ErrAlreadyExists", err)
+	}
+	err = s.Register(ctx, &models.User{Email: "bob@example.com", Name: "Bob"}, "short")
+	if !errors.Is(err, repository.ErrInvalidInput) {
+		t.Fatalf("Register with short password: err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestLoginFailuresAreIndistinguishable(t *testing.T) {
+	ctx := context.Background()
+	s, users := newTestService(t)
+	mustRegister(t, s, "alice@example.com", "correct horse")
+	if err := users.Create(ctx, &models.User{Email: "nopass@example.com", Name: "No Password"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct{ name, email, password string }{
+		{"WrongPassword", "alice@example.com", "wrong horse"},
+		{"UnknownEmail", "nobody@example.com", "correct horse"},
+		{"NoPassword", "nopass@example.com", ""},
+		{"TooLong", "alice@example.com", strings.Repeat("x", MaxPasswordLength+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := s.Login(ctx, tt.email, tt.password)
+			if err != ErrInvalidCredentials || user != nil {
+				t.Fatalf("Login = %v, %v, want nil, ErrInvalidCredentials", user, err)
+			}
+		})
+	}
+}
+
+func TestLoginRehashesOutdatedHash(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewStore().Users()
+	weak := testParams
+	weak.Iterations = 1
+	weak.Memory = 512
+	user := &models.User{Email: "alice@example.com", Name: "Alice"}
+	if err := NewService(users, weak).Register(ctx, user, "correct horse"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewService(users, testParams).Login(ctx, "alice@example.com", "correct horse"); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	_, hash, err := users.GetCredentials(ctx, "alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,") {
+		t.Fatalf("hash after login = %q, want rehashed with m=1024", hash)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newTestService(t)
+	user := mustRegister(t, s, "alice@example.com", "correct horse")
+
+	if err := s.ChangePassword(ctx, user.ID, "wrong horse", "battery staple"); err != ErrInvalidCredentials {
+		t.Fatalf("ChangePassword with wrong current: err = %v, want ErrInvalidCredentials", err)
+	}
+	if err := s.ChangePassword(ctx, user.ID, "correct horse", "short"); !errors.Is(err, repository.ErrInvalidInput) {
+		t.Fatalf("ChangePassword to short: err = %v, want ErrInvalidInput", err)
+	}
+	if err := s.ChangePassword(ctx, user.ID, "correct horse", "battery staple"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+
+	if _, err := s.Login(ctx, "alice@example.com", "correct horse"); err != ErrInvalidCredentials {
+		t.Fatalf("Login with old password: err = %v, want ErrInvalidCredentials", err)
+	}
+	if _, err := s.Login(ctx, "alice@example.com", "battery staple"); err != nil {
+		t.Fatalf("Login with new password: %v", err)
+	}
+	if err := s.ChangePassword(ctx, 999, "correct horse", "battery staple"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("ChangePassword of missing user: err = %v, want ErrNotFound", err)
+	}
+}
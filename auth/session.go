@@ -0,0 +1,284 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrUnauthenticated — токен неизвестен, отозван, истек или подделан
+var ErrUnauthenticated = errors.New("invalid or expired token")
+
+const (
+	DefaultSessionTTL  = 30 * 24 * time.Hour
+	DefaultIdleTimeout = 7 * 24 * time.Hour
+	DefaultAccessTTL   = 15 * time.Minute
+
+	// touchInterval — как часто активность сессии записывается в базу
+	touchInterval = time.Minute
+	tokenBytes    = 32
+)
+
+// SessionConfig — сроки сессий и необязательная подпись JWT
+type SessionConfig struct {
+	// TTL — предельный срок жизни сессии, IdleTimeout — срок без активности
+	TTL         time.Duration
+	IdleTimeout time.Duration
+	// JWTKey включает выдачу access-токенов JWT (HS256). Без ключа клиенты
+	// работают только с токеном сессии.
+	JWTKey    []byte
+	AccessTTL time.Duration
+	Issuer    string
+}
+
+// Tokens — то, что получает клиент при входе и обновлении. Token — токен
+// сессии: им можно авторизовать запросы и получить новую пару через Refresh.
+// AccessToken — короткоживущий JWT, выдается при заданном JWTKey.
+type Tokens struct {
+	SessionID       int        `json:"session_id"`
+	Token           string     `json:"token"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	AccessToken     string     `json:"access_token,omitempty"`
+	AccessExpiresAt *time.Time `json:"access_expires_at,omitempty"`
+}
+
+// Principal — пользователь, от имени которого выполняется запрос
+type Principal struct {
+	User      *models.User
+	SessionID int
+}
+
+// Client — сведения о клиенте, сохраняемые в сессии
+type Client struct {
+	UserAgent string
+	IP        string
+}
+
+type accessClaims struct {
+	SessionID int `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// Sessions выдает, проверяет, обновляет и отзывает сессии
+type Sessions struct {
+	sessions repository.SessionStore
+	users    repository.UserStore
+	cfg      SessionConfig
+	now      func() time.Time
+}
+
+// NewSessions создает менеджер сессий; нулевые сроки заменяются значениями
+// по умолчанию
+func NewSessions(sessions repository.SessionStore, users repository.UserStore, cfg SessionConfig) *Sessions {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultSessionTTL
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = DefaultIdleTimeout
+	}
+	if cfg.AccessTTL <= 0 {
+		cfg.AccessTTL = DefaultAccessTTL
+	}
+	return &Sessions{sessions: sessions, users: users, cfg: cfg, now: time.Now}
+}
+
+// newToken возвращает случайный токен и его SHA-256. Токен несет 256 бит
+// энтропии, поэтому медленный хэш, как для паролей, не нужен.
+func newToken() (string, []byte) {
+	b := make([]byte, tokenBytes)
+	rand.Read(b)
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashToken(token)
+}
+
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+// Start открывает сессию для пользователя, прошедшего проверку пароля
+func (m *Sessions) Start(ctx context.Context, userID int, client Client) (*Tokens, error) {
+	now := m.now()
+	token, hash := newToken()
+	session := &models.Session{
+		UserID:     userID,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(m.cfg.TTL),
+	}
+	session.IdleExpiresAt = m.idleExpiry(session, now)
+	if err := m.sessions.Create(ctx, session, hash); err != nil {
+		return nil, err
+	}
+	return m.tokens(session, token, now)
+}
+
+// idleExpiry — истечение простоя, не позже предельного срока
+func (m *Sessions) idleExpiry(session *models.Session, now time.Time) time.Time {
+	idle := now.Add(m.cfg.IdleTimeout)
+	if idle.After(session.ExpiresAt) {
+		return session.ExpiresAt
+	}
+	return idle
+}
+
+func (m *Sessions) tokens(session *models.Session, token string, now time.Time) (*Tokens, error) {
+	t := &Tokens{SessionID: session.ID, Token: token, ExpiresAt: session.ExpiresAt}
+	if len(m.cfg.JWTKey) == 0 {
+		return t, nil
+	}
+
+	expires := now.Add(m.cfg.AccessTTL)
+	if expires.After(session.ExpiresAt) {
+		expires = session.ExpiresAt
+	}
+	claims := accessClaims{
+		SessionID: session.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.cfg.Issuer,
+			Subject:   strconv.Itoa(session.UserID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expires),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.cfg.JWTKey)
+	if err != nil {
+		return nil, fmt.Errorf("sign access token: %w", err)
+	}
+	t.AccessToken = signed
+	t.AccessExpiresAt = &expires
+	return t, nil
+}
+
+// Authenticate проверяет токен из заголовка Authorization: JWT, если он
+// включен и токен на него похож, иначе токен сессии. Пользователь из
+// корзины не проходит проверку.
+func (m *Sessions) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	if len(m.cfg.JWTKey) > 0 && strings.Count(token, ".") == 2 {
+		return m.authenticateJWT(ctx, token)
+	}
+
+	session, err := m.activeSession(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	now := m.now()
+	if now.Sub(session.LastSeenAt) >= touchInterval {
+		if err := m.sessions.Touch(ctx, session.ID, now, m.idleExpiry(session, now)); err != nil {
+			return nil, unauthenticated(err)
+		}
+	}
+	return m.principal(ctx, session.UserID, session.ID)
+}
+
+// activeSession находит действующую сессию по токену
+func (m *Sessions) activeSession(ctx context.Context, token string) (*models.Session, error) {
+	session, err := m.sessions.GetByToken(ctx, hashToken(token))
+	if err != nil {
+		return nil, unauthenticated(err)
+	}
+	if !session.Active(m.now()) {
+		return nil, ErrUnauthenticated
+	}
+	return session, nil
+}
+
+// authenticateJWT проверяет подпись и срок JWT без обращения к сессиям:
+// отзыв сессии вступает в силу для него по истечении AccessTTL
+func (m *Sessions) authenticateJWT(ctx context.Context, token string) (*Principal, error) {
+	var claims accessClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return m.cfg.JWTKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(m.cfg.Issuer),
+		jwt.WithTimeFunc(m.now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnauthenticated, err)
+	}
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid subject", ErrUnauthenticated)
+	}
+	return m.principal(ctx, userID, claims.SessionID)
+}
+
+func (m *Sessions) principal(ctx context.Context, userID, sessionID int) (*Principal, error) {
+	user, err := m.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, unauthenticated(err)
+	}
+	return &Principal{User: user, SessionID: sessionID}, nil
+}
+
+// Refresh меняет токен сессии на новый и выдает новый access-токен.
+// Повторное предъявление уже замененного токена означает, что его
+// перехватили: сессия отзывается целиком.
+func (m *Sessions) Refresh(ctx context.Context, token string) (*Tokens, error) {
+	hash := hashToken(token)
+	session, err := m.sessions.GetByToken(ctx, hash)
+	if errors.Is(err, repository.ErrNotFound) {
+		if _, revokeErr := m.sessions.RevokeByPreviousToken(ctx, hash); revokeErr != nil && !errors.Is(revokeErr, repository.ErrNotFound) {
+			return nil, revokeErr
+		}
+		return nil, ErrUnauthenticated
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := m.now()
+	if !session.Active(now) {
+		return nil, ErrUnauthenticated
+	}
+
+	next, nextHash := newToken()
+	err = m.sessions.Rotate(ctx, session.ID, hash, nextHash, now, m.idleExpiry(session, now))
+	if errors.Is(err, repository.ErrConflict) {
+		// Токен успели обновить параллельно: это тоже повторное предъявление
+		if _, err := m.sessions.RevokeByPreviousToken(ctx, hash); err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+		return nil, ErrUnauthenticated
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m.tokens(session, next, now)
+}
+
+// List возвращает действующие сессии пользователя
+func (m *Sessions) List(ctx context.Context, userID int) ([]*models.Session, error) {
+	return m.sessions.ListActive(ctx, userID, m.now())
+}
+
+// Revoke завершает одну сессию пользователя
+func (m *Sessions) Revoke(ctx context.Context, userID, sessionID int) error {
+	return m.sessions.Revoke(ctx, userID, sessionID)
+}
+
+// RevokeAll завершает все сессии пользователя, например после смены пароля
+func (m *Sessions) RevokeAll(ctx context.Context, userID int) (int, error) {
+	return m.sessions.RevokeAll(ctx, userID)
+}
+
+// unauthenticated прячет от клиента причину отказа, кроме сбоев хранилища
+func unauthenticated(err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrUnauthenticated
+	}
+	return err
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/repository/memory"
+	"testing"
+	"time"
+)
+
+// testClock — управляемые часы для проверки сроков
+type testClock struct{ t time.Time }
+
+func (c *testClock) now() time.Time          { return c.t }
+func (c *testClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestSessions(t *testing.T, cfg SessionConfig) (*Sessions, *memory.Store, *models.User, *testClock) {
+	t.Helper()
+	store := memory.NewStore()
+	user := &models.User{Email: "alice@example.com", Name: "Alice"}
+	if err := store.Users().Create(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	clock := &testClock{t: time.Now()}
+	m := NewSessions(store.Sessions(), store.Users(), cfg)
+	m.now = clock.now
+	return m, store, user, clock
+}
+
+func mustStart(t *testing.T, m *Sessions, userID int) *Tokens {
+	t.Helper()
+	tokens, err := m.Start(context.Background(), userID, Client{UserAgent: "test", IP: "127.0.0.1"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	return tokens
+}
+
+func wantUnauthenticated(t *testing.T, m *Sessions, token, what string) {
+	t.Helper()
+	if p, err := m.Authenticate(context.Background(), token); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("Authenticate %s = %v, %v, want ErrUnauthenticated", what, p, err)
+	}
+}
+
+func TestSessionAuthenticate(t *testing.T) {
+	m, _, user, _ := newTestSessions(t, SessionConfig{})
+	tokens := mustStart(t, m, user.ID)
+	if tokens.AccessToken != "" {
+		t.Fatal("access token issued without JWT key")
+	}
+
+	p, err := m.Authenticate(context.Background(), tokens.Token)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if p.User.ID != user.ID || p.SessionID != tokens.SessionID {
+		t.Fatalf("Authenticate = user %d session %d, want %d and %d", p.User.ID, p.SessionID, user.ID, tokens.SessionID)
+	}
+	wantUnauthenticated(t, m, "unknown", "with unknown token")
+}
+
+func TestSessionIdleAndAbsoluteExpiry(t *testing.T) {
+	ctx := context.Background()
+	m, _, user, clock := newTestSessions(t, SessionConfig{TTL: 10 * time.Hour, IdleTimeout: time.Hour})
+	tokens := mustStart(t, m, user.ID)
+
+	// Активность раз в 50 минут продлевает простой до предельного срока
+	for i := 0; i < 11; i++ {
+		clock.advance(50 * time.Minute)
+		if _, err := m.Authenticate(ctx, tokens.Token); err != nil {
+			t.Fatalf("Authenticate after %v: %v", time.Duration(i+1)*50*time.Minute, err)
+		}
+	}
+	clock.advance(2 * time.Hour)
+	wantUnauthenticated(t, m, tokens.Token, "after TTL")
+
+	idle := mustStart(t, m, user.ID)
+	clock.advance(time.Hour + time.Second)
+	wantUnauthenticated(t, m, idle.Token, "after idle timeout")
+}
+
+func TestSessionRefreshRotation(t *testing.T) {
+	ctx := context.Background()
+	m, _, user, _ := newTestSessions(t, SessionConfig{})
+	first := mustStart(t, m, user.ID)
+
+	second, err := m.Refresh(ctx, first.Token)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if second.Token == first.Token || second.SessionID != first.SessionID {
+		t.Fatalf("Refresh = %+v, want a new token for session %d", second, first.SessionID)
+	}
+	wantUnauthenticated(t, m, first.Token, "with rotated token")
+	if _, err := m.Authenticate(ctx, second.Token); err != nil {
+		t.Fatalf("Authenticate with new token: %v", err)
+	}
+
+	// Повтор старого токена отзывает сессию вместе с новым токеном
+	if _, err := m.Refresh(ctx, first.Token); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("Refresh with reused token: err = %v, want ErrUnauthenticated", err)
+	}
+	wantUnauthenticated(t, m, second.Token, "after token reuse")
+}
+
+func TestSessionRevoke(t *testing.T) {
+	ctx := context.Background()
+	m, store, user, _ := newTestSessions(t, SessionConfig{})
+	first := mustStart(t, m, user.ID)
+	second := mustStart(t, m, user.ID)
+
+	sessions, err := m.List(ctx, user.ID)
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("List = %d sessions, %v, want 2", len(sessions), err)
+	}
+	if err := m.Revoke(ctx, user.ID, first.SessionID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	wantUnauthenticated(t, m, first.Token, "with revoked session")
+	if _, err := m.Authenticate(ctx, second.Token); err != nil {
+		t.Fatalf("Authenticate with other session: %v", err)
+	}
+
+	if n, err := m.RevokeAll(ctx, user.ID); err != nil || n != 1 {
+		t.Fatalf("RevokeAll = %d, %v, want 1", n, err)
+	}
+	wantUnauthenticated(t, m, second.Token, "after RevokeAll")
+
+	third := mustStart(t, m, user.ID)
+	if err := store.Users().Delete(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	wantUnauthenticated(t, m, third.Token, "of trashed user")
+}
+
+func TestSessionJWT(t *testing.T) {
+	ctx := context.Background()
+	m, _, user, clock := newTestSessions(t, SessionConfig{JWTKey: []byte("test key"), AccessTTL: time.Minute, Issuer: "test"})
+	tokens := mustStart(t, m, user.ID)
+	if tokens.AccessToken == "" || tokens.AccessExpiresAt == nil {
+		t.Fatal("access token not issued")
+	}
+
+	p, err := m.Authenticate(ctx, tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("Authenticate JWT: %v", err)
+	}
+	if p.User.ID != user.ID || p.SessionID != tokens.SessionID {
+		t.Fatalf("Authenticate JWT = user %d session %d", p.User.ID, p.SessionID)
+	}
+
+	other := NewSessions(nil, nil, SessionConfig{JWTKey: []byte("other key"), Issuer: "test"})
+	other.now = clock.now
+	forged, err := other.tokens(&models.Session{ID: tokens.SessionID, UserID: user.ID, ExpiresAt: clock.t.Add(time.Hour)}, "", clock.t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantUnauthenticated(t, m, forged.AccessToken, "with JWT signed by another key")
+	wantUnauthenticated(t, m, "eyJhbGciOiJub25lIn0.eyJzdWIiOiIxIn0.", "with unsigned JWT")
+
+	clock.advance(2 * time.Minute)
+	wantUnauthenticated(t, m, tokens.AccessToken, "with expired JWT")
+	if _, err := m.Authenticate(ctx, tokens.Token); err != nil {
+		t.Fatalf("session token must still work: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go-articles-app/config"
+	"go-articles-app/repository"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// cli — окружение команд: настройки, база, репозитории и потоки ввода-вывода.
+// Команды пишут результат в stdout, а подсказки и ошибки — в stderr, чтобы
+// вывод -o json можно было передавать другим программам.
+type cli struct {
+	cfg      *config.Config
+	db       *sql.DB
+	users    repository.UserStore
+	articles repository.ArticleStore
+	stats    repository.StatsStore
+	bulk     repository.BulkStore
+
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func newCLI(cfg *config.Config, database *sql.DB, stdin io.Reader, stdout, stderr io.Writer) *cli {
+	return &cli{
+		cfg:      cfg,
+		db:       database,
+		users:    repository.NewUserRepository(database),
+		articles: repository.NewArticleRepository(database),
+		stats:    repository.NewStatsRepository(database),
+		bulk:     repository.NewBulkRepository(database),
+		stdin:    stdin,
+		stdout:   stdout,
+		stderr:   stderr,
+	}
+}
+
+// command — подкоманда CLI
+type command struct {
+	name    string
+	args    string
+	summary string
+	// noSchemaCheck — команда работает и на отставшей схеме (migrate)
+	noSchemaCheck bool
+	run           func(ctx context.Context, c *cli, args []string) error
+}
+
+func commands() []command {
+	return []command{
+		{name: "serve", args: "[flags]", summary: "run the HTTP API", run: runServe},
+		{name: "migrate", args: "up | down [N] | goto V | force V | status", summary: "apply or inspect schema migrations", noSchemaCheck: true, run: runMigrate},
+		{name: "seed", args: "[-users N] [-articles M] [-seed S]", summary: "generate fake users and articles", run: runSeed},
+		{name: "users", args: "list | create | delete", summary: "manage users", run: runUsers},
+		{name: "articles", args: "list | show | publish | delete", summary: "manage articles", run: runArticles},
+		{name: "stats", args: "[-o json] [-top N]", summary: "show content statistics", run: runStats},
+		{name: "demo", args: "--reset [-yes]", summary: "wipe all users and articles and run a walkthrough", run: runDemo},
+	}
+}
+
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: go-articles-app [config flags] <command> [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, cmd := range commands() {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", cmd.name, cmd.args, cmd.summary)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "run \"go-articles-app -h\" for config flags and \"<command> -h\" for command flags")
+}
+
+// subcommand выбирает вложенную команду вроде "users list"
+func subcommand(ctx context.Context, c *cli, group string, args []string, subs map[string]func(context.Context, *cli, []string) error) error {
+	usage := fmt.Errorf("usage: %s %s", group, strings.Join(slices.Sorted(maps.Keys(subs)), " | "))
+	if len(args) == 0 {
+		return usage
+	}
+	run, ok := subs[args[0]]
+	if !ok {
+		return usage
+	}
+	return run(ctx, c, args[1:])
+}
+
+// newFlagSet создает набор флагов команды, который возвращает ошибку вместо
+// выхода из процесса
+func (c *cli) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	return fs
+}
+
+// format — формат вывода: table для человека, json для скриптов
+type format string
+
+const (
+	formatTable format = "table"
+	formatJSON  format = "json"
+)
+
+func (f *format) String() string { return string(*f) }
+
+func (f *format) Set(s string) error {
+	switch format(s) {
+	case formatTable, formatJSON:
+		*f = format(s)
+		return nil
+	}
+	return errors.New("must be table or json")
+}
+
+func formatFlag(fs *flag.FlagSet) *format {
+	f := formatTable
+	fs.Var(&f, "o", "output format: table or json")
+	return &f
+}
+
+// print выводит v как JSON или вызывает table для табличного вида
+func (c *cli) print(f format, v any, table func(w io.Writer)) error {
+	if f == formatJSON {
+		enc := json.NewEncoder(c.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+// confirm спрашивает подтверждение: пользователь должен ввести word
+func (c *cli) confirm(prompt, word string) bool {
+	fmt.Fprintf(c.stderr, "%s\nType %q to continue: ", prompt, word)
+	line, err := bufio.NewReader(c.stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return false
+	}
+	return strings.TrimSpace(line) == word
+}
+
+// parseID разбирает единственный позиционный аргумент — положительный ID
+func parseID(fs *flag.FlagSet, what string) (int, error) {
+	if fs.NArg() != 1 {
+		return 0, fmt.Errorf("usage: %s [flags] ID", fs.Name())
+	}
+	id, err := strconv.Atoi(fs.Arg(0))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s ID %q", what, fs.Arg(0))
+	}
+	return id, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return formatTime(*t)
+}
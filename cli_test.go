@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"go-articles-app/config"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"go-articles-app/repository/memory"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testCLI — окружение команд поверх in-memory хранилища
+type testCLI struct {
+	*cli
+	stdin          *strings.Reader
+	stdout, stderr *bytes.Buffer
+}
+
+func newTestCLI(t *testing.T) *testCLI {
+	t.Helper()
+	store := memory.NewStore()
+	tc := &testCLI{stdin: strings.NewReader(""), stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}}
+	cfg := config.Defaults()
+	cfg.DB.DBName = "go_article_app"
+	tc.cli = &cli{
+		cfg:      &cfg,
+		users:    store.Users(),
+		articles: store.Articles(),
+		stats:    store.Stats(),
+		bulk:     store.Bulk(),
+		stdin:    tc.stdin,
+		stdout:   tc.stdout,
+		stderr:   tc.stderr,
+	}
+	return tc
+}
+
+// exec запускает команду и возвращает ее stdout
+func (tc *testCLI) exec(t *testing.T, name string, args ...string) string {
+	t.Helper()
+	tc.stdout.Reset()
+	cmd, ok := findCommand(name)
+	if !ok {
+		t.Fatalf("unknown command %q", name)
+	}
+	if err := cmd.run(context.Background(), tc.cli, args); err != nil {
+		t.Fatalf("%s %s: %v", name, strings.Join(args, " "), err)
+	}
+	return tc.stdout.String()
+}
+
+func TestUsersCommands(t *testing.T) {
+	tc := newTestCLI(t)
+
+	var created models.User
+	out := tc.exec(t, "users", "create", "-o", "json", "-email", "ann@example.com", "-name", "Ann", "-role", "editor")
+	if err := json.Unmarshal([]byte(out), &created); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if created.ID == 0 || created.Role != models.RoleEditor {
+		t.Fatalf("created = %+v", created)
+	}
+
+	out = tc.exec(t, "users", "list")
+	if !strings.Contains(out, "ann@example.com") || !strings.HasPrefix(out, "ID") {
+		t.Fatalf("users list =\n%s", out)
+	}
+
+	tc.exec(t, "users", "delete", "1")
+	var page repository.Page[*models.User]
+	if err := json.Unmarshal([]byte(tc.exec(t, "users", "list", "-o", "json")), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 0 {
+		t.Fatalf("users after delete = %+v", page.Items)
+	}
+	if out := tc.exec(t, "users", "list", "-trash"); !strings.Contains(out, "ann@example.com") {
+		t.Fatalf("users list -trash =\n%s", out)
+	}
+
+	cmd, _ := findCommand("users")
+	for _, args := range [][]string{
+		{"create", "-email", "not-an-email", "-name", "X"},
+		{"create", "-email", "x@example.com", "-name", "X", "-role", "owner"},
+		{"delete", "abc"},
+		{"rename"},
+		{"list", "-o", "yaml"},
+	} {
+		if err := cmd.run(context.Background(), tc.cli, args); err == nil {
+			t.Errorf("users %v succeeded, want an error", args)
+		}
+	}
+}
+
+func TestArticlesCommands(t *testing.T) {
+	tc := newTestCLI(t)
+	articles, err := seedSample(context.Background(), tc.cli)
+	if err != nil {
+		t.Fatalf("seedSample: %v", err)
+	}
+
+	var list repository.ArticleList
+	if err := json.Unmarshal([]byte(tc.exec(t, "articles", "list", "-o", "json", "-status", "published")), &list); err != nil {
+		t.Fatal(err)
+	}
+	if list.Total != 3 {
+		t.Fatalf("published total = %d, want 3", list.Total)
+	}
+
+	draft := articles[1]
+	out := tc.exec(t, "articles", "show", draft.Slug)
+	if !strings.Contains(out, draft.Title) || !strings.Contains(out, "Alice <alice@example.com>") || !strings.HasSuffix(out, draft.Content+"\n") {
+		t.Fatalf("articles show =\n%s", out)
+	}
+
+	tc.exec(t, "articles", "publish", "2")
+	var view articleView
+	if err := json.Unmarshal([]byte(tc.exec(t, "articles", "show", "-o", "json", "2")), &view); err != nil {
+		t.Fatal(err)
+	}
+	if view.Status != models.ArticlePublished || view.AuthorName != "Alice" {
+		t.Fatalf("article after publish = %+v", view)
+	}
+
+	tc.exec(t, "articles", "delete", "2")
+	cmd, _ := findCommand("articles")
+	if err := cmd.run(context.Background(), tc.cli, []string{"show", "2"}); err == nil {
+		t.Fatal("show of a deleted article succeeded")
+	}
+}
+
+func TestStatsCommand(t *testing.T) {
+	tc := newTestCLI(t)
+	if _, err := seedSample(context.Background(), tc.cli); err != nil {
+		t.Fatalf("seedSample: %v", err)
+	}
+
+	var stats repository.Stats
+	if err := json.Unmarshal([]byte(tc.exec(t, "stats", "-o", "json", "-top", "2")), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Users != 3 || stats.Articles != 5 || len(stats.TopAuthors) != 2 {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	out := tc.exec(t, "stats")
+	for _, want := range []string{"draft 2, published 3", "TOP AUTHORS"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("stats output does not contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestDemoRequiresConfirmation(t *testing.T) {
+	tc := newTestCLI(t)
+	cmd, _ := findCommand("demo")
+
+	if err := cmd.run(context.Background(), tc.cli, nil); err == nil || !strings.Contains(err.Error(), "--reset") {
+		t.Fatalf("demo without --reset = %v", err)
+	}
+
+	tc.stdin.Reset("yes\n")
+	if err := cmd.run(context.Background(), tc.cli, []string{"--reset"}); err == nil || !strings.Contains(err.Error(), "aborted") {
+		t.Fatalf("demo with wrong confirmation = %v", err)
+	}
+	if !strings.Contains(tc.stderr.String(), `Type "go_article_app"`) {
+		t.Fatalf("prompt = %q", tc.stderr.String())
+	}
+}
+
+func TestSeedCommand(t *testing.T) {
+	args := []string{"-users", "30", "-articles", "250", "-batch", "40", "-seed", "5", "-until", "2025-06-01"}
+	stats := func(tc *testCLI) repository.Stats {
+		var stats repository.Stats
+		if err := json.Unmarshal([]byte(tc.exec(t, "stats", "-o", "json")), &stats); err != nil {
+			t.Fatal(err)
+		}
+		return stats
+	}
+
+	first := newTestCLI(t)
+	if out := first.exec(t, "seed", args...); !strings.HasPrefix(out, "Created 30 users and 250 articles") {
+		t.Fatalf("seed output = %q", out)
+	}
+	if !strings.Contains(first.stderr.String(), "articles: 250/250") {
+		t.Fatalf("seed progress = %q", first.stderr.String())
+	}
+	got := stats(first)
+	if got.Users != 30 || got.Articles != 250 || got.Views == 0 {
+		t.Fatalf("stats after seed = %+v", got)
+	}
+
+	// Тот же набор флагов на пустом хранилище дает те же данные
+	second := newTestCLI(t)
+	second.exec(t, "seed", args...)
+	if again := stats(second); !reflect.DeepEqual(again, got) {
+		t.Fatalf("second run stats = %+v, want %+v", again, got)
+	}
+
+	// Повторный запуск с тем же зерном упирается в занятые email
+	cmd, _ := findCommand("seed")
+	if err := cmd.run(context.Background(), first.cli, args); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("repeated seed: err = %v, want ErrAlreadyExists", err)
+	}
+	for _, bad := range [][]string{
+		{"-articles", "5", "-users", "0"},
+		{"-batch", "0"},
+		{"-until", "yesterday"},
+	} {
+		if err := cmd.run(context.Background(), first.cli, bad); err == nil {
+			t.Errorf("seed %v succeeded, want an error", bad)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig — формат файла настроек. Ключи одинаковы для YAML и TOML:
+//
+//	database:
+//	  url: postgres://app@db:5432/go_article_app?sslmode=verify-full
+//	  password_file: /run/secrets/db_password
+//	  sslrootcert: certs/ca.pem
+//	  statement_timeout: 30s
+//	  max_open_conns: 50
+//	server:
+//	  addr: ":8080"
+//	  jwt_secret_file: /run/secrets/jwt_secret
+type fileConfig struct {
+	Database struct {
+		URL          string `yaml:"url" toml:"url"`
+		URLFile      string `yaml:"url_file" toml:"url_file"`
+		Host         string `yaml:"host" toml:"host"`
+		Port         int    `yaml:"port" toml:"port"`
+		User         string `yaml:"user" toml:"user"`
+		Password     string `yaml:"password" toml:"password"`
+		PasswordFile string `yaml:"password_file" toml:"password_file"`
+		Name         string `yaml:"name" toml:"name"`
+		SSLMode      string `yaml:"sslmode" toml:"sslmode"`
+
+		SSLRootCert      string `yaml:"sslrootcert" toml:"sslrootcert"`
+		SSLCert          string `yaml:"sslcert" toml:"sslcert"`
+		SSLKey           string `yaml:"sslkey" toml:"sslkey"`
+		ConnectTimeout   string `yaml:"connect_timeout" toml:"connect_timeout"`
+		ApplicationName  string `yaml:"application_name" toml:"application_name"`
+		SearchPath       string `yaml:"search_path" toml:"search_path"`
+		StatementTimeout string `yaml:"statement_timeout" toml:"statement_timeout"`
+		LockTimeout      string `yaml:"lock_timeout" toml:"lock_timeout"`
+		MaxOpenConns     int    `yaml:"max_open_conns" toml:"max_open_conns"`
+		MaxIdleConns     int    `yaml:"max_idle_conns" toml:"max_idle_conns"`
+		ConnMaxLifetime  string `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+		ConnMaxIdleTime  string `yaml:"conn_max_idle_time" toml:"conn_max_idle_time"`
+	} `yaml:"database" toml:"database"`
+	Server struct {
+		Addr          string `yaml:"addr" toml:"addr"`
+		SiteURL       string `yaml:"site_url" toml:"site_url"`
+		SiteTitle     string `yaml:"site_title" toml:"site_title"`
+		JWTSecret     string `yaml:"jwt_secret" toml:"jwt_secret"`
+		JWTSecretFile string `yaml:"jwt_secret_file" toml:"jwt_secret_file"`
+	} `yaml:"server" toml:"server"`
+}
+
+// fileSource читает файл настроек. Формат определяется расширением;
+// неизвестные ключи — ошибка, чтобы опечатка не превращалась в молча
+// проигнорированную настройку. Относительные пути *_file и сертификатов
+// отсчитываются от каталога файла.
+func fileSource(path string) (source, error) {
+	src := source{name: path, values: make(map[field]string), keyOf: func(k key) string { return k.file }}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return src, fmt.Errorf("config: %w", err)
+	}
+
+	var fc fileConfig
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&fc); err != nil && !errors.Is(err, io.EOF) {
+			return src, fmt.Errorf("config: %s: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), &fc)
+		if err != nil {
+			return src, fmt.Errorf("config: %s: %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return src, fmt.Errorf("config: %s: unknown key %q", path, undecoded[0].String())
+		}
+	default:
+		return src, fmt.Errorf("config: %s: unsupported format %q, use .yaml, .yml or .toml", path, ext)
+	}
+
+	dir := filepath.Dir(path)
+	d, s := fc.Database, fc.Server
+	url, err := secret(d.URL, d.URLFile, "database.url", "database.url_file", dir)
+	if err != nil {
+		return src, err
+	}
+	password, err := secret(d.Password, d.PasswordFile, "database.password", "database.password_file", dir)
+	if err != nil {
+		return src, err
+	}
+	jwtSecret, err := secret(s.JWTSecret, s.JWTSecretFile, "server.jwt_secret", "server.jwt_secret_file", dir)
+	if err != nil {
+		return src, err
+	}
+	itoa := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+	relative := func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return filepath.Join(dir, path)
+	}
+
+	for f, value := range map[field]string{
+		fieldURL:      url,
+		fieldHost:     d.Host,
+		fieldPort:     itoa(d.Port),
+		fieldUser:     d.User,
+		fieldPassword: password,
+		fieldName:     d.Name,
+		fieldSSLMode:  d.SSLMode,
+
+		fieldSSLRootCert:      relative(d.SSLRootCert),
+		fieldSSLCert:          relative(d.SSLCert),
+		fieldSSLKey:           relative(d.SSLKey),
+		fieldConnectTimeout:   d.ConnectTimeout,
+		fieldApplicationName:  d.ApplicationName,
+		fieldSearchPath:       d.SearchPath,
+		fieldStatementTimeout: d.StatementTimeout,
+		fieldLockTimeout:      d.LockTimeout,
+		fieldMaxOpenConns:     itoa(d.MaxOpenConns),
+		fieldMaxIdleConns:     itoa(d.MaxIdleConns),
+		fieldConnMaxLifetime:  d.ConnMaxLifetime,
+		fieldConnMaxIdleTime:  d.ConnMaxIdleTime,
+
+		fieldAddr:      s.Addr,
+		fieldSiteURL:   s.SiteURL,
+		fieldSiteTitle: s.SiteTitle,
+		fieldJWTSecret: jwtSecret,
+	} {
+		if value != "" {
+			src.values[f] = value
+		}
+	}
+	return src, nil
+}
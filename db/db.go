@@ -3,11 +3,22 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
 )
 
+// Значения пула по умолчанию, если в Config они не заданы
+const (
+	DefaultMaxOpenConns    = 25
+	DefaultMaxIdleConns    = 25
+	DefaultConnMaxLifetime = 5 * time.Minute
+	DefaultConnMaxIdleTime = 5 * time.Minute
+)
+
 type Config struct {
 	Host     string
 	Port     int
@@ -15,25 +26,118 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	// Пути к корневому сертификату CA и клиентским сертификату и ключу
+	SSLRootCert string
+	SSLCert     string
+	SSLKey      string
+
+	// ConnectTimeout ограничивает установку соединения; округляется вверх до
+	// секунд. 0 — ждать без ограничения.
+	ConnectTimeout  time.Duration
+	ApplicationName string
+	// SearchPath — схемы через запятую, как в SET search_path
+	SearchPath string
+	// StatementTimeout и LockTimeout передаются серверу при подключении
+	// (statement_timeout, lock_timeout); 0 — настройка сервера.
+	StatementTimeout time.Duration
+	LockTimeout      time.Duration
+
+	// Настройки пула database/sql; 0 — значение Default*. Отрицательные
+	// значения передаются как есть: для MaxOpenConns и времен это отсутствие
+	// ограничения, для MaxIdleConns — пул без простаивающих соединений.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
 }
 
-func NewConnection(cfg Config) (*sql.DB, error) {
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode)
+// DSN собирает строку подключения в формате key=value. Значения берутся в
+// кавычки с экранированием, поэтому пароль с пробелами, кавычками или
+// обратной косой чертой не ломает строку. Пустые настройки не передаются.
+func (c Config) DSN() string {
+	params := map[string]string{
+		"host":             c.Host,
+		"user":             c.User,
+		"password":         c.Password,
+		"dbname":           c.DBName,
+		"sslmode":          c.SSLMode,
+		"sslrootcert":      c.SSLRootCert,
+		"sslcert":          c.SSLCert,
+		"sslkey":           c.SSLKey,
+		"application_name": c.ApplicationName,
+		"search_path":      c.SearchPath,
+	}
+	if c.Port != 0 {
+		params["port"] = strconv.Itoa(c.Port)
+	}
+	if c.ConnectTimeout > 0 {
+		seconds := (c.ConnectTimeout + time.Second - 1) / time.Second
+		params["connect_timeout"] = strconv.FormatInt(int64(seconds), 10)
+	}
+	if c.StatementTimeout > 0 {
+		params["statement_timeout"] = strconv.FormatInt(c.StatementTimeout.Milliseconds(), 10)
+	}
+	if c.LockTimeout > 0 {
+		params["lock_timeout"] = strconv.FormatInt(c.LockTimeout.Milliseconds(), 10)
+	}
+
+	keys := make([]string, 0, len(params))
+	for k, v := range params {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
 
-	db, err := sql.Open("postgres", dsn)
+	var b strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(quote(params[k]))
+	}
+	return b.String()
+}
+
+// quote берет значение в одинарные кавычки, экранируя ' и \ (формат libpq)
+func quote(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
+
+// applyPool настраивает пул соединений по cfg
+func applyPool(db *sql.DB, cfg Config) {
+	// MaxIdleConns выставляется вторым: database/sql урезает его до MaxOpenConns
+	db.SetMaxOpenConns(orDefault(cfg.MaxOpenConns, DefaultMaxOpenConns))
+	db.SetMaxIdleConns(orDefault(cfg.MaxIdleConns, DefaultMaxIdleConns))
+	db.SetConnMaxLifetime(orDefault(cfg.ConnMaxLifetime, DefaultConnMaxLifetime))
+	db.SetConnMaxIdleTime(orDefault(cfg.ConnMaxIdleTime, DefaultConnMaxIdleTime))
+}
+
+// orDefault заменяет 0 на def
+func orDefault[T int | time.Duration](v, def T) T {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+func NewConnection(cfg Config) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.DSN())
 	if err != nil {
 		return nil, fmt.Errorf("failed to open db: %w", err)
 	}
+	applyPool(db, cfg)
 
 	// Проверяем подключение
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping db: %w", err)
 	}
 
-	db.SetMaxOpenConns(25)
-	db.SetConnMaxIdleTime(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
-
 	return db, nil
 }
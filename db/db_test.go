@@ -0,0 +1,54 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestDSNQuotesValues(t *testing.T) {
+	cfg := Config{
+		Host:             "db.internal",
+		Port:             5432,
+		User:             "app",
+		Password:         `p a's\s`,
+		DBName:           "blog",
+		SSLMode:          "verify-full",
+		SSLRootCert:      "/etc/ssl/ca.pem",
+		ConnectTimeout:   1500 * time.Millisecond,
+		SearchPath:       "blog, public",
+		StatementTimeout: 30 * time.Second,
+	}
+	want := `connect_timeout='2' dbname='blog' host='db.internal' password='p a\'s\\s' port='5432' ` +
+		`search_path='blog, public' sslmode='verify-full' sslrootcert='/etc/ssl/ca.pem' statement_timeout='30000' user='app'`
+	if got := cfg.DSN(); got != want {
+		t.Fatalf("DSN =\n%s\nwant\n%s", got, want)
+	}
+	// Драйвер должен разобрать строку, не спотыкаясь о кавычки и пробелы
+	if _, err := pq.NewConnector(cfg.DSN()); err != nil {
+		t.Fatalf("pq.NewConnector: %v", err)
+	}
+}
+
+func TestApplyPool(t *testing.T) {
+	db, err := sql.Open("postgres", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	applyPool(db, Config{})
+	if got := db.Stats().MaxOpenConnections; got != DefaultMaxOpenConns {
+		t.Fatalf("default MaxOpenConnections = %d, want %d", got, DefaultMaxOpenConns)
+	}
+	applyPool(db, Config{MaxOpenConns: 3})
+	if got := db.Stats().MaxOpenConnections; got != 3 {
+		t.Fatalf("MaxOpenConnections = %d, want 3", got)
+	}
+	applyPool(db, Config{MaxOpenConns: -1})
+	if got := db.Stats().MaxOpenConnections; got != 0 {
+		t.Fatalf("MaxOpenConnections = %d, want 0 (unlimited)", got)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+)
+
+// runDemo: demo --reset [-yes] — удаляет всех пользователей и статьи, заново
+// заполняет базу примером данных и показывает основные операции
+// репозиториев. Без --reset не запускается, а перед удалением спрашивает
+// подтверждение: нужно ввести имя базы.
+func runDemo(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlagSet("demo")
+	reset := fs.Bool("reset", false, "required: confirm that all users and articles will be deleted")
+	yes := fs.Bool("yes", false, "do not ask for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !*reset {
+		return errors.New("demo deletes all users and articles; run \"demo --reset\" to confirm")
+	}
+	dbName := c.cfg.DB.DBName
+	if !*yes && !c.confirm(fmt.Sprintf("demo will delete ALL users and articles in database %q.", dbName), dbName) {
+		return errors.New("demo: aborted")
+	}
+
+	// Комментарии, ревизии, сессии и связи с тегами удаляются каскадно
+	for _, table := range []string{"articles", "users"} {
+		if _, err := c.db.ExecContext(ctx, "DELETE FROM "+table); err != nil {
+			return fmt.Errorf("reset %s: %w", table, err)
+		}
+	}
+	return demoWalkthrough(ctx, c)
+}
+
+func demoWalkthrough(ctx context.Context, c *cli) error {
+	out := c.stdout
+
+	// 1. Пример данных
+	fmt.Fprintln(out, "\n📰 Creating sample articles with CreateArticleWithAuthor...")
+	articles, err := seedSample(ctx, c)
+	if err != nil {
+		return err
+	}
+	for i, a := range articles {
+		s := sampleArticles[i]
+		fmt.Fprintf(out, "✅ Created article %q by %s", a.Title, s.author)
+		if s.publish {
+			fmt.Fprint(out, " (published)")
+		}
+		fmt.Fprintln(out)
+	}
+	intro, basics := articles[0], articles[1]
+
+	alice, err := c.users.GetByEmail(ctx, "alice@example.com")
+	if err != nil {
+		return err
+	}
+	bob, err := c.users.GetByEmail(ctx, "bob@example.com")
+	if err != nil {
+		return err
+	}
+
+	// 2. Увеличение просмотров
+	fmt.Fprintln(out, "\n👁️  Incrementing views...")
+	for range 5 {
+		if err := c.articles.IncrementViews(ctx, intro.ID); err != nil {
+			return fmt.Errorf("increment views: %w", err)
+		}
+	}
+	updated, err := c.articles.GetByID(ctx, intro.ID)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "✅ %q views: 0 → %d\n", intro.Title, updated.Views)
+
+	// 3. Статистика
+	if err := printDemoStats(ctx, c, "📊 Statistics:"); err != nil {
+		return err
+	}
+
+	// 4. Статьи Alice
+	fmt.Fprintln(out, "\n📚 Articles by Alice:")
+	aliceArticles, err := c.articles.GetByAuthorID(ctx, alice.ID)
+	if err != nil {
+		return err
+	}
+	for i, a := range aliceArticles {
+		fmt.Fprintf(out, "  %d. %q (%s, %d views)\n", i+1, a.Title, a.Status, a.Views)
+	}
+
+	// 5. Все опубликованные статьи с авторами
+	fmt.Fprintln(out, "\n🌐 All published articles:")
+	published, err := c.articles.GetPublished(ctx)
+	if err != nil {
+		return err
+	}
+	for i, a := range published {
+		withAuthor, err := c.articles.GetArticleWithAuthor(ctx, a.ID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "  %d. %q by %s (%d views)\n", i+1, a.Title, withAuthor.AuthorName, a.Views)
+	}
+
+	// 6. Обновление статьи
+	fmt.Fprintln(out, "\n✏️  Updating article...")
+	oldTitle := basics.Title
+	basics.Title = "Advanced PostgreSQL"
+	basics.Content = "Advanced PostgreSQL features and optimization..."
+	if err := c.articles.Update(ctx, basics); err != nil {
+		return fmt.Errorf("update article: %w", err)
+	}
+	fmt.Fprintf(out, "✅ Updated: %q → %q\n", oldTitle, basics.Title)
+
+	// 7. Удаление пользователя Bob
+	fmt.Fprintln(out, "\n🗑️  Deleting user Bob...")
+	bobArticles, err := c.articles.GetByAuthorID(ctx, bob.ID)
+	if err != nil {
+		return err
+	}
+	if err := c.users.Delete(ctx, bob.ID); err != nil {
+		return fmt.Errorf("delete Bob: %w", err)
+	}
+	fmt.Fprintf(out, "✅ Moved user Bob to trash (%d articles trashed along with the account)\n", len(bobArticles))
+
+	if err := printDemoStats(ctx, c, "📊 Final statistics:"); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "\n🎉 All operations completed successfully!")
+	return nil
+}
+
+func printDemoStats(ctx context.Context, c *cli, title string) error {
+	stats, err := c.stats.Get(ctx, repository.DefaultTopAuthors)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "\n%s\n", title)
+	fmt.Fprintf(c.stdout, "  - Total users: %d\n", stats.Users)
+	fmt.Fprintf(c.stdout, "  - Total articles: %d\n", stats.Articles)
+	fmt.Fprintf(c.stdout, "  - Published articles: %d\n", stats.ArticlesByStatus[models.ArticlePublished])
+	fmt.Fprintf(c.stdout, "  - In trash: %d users, %d articles\n", stats.TrashedUsers, stats.TrashedArticles)
+	return nil
+}
+
+// sampleArticles — небольшой набор данных для demo. Авторы
+// создаются по email при первой статье.
+var sampleArticles = []struct {
+	author, email, title, content string
+	publish                       bool
+}{
+	{"Alice", "alice@example.com", "Introduction to Go", "Go is a statically typed, compiled language...", true},
+	{"Alice", "alice@example.com", "PostgreSQL Basics", "PostgreSQL is a powerful database...", false},
+	{"Bob", "bob@example.com", "Web Development in Go", "Building web applications in Go...", true},
+	{"Bob", "bob@example.com", "Docker for Beginners", "Docker simplifies deployment...", true},
+	{"Diana", "diana@example.com", "Microservices Architecture", "Microservices pattern explained...", false},
+}
+
+// seedSample создает статьи из sampleArticles и публикует отмеченные
+func seedSample(ctx context.Context, c *cli) ([]*models.Article, error) {
+	articles := make([]*models.Article, 0, len(sampleArticles))
+	for _, s := range sampleArticles {
+		_, article, err := c.articles.CreateArticleWithAuthor(ctx, s.author, s.email, s.title, s.content)
+		if err != nil {
+			return nil, fmt.Errorf("create %q: %w", s.title, err)
+		}
+		if s.publish {
+			if err := c.articles.Publish(ctx, article.ID); err != nil {
+				return nil, fmt.Errorf("publish %q: %w", s.title, err)
+			}
+			article.Status = models.ArticlePublished
+		}
+		articles = append(articles, article)
+	}
+	return articles, nil
+}
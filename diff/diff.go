@@ -0,0 +1,200 @@
+// Package diff строит построчную разницу между двумя текстами алгоритмом
+// Майерса: результат — кратчайший набор удалений и вставок. Слишком большие
+// различия (см. MaxLines) показываются заменой целиком.
+package diff
+
+import "strings"
+
+// MaxLines ограничивает число различающихся строк, которые сравниваются
+// алгоритмом Майерса: его трасса занимает память, квадратичную по числу
+// правок. Общие начало и конец текстов в лимит не входят; если середина
+// длиннее, она целиком показывается как удаление старых строк и вставка новых.
+const MaxLines = 1000
+
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// Line — строка результата. Для Equal и Delete заполнен OldLine, для
+// Equal и Insert — NewLine; номера строк начинаются с 1.
+type Line struct {
+	Op      Op     `json:"op"`
+	Text    string `json:"text"`
+	OldLine int    `json:"old_line,omitempty"`
+	NewLine int    `json:"new_line,omitempty"`
+}
+
+// Lines сравнивает тексты построчно. Перевод строки в конце текста не
+// порождает отдельной пустой строки, "\r\n" считается обычным переводом строки.
+func Lines(oldText, newText string) []Line {
+	a, b := split(oldText), split(newText)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(a)+len(b)-prefix-suffix)
+	for i := 0; i < prefix; i++ {
+		lines = append(lines, Line{Op: Equal, Text: a[i], OldLine: i + 1, NewLine: i + 1})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	var middle []Line
+	if len(midA)+len(midB) > MaxLines {
+		middle = replace(midA, midB)
+	} else {
+		middle = backtrack(shortestEdit(midA, midB), midA, midB)
+	}
+	for _, l := range middle {
+		if l.OldLine != 0 {
+			l.OldLine += prefix
+		}
+		if l.NewLine != 0 {
+			l.NewLine += prefix
+		}
+		lines = append(lines, l)
+	}
+
+	for i := 0; i < suffix; i++ {
+		x, y := len(a)-suffix+i, len(b)-suffix+i
+		lines = append(lines, Line{Op: Equal, Text: a[x], OldLine: x + 1, NewLine: y + 1})
+	}
+	return lines
+}
+
+// Changed сообщает, есть ли в результате удаления или вставки
+func Changed(lines []Line) bool {
+	for _, l := range lines {
+		if l.Op != Equal {
+			return true
+		}
+	}
+	return false
+}
+
+// Format выводит результат в виде, привычном по unified diff: "+", "-" или
+// пробел перед каждой строкой
+func Format(lines []Line) string {
+	var sb strings.Builder
+	for _, l := range lines {
+		switch l.Op {
+		case Insert:
+			sb.WriteString("+")
+		case Delete:
+			sb.WriteString("-")
+		default:
+			sb.WriteString(" ")
+		}
+		sb.WriteString(l.Text)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func split(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	return strings.Split(text, "\n")
+}
+
+// shortestEdit проходит диагонали k = x - y, пока не достигнет конца обоих
+// текстов. trace[d] хранит самые дальние x для диагоналей -d..d после шага d.
+func shortestEdit(a, b []string) [][]int {
+	n, m := len(a), len(b)
+	maxD := n + m
+	// v[k+maxD+1] — самый дальний x на диагонали k; запас в одну ячейку для k = -d-1
+	v := make([]int, 2*maxD+3)
+	offset := maxD + 1
+
+	var trace [][]int
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // шаг вниз: вставка
+			} else {
+				x = v[offset+k-1] + 1 // шаг вправо: удаление
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+		}
+
+		snapshot := make([]int, 2*d+1)
+		copy(snapshot, v[offset-d:offset+d+1])
+		trace = append(trace, snapshot)
+
+		if v[offset+n-m] >= n && n-m >= -d && n-m <= d {
+			break
+		}
+	}
+	return trace
+}
+
+// replace показывает замену целиком: сначала удаления, затем вставки
+func replace(a, b []string) []Line {
+	lines := make([]Line, 0, len(a)+len(b))
+	for i, text := range a {
+		lines = append(lines, Line{Op: Delete, Text: text, OldLine: i + 1})
+	}
+	for i, text := range b {
+		lines = append(lines, Line{Op: Insert, Text: text, NewLine: i + 1})
+	}
+	return lines
+}
+
+// backtrack восстанавливает путь от конца текстов к началу по сохраненным шагам
+func backtrack(trace [][]int, a, b []string) []Line {
+	at := func(d, k int) int { return trace[d][k+d] }
+
+	x, y := len(a), len(b)
+	var reversed []Line
+	for d := len(trace) - 1; d >= 0; d-- {
+		k := x - y
+
+		var prevX, prevY int
+		if d > 0 {
+			prevK := k - 1
+			if k == -d || (k != d && at(d-1, k-1) < at(d-1, k+1)) {
+				prevK = k + 1
+			}
+			prevX = at(d-1, prevK)
+			prevY = prevX - prevK
+		}
+
+		for x > prevX && y > prevY {
+			reversed = append(reversed, Line{Op: Equal, Text: a[x-1], OldLine: x, NewLine: y})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				reversed = append(reversed, Line{Op: Insert, Text: b[y-1], NewLine: y})
+			} else {
+				reversed = append(reversed, Line{Op: Delete, Text: a[x-1], OldLine: x})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	lines := make([]Line, len(reversed))
+	for i, l := range reversed {
+		lines[len(reversed)-1-i] = l
+	}
+	return lines
+}
@@ -0,0 +1,148 @@
+package diff
+
+import (
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{"BothEmpty", "", "", ""},
+		{"Identical", "a\nb\n", "a\nb", " a\n b\n"},
+		{"FromEmpty", "", "a\nb", "+a\n+b\n"},
+		{"ToEmpty", "a\nb", "", "-a\n-b\n"},
+		{"ReplaceMiddle", "a\nb\nc", "a\nx\nc", " a\n-b\n+x\n c\n"},
+		{"InsertAndDelete", "a\nb\nc\nd", "b\nc\ne\nd", "-a\n b\n c\n+e\n d\n"},
+		{"CRLF", "a\r\nb\r\n", "a\nb\n", " a\n b\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(Lines(tt.old, tt.new)); got != tt.want {
+				t.Fatalf("diff:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinesNumbers(t *testing.T) {
+	lines := Lines("a\nb\nc", "a\nc\nd")
+	want := []Line{
+		{Op: Equal, Text: "a", OldLine: 1, NewLine: 1},
+		{Op: Delete, Text: "b", OldLine: 2},
+		{Op: Equal, Text: "c", OldLine: 3, NewLine: 2},
+		{Op: Insert, Text: "d", NewLine: 3},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %+v, want %+v", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Fatalf("line %d = %+v, want %+v", i, lines[i], want[i])
+		}
+	}
+	if !Changed(lines) || Changed(Lines("a", "a")) {
+		t.Fatal("Changed reports wrong result")
+	}
+}
+
+func TestLinesLimit(t *testing.T) {
+	numbered := func(prefix string, n int) []string {
+		lines := make([]string, n)
+		for i := range lines {
+			lines[i] = prefix + strconv.Itoa(i)
+		}
+		return lines
+	}
+	head, tail := numbered("head", 3), numbered("tail", 2)
+	oldMid, newMid := numbered("old", MaxLines), numbered("new", 1)
+	text := func(mid []string) string {
+		return strings.Join(slices.Concat(head, mid, tail), "\n")
+	}
+
+	// Общие строки по краям остаются, середина сверх лимита заменяется целиком
+	lines := Lines(text(oldMid), text(newMid))
+	if len(lines) != len(head)+MaxLines+1+len(tail) {
+		t.Fatalf("got %d lines", len(lines))
+	}
+	if want := (Line{Op: Delete, Text: "old0", OldLine: 4}); lines[3] != want {
+		t.Fatalf("first deleted line = %+v, want %+v", lines[3], want)
+	}
+	if want := (Line{Op: Insert, Text: "new0", NewLine: 4}); lines[3+MaxLines] != want {
+		t.Fatalf("inserted line = %+v, want %+v", lines[3+MaxLines], want)
+	}
+	if want := (Line{Op: Equal, Text: "tail1", OldLine: MaxLines + 5, NewLine: 6}); lines[len(lines)-1] != want {
+		t.Fatalf("last line = %+v, want %+v", lines[len(lines)-1], want)
+	}
+
+	// Длинный текст с маленькой правкой по-прежнему сравнивается точно
+	long := numbered("line", 5*MaxLines)
+	edited := slices.Clone(long)
+	edited[MaxLines] = "changed"
+	got := Format(Lines(strings.Join(long, "\n"), strings.Join(edited, "\n")))
+	if n := strings.Count(got, "\n-") + strings.Count(got, "\n+"); n != 2 {
+		t.Fatalf("got %d changed lines, want 2", n)
+	}
+}
+
+// Из результата должны восстанавливаться оба текста, а число правок
+// должно быть минимальным: len(a) + len(b) - 2*LCS
+func TestLinesReconstructsRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	words := []string{"a", "b", "c", "d"}
+	randomText := func() []string {
+		lines := make([]string, rng.Intn(12))
+		for i := range lines {
+			lines[i] = words[rng.Intn(len(words))]
+		}
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := randomText(), randomText()
+		lines := Lines(strings.Join(a, "\n"), strings.Join(b, "\n"))
+
+		var gotA, gotB []string
+		edits := 0
+		for _, l := range lines {
+			if l.Op != Insert {
+				gotA = append(gotA, l.Text)
+			}
+			if l.Op != Delete {
+				gotB = append(gotB, l.Text)
+			}
+			if l.Op != Equal {
+				edits++
+			}
+		}
+		if strings.Join(gotA, "\n") != strings.Join(a, "\n") || strings.Join(gotB, "\n") != strings.Join(b, "\n") {
+			t.Fatalf("case %d: diff of %q and %q does not reconstruct inputs:\n%s", i, a, b, Format(lines))
+		}
+		if want := len(a) + len(b) - 2*lcs(a, b); edits != want {
+			t.Fatalf("case %d: %d edits, want %d:\n%s", i, edits, want, Format(lines))
+		}
+	}
+}
+
+func lcs(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else {
+				dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+			}
+		}
+	}
+	return dp[0][0]
+}
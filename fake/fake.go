@@ -0,0 +1,245 @@
+// Package fake генерирует правдоподобных пользователей и статьи на русском и
+// английском для нагрузочных экспериментов. Данные псевдослучайные, но
+// воспроизводимые: одно зерно и одна последовательность вызовов дают одни и
+// те же данные (в пределах одной версии программы).
+package fake
+
+import (
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/slug"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// DefaultSpan — глубина истории по умолчанию: даты регистрации и статей
+// распределяются по двум годам до Options.Until
+const DefaultSpan = 2 * 365 * 24 * time.Hour
+
+// authorSkew — показатель распределения Ципфа для выбора автора статьи:
+// несколько авторов пишут большую часть статей, остальные — по одной-две
+const authorSkew = 1.2
+
+// Options задает генератор
+type Options struct {
+	Seed uint64
+	// Until — конец интервала дат; все временные метки не позже него
+	Until time.Time
+	// Span — длина интервала дат; 0 — DefaultSpan
+	Span time.Duration
+}
+
+// Generator выдает пользователей и статьи. Не безопасен для
+// конкурентного использования.
+type Generator struct {
+	rnd   *rand.Rand
+	seed  uint64
+	until time.Time
+	span  time.Duration
+
+	users   int
+	writers []writer
+	zipf    *rand.Zipf
+}
+
+// writer — пользователь, которому можно приписывать статьи
+type writer struct {
+	user *models.User
+	lang language
+}
+
+func New(opts Options) *Generator {
+	if opts.Span <= 0 {
+		opts.Span = DefaultSpan
+	}
+	return &Generator{
+		rnd:   rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x9e3779b97f4a7c15)),
+		seed:  opts.Seed,
+		until: opts.Until.UTC().Truncate(time.Second),
+		span:  opts.Span,
+	}
+}
+
+// Users возвращает n новых пользователей. Роли распределены как на живом
+// сайте: в основном авторы и читатели, немного редакторов и админов.
+// Пользователи, кроме читателей, запоминаются как авторы для Article, поэтому
+// их ID, присвоенные хранилищем, должны быть заполнены до вызова Article.
+func (g *Generator) Users(n int) []*models.User {
+	users := make([]*models.User, 0, n)
+	langs := make([]language, 0, n)
+	for range n {
+		g.users++
+		lang := g.language()
+		first, last := g.name(lang)
+
+		created := g.registeredAt()
+		user := &models.User{
+			Email:     g.email(first, last),
+			Name:      first + " " + last,
+			Role:      g.role(),
+			CreatedAt: created,
+			UpdatedAt: g.between(created, g.until, 4),
+		}
+		users = append(users, user)
+		langs = append(langs, lang)
+		if user.Role != models.RoleReader {
+			g.writers = append(g.writers, writer{user: user, lang: lang})
+		}
+	}
+	// Хотя бы один автор нужен всегда, иначе статьи некому писать
+	if len(g.writers) == 0 && len(users) > 0 {
+		users[0].Role = models.RoleAuthor
+		g.writers = append(g.writers, writer{user: users[0], lang: langs[0]})
+	}
+	g.zipf = nil
+	return users
+}
+
+// Article возвращает новую статью одного из авторов, созданных Users. Автор
+// выбирается по закону Ципфа, язык статьи обычно совпадает с языком автора.
+// Статус, просмотры и даты согласованы между собой: просмотры есть только у
+// статей, которые публиковались, а даты не выходят за [регистрация автора,
+// Until]. Паникует, если авторов нет.
+func (g *Generator) Article() *models.Article {
+	if len(g.writers) == 0 {
+		panic("fake: Article called before Users")
+	}
+	if g.zipf == nil {
+		g.zipf = rand.NewZipf(g.rnd, authorSkew, 1, uint64(len(g.writers)-1))
+		// Самые плодовитые авторы — не обязательно первые
+		// зарегистрированные: ранги перемешиваются
+		g.rnd.Shuffle(len(g.writers), func(i, j int) { g.writers[i], g.writers[j] = g.writers[j], g.writers[i] })
+	}
+	w := g.writers[g.zipf.Uint64()]
+
+	lang := w.lang
+	if g.rnd.IntN(10) == 0 {
+		lang = 1 - lang
+	}
+	c := corpora[lang]
+	topic := pick(g.rnd, c.topics)
+	tech := pick(g.rnd, techs)
+
+	created := g.between(w.user.CreatedAt, g.until, 1)
+	article := &models.Article{
+		Title:     g.title(c, topic, tech),
+		Content:   g.content(c, topic, tech),
+		AuthorID:  w.user.ID,
+		Status:    g.status(),
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+
+	switch article.Status {
+	case models.ArticlePublished, models.ArticleArchived:
+		published := g.between(created, minTime(created.Add(7*24*time.Hour), g.until), 1)
+		article.PublishedAt = &published
+		article.UpdatedAt = published
+		article.Views = g.views(published)
+	}
+	if g.rnd.IntN(3) == 0 {
+		article.UpdatedAt = g.between(article.UpdatedAt, g.until, 2)
+	}
+	return article
+}
+
+type language int
+
+const (
+	langRU language = iota
+	langEN
+)
+
+// language выбирает язык пользователя: русских примерно 60%
+func (g *Generator) language() language {
+	if g.rnd.IntN(10) < 6 {
+		return langRU
+	}
+	return langEN
+}
+
+func (g *Generator) name(lang language) (first, last string) {
+	if lang == langEN {
+		return pick(g.rnd, enFirstNames), pick(g.rnd, enLastNames)
+	}
+	// Фамилия согласуется с родом имени: Иванов / Иванова
+	last = pick(g.rnd, ruLastNames)
+	if g.rnd.IntN(2) == 0 {
+		return pick(g.rnd, ruMaleNames), last
+	}
+	return pick(g.rnd, ruFemaleNames), ruFeminine(last)
+}
+
+// email строит адрес из транслитерированного имени. Номер пользователя и
+// зерно делают адрес уникальным в пределах запуска и между запусками с
+// разными зернами.
+func (g *Generator) email(first, last string) string {
+	local := strings.ReplaceAll(slug.MakeOr(first+" "+last, "user"), "-", ".")
+	return fmt.Sprintf("%s.%d@seed%d.example.com", local, g.users, g.seed)
+}
+
+func (g *Generator) role() models.Role {
+	switch n := g.rnd.IntN(100); {
+	case n < 2:
+		return models.RoleAdmin
+	case n < 10:
+		return models.RoleEditor
+	case n < 60:
+		return models.RoleAuthor
+	default:
+		return models.RoleReader
+	}
+}
+
+func (g *Generator) status() models.ArticleStatus {
+	switch n := g.rnd.IntN(100); {
+	case n < 65:
+		return models.ArticlePublished
+	case n < 85:
+		return models.ArticleDraft
+	case n < 92:
+		return models.ArticleInReview
+	default:
+		return models.ArticleArchived
+	}
+}
+
+// registeredAt — дата регистрации; регистраций становится больше ближе к
+// Until, как у растущего сайта
+func (g *Generator) registeredAt() time.Time {
+	u := g.rnd.Float64()
+	ago := time.Duration(float64(g.span) * u * u)
+	return g.until.Add(-ago).Truncate(time.Second)
+}
+
+// between возвращает момент в [from, to]. При bias > 1 он смещен к from:
+// правки обычно идут вскоре после предыдущего события.
+func (g *Generator) between(from, to time.Time, bias float64) time.Time {
+	if !to.After(from) {
+		return from
+	}
+	u := math.Pow(g.rnd.Float64(), bias)
+	return from.Add(time.Duration(float64(to.Sub(from)) * u)).Truncate(time.Second)
+}
+
+// views — число просмотров по логнормальному закону: большинство статей
+// читают десятки-сотни раз, единицы — десятки тысяч. Старые статьи успели
+// набрать больше.
+func (g *Generator) views(published time.Time) int {
+	days := g.until.Sub(published).Hours() / 24
+	v := math.Exp(4+1.5*g.rnd.NormFloat64()) * math.Sqrt(1+days/30)
+	return int(math.Min(v, math.MaxInt32/2))
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func pick[T any](rnd *rand.Rand, items []T) T {
+	return items[rnd.IntN(len(items))]
+}
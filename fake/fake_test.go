@@ -0,0 +1,137 @@
+package fake
+
+import (
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+var until = time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+// generate создает users пользователей и articles статей, присваивая
+// пользователям ID так же, как хранилище
+func generate(seed uint64, users, articles int) ([]*models.User, []*models.Article) {
+	g := New(Options{Seed: seed, Until: until})
+	us := g.Users(users)
+	for i, u := range us {
+		u.ID = i + 1
+	}
+	as := make([]*models.Article, articles)
+	for i := range as {
+		as[i] = g.Article()
+	}
+	return us, as
+}
+
+func TestDeterministic(t *testing.T) {
+	users1, articles1 := generate(42, 50, 200)
+	users2, articles2 := generate(42, 50, 200)
+	if !reflect.DeepEqual(users1, users2) || !reflect.DeepEqual(articles1, articles2) {
+		t.Fatal("the same seed produced different data")
+	}
+
+	users3, articles3 := generate(43, 50, 200)
+	if reflect.DeepEqual(users1, users3) || reflect.DeepEqual(articles1, articles3) {
+		t.Fatal("different seeds produced the same data")
+	}
+}
+
+func TestUsers(t *testing.T) {
+	users, _ := generate(1, 500, 0)
+	emails := make(map[string]bool)
+	roles := make(map[models.Role]int)
+	for _, u := range users {
+		if emails[u.Email] {
+			t.Fatalf("duplicate email %s", u.Email)
+		}
+		emails[u.Email] = true
+		if !strings.HasSuffix(u.Email, "@seed1.example.com") || !utf8.ValidString(u.Name) || u.Name == "" {
+			t.Fatalf("user = %+v", u)
+		}
+		if !u.Role.Valid() {
+			t.Fatalf("role %q", u.Role)
+		}
+		roles[u.Role]++
+		if u.CreatedAt.Before(until.Add(-DefaultSpan)) || u.UpdatedAt.Before(u.CreatedAt) || u.UpdatedAt.After(until) {
+			t.Fatalf("user %d: created %v, updated %v", u.ID, u.CreatedAt, u.UpdatedAt)
+		}
+	}
+	for _, role := range []models.Role{models.RoleReader, models.RoleAuthor, models.RoleEditor} {
+		if roles[role] == 0 {
+			t.Errorf("no users with role %s: %v", role, roles)
+		}
+	}
+}
+
+func TestArticles(t *testing.T) {
+	users, articles := generate(7, 200, 2000)
+	byID := make(map[int]*models.User)
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+
+	perAuthor := make(map[int]int)
+	statuses := make(map[models.ArticleStatus]int)
+	cyrillic := 0
+	for _, a := range articles {
+		if err := repository.CheckBulkArticle(a); err != nil {
+			t.Fatal(err)
+		}
+		author := byID[a.AuthorID]
+		if author == nil || author.Role == models.RoleReader {
+			t.Fatalf("article %q by %+v", a.Title, author)
+		}
+		if a.CreatedAt.Before(author.CreatedAt) || a.UpdatedAt.After(until) {
+			t.Fatalf("article %q: created %v by a user registered %v, updated %v", a.Title, a.CreatedAt, author.CreatedAt, a.UpdatedAt)
+		}
+		if a.PublishedAt != nil && (a.PublishedAt.Before(a.CreatedAt) || a.PublishedAt.After(a.UpdatedAt)) {
+			t.Fatalf("article %q: published %v outside [%v, %v]", a.Title, a.PublishedAt, a.CreatedAt, a.UpdatedAt)
+		}
+		if a.PublishedAt == nil && a.Views != 0 {
+			t.Fatalf("unpublished article %q has %d views", a.Title, a.Views)
+		}
+		if a.Title == "" || utf8.RuneCountInString(a.Title) > 255 || !strings.Contains(a.Content, "\n## ") {
+			t.Fatalf("article = %q\n%s", a.Title, a.Content)
+		}
+		perAuthor[a.AuthorID]++
+		statuses[a.Status]++
+		if strings.ContainsAny(a.Title, "аеиоу") {
+			cyrillic++
+		}
+	}
+
+	for _, st := range []models.ArticleStatus{models.ArticleDraft, models.ArticleInReview, models.ArticlePublished, models.ArticleArchived} {
+		if statuses[st] == 0 {
+			t.Errorf("no %s articles: %v", st, statuses)
+		}
+	}
+	if cyrillic < len(articles)/4 || cyrillic > len(articles)*3/4 {
+		t.Errorf("%d of %d titles are in Russian, want a mix", cyrillic, len(articles))
+	}
+
+	// Распределение авторов перекошено: самый плодовитый пишет намного
+	// больше среднего
+	top := 0
+	for _, n := range perAuthor {
+		top = max(top, n)
+	}
+	if mean := len(articles) / len(perAuthor); top < 10*mean {
+		t.Errorf("top author wrote %d articles, mean is %d; want a skewed distribution", top, mean)
+	}
+}
+
+func TestArticleWithoutWriters(t *testing.T) {
+	g := New(Options{Seed: 1, Until: until})
+	users := g.Users(1)
+	if users[0].Role == models.RoleReader {
+		t.Fatal("the only user is a reader, articles have no author")
+	}
+	users[0].ID = 1
+	if a := g.Article(); a.AuthorID != 1 {
+		t.Fatalf("AuthorID = %d, want 1", a.AuthorID)
+	}
+}
@@ -0,0 +1,278 @@
+package fake
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// corpus — словарь и шаблоны текста на одном языке. В шаблонах {topic} —
+// тема статьи, {Topic} — она же с заглавной буквы, {tech} — технология,
+// {n} — небольшое число.
+type corpus struct {
+	// titleCase — {Topic} пишется с заглавной буквы в каждом слове
+	titleCase  bool
+	topics     []string
+	titles     []string
+	headings   []string
+	sentences  []string
+	listItems  []string
+	conclusion []string
+}
+
+var corpora = [...]*corpus{
+	langRU: {
+		topics: []string{
+			"индексы в базе данных", "пул соединений", "корректное завершение сервиса",
+			"обработка ошибок", "структурированное логирование", "полнотекстовый поиск",
+			"ограничение частоты запросов", "кэширование", "миграции схемы", "код-ревью",
+			"наблюдаемость", "нагрузочное тестирование", "отмена запросов через context",
+			"профилирование памяти", "проектирование API", "пагинация", "фоновые задачи",
+			"фича-флаги", "модульные тесты", "конкурентность", "транзакции",
+			"очереди сообщений", "деплой без простоя", "мониторинг",
+		},
+		titles: []string{
+			"{Topic} в {tech}: практическое руководство",
+			"{Topic} на практике",
+			"Чек-лист: {topic}",
+			"Разбираемся: {topic}",
+			"{Topic} без боли",
+			"Опыт команды: {topic} в продакшене",
+			"{Topic} и {tech}: чему мы научились за год",
+			"Заметки на полях: {topic}",
+			"{Topic}: типичные ошибки и как их избежать",
+			"Ещё раз про {tech}: {topic}",
+		},
+		headings: []string{
+			"Зачем это нужно", "С чего начать", "Как это устроено", "Типичные ошибки",
+			"Пример из практики", "Измеряем результат", "Что пошло не так",
+			"Альтернативы", "Настройки по умолчанию", "Тестирование", "Итоги",
+		},
+		sentences: []string{
+			"Когда сервис растёт, {topic} перестаёт быть мелочью.",
+			"В {tech} для этого есть всё необходимое, но детали легко упустить.",
+			"Мы столкнулись с этим, когда время ответа выросло до {n} секунд.",
+			"Первое, что стоит сделать, — собрать метрики и понять, где узкое место.",
+			"Хорошая новость: большинство проблем видны ещё на ревью.",
+			"Плохая новость: часть из них проявляется только под нагрузкой.",
+			"На тестовом стенде всё работало, а в продакшене начались таймауты.",
+			"Важно не оптимизировать вслепую, а проверять каждое изменение замерами.",
+			"Документация {tech} описывает это довольно кратко.",
+			"На это ушло около {n} недель, и результат того стоил.",
+			"Такой подход хорошо масштабируется и не требует сложной инфраструктуры.",
+			"Главное — договориться в команде о единых правилах.",
+			"Если коротко: {topic} нужно проектировать заранее, а не чинить потом.",
+			"Ниже — конкретные шаги, которые сработали у нас.",
+			"Стоит помнить и о совместимости со старыми клиентами.",
+			"Автоматические проверки в CI сэкономили нам немало нервов.",
+			"Код получился короче, а поддерживать его стало проще.",
+			"Иногда самое простое решение оказывается самым надёжным.",
+		},
+		listItems: []string{
+			"измерьте текущее состояние", "включите подробные логи на время эксперимента",
+			"добавьте тест, который воспроизводит проблему", "проверьте значения по умолчанию",
+			"ограничьте время ожидания", "обновите {tech} до актуальной версии",
+			"опишите решение в README", "настройте алерты", "уберите лишние аллокации",
+		},
+		conclusion: []string{
+			"Надеюсь, этот опыт пригодится и вам. Вопросы и замечания — в комментариях.",
+			"Если у вас есть свой рецепт, поделитесь в комментариях.",
+			"В следующей статье разберём, как мы автоматизировали эти проверки.",
+			"Полный пример кода лежит в репозитории проекта.",
+		},
+	},
+	langEN: {
+		titleCase: true,
+		topics: []string{
+			"database indexing", "connection pooling", "graceful shutdown", "error handling",
+			"structured logging", "full-text search", "rate limiting", "caching",
+			"schema migrations", "code review", "observability", "load testing",
+			"context cancellation", "memory profiling", "API design", "pagination",
+			"background jobs", "feature flags", "unit testing", "concurrency",
+			"transactions", "message queues", "zero-downtime deploys", "monitoring",
+		},
+		titles: []string{
+			"A Practical Guide to {Topic} in {tech}",
+			"{Topic} in Practice",
+			"{n} Things I Learned About {Topic}",
+			"Understanding {Topic}",
+			"{Topic} Without the Pain",
+			"How We Do {Topic} in Production",
+			"{Topic} with {tech}: Lessons From a Year in Production",
+			"{Topic}: Common Mistakes and How to Avoid Them",
+			"Revisiting {Topic} in {tech}",
+			"Notes on {Topic}",
+		},
+		headings: []string{
+			"Why It Matters", "Getting Started", "How It Works", "Common Mistakes",
+			"A Real-World Example", "Measuring the Results", "What Went Wrong",
+			"Alternatives", "Sensible Defaults", "Testing", "Wrapping Up",
+		},
+		sentences: []string{
+			"As a service grows, {topic} stops being a detail.",
+			"{tech} gives you everything you need, but the details are easy to miss.",
+			"We ran into this when response times grew {n}x overnight.",
+			"The first step is to collect metrics and find the actual bottleneck.",
+			"The good news is that most problems are visible in code review.",
+			"The bad news is that some of them only show up under load.",
+			"Everything worked on staging, and then production started timing out.",
+			"Do not optimize blindly: measure every change.",
+			"The {tech} documentation covers this only briefly.",
+			"It took us {n} days, and it was worth it.",
+			"This approach scales well and needs no fancy infrastructure.",
+			"The key is agreeing on a single set of rules across the team.",
+			"In short: design for {topic} up front instead of fixing it later.",
+			"Below are the concrete steps that worked for us.",
+			"Keep backward compatibility with older clients in mind.",
+			"Automated checks in CI saved us a lot of trouble.",
+			"The code got shorter and became easier to maintain.",
+			"Sometimes the simplest solution turns out to be the most reliable one.",
+		},
+		listItems: []string{
+			"measure the current state", "turn on verbose logging for the experiment",
+			"add a test that reproduces the problem", "check the defaults",
+			"put a bound on every wait", "upgrade {tech} to a recent version",
+			"document the decision in the README", "set up alerts", "cut unnecessary allocations",
+		},
+		conclusion: []string{
+			"I hope this helps. Questions and corrections are welcome in the comments.",
+			"If you have your own recipe, share it in the comments.",
+			"In the next post we will automate these checks.",
+			"The full example is in the project repository.",
+		},
+	},
+}
+
+var techs = []string{
+	"Go", "PostgreSQL", "Docker", "Kubernetes", "Redis", "Kafka", "gRPC",
+	"Nginx", "Linux", "Terraform", "Prometheus", "ClickHouse",
+}
+
+// snippets — фрагменты кода для статей; язык подсветки в первой строке
+var snippets = []string{
+	"go\nctx, cancel := context.WithTimeout(ctx, 5*time.Second)\ndefer cancel()\n\nif err := db.PingContext(ctx); err != nil {\n\treturn fmt.Errorf(\"ping: %w\", err)\n}",
+	"go\nfor i := range workers {\n\twg.Go(func() {\n\t\tprocess(ctx, i, jobs)\n\t})\n}\nwg.Wait()",
+	"sql\nCREATE INDEX CONCURRENTLY idx_articles_author_created\n    ON articles (author_id, created_at DESC);",
+	"sql\nEXPLAIN (ANALYZE, BUFFERS)\nSELECT id, title FROM articles\nWHERE status = 'published'\nORDER BY created_at DESC\nLIMIT 20;",
+	"bash\ngo test -run TestStore -count=1 -race ./...",
+	"yaml\nresources:\n  limits:\n    memory: 256Mi\n    cpu: 500m",
+}
+
+var (
+	ruMaleNames = []string{
+		"Алексей", "Дмитрий", "Иван", "Сергей", "Андрей", "Михаил", "Николай",
+		"Павел", "Егор", "Артём", "Максим", "Кирилл", "Роман", "Денис",
+	}
+	ruFemaleNames = []string{
+		"Анна", "Мария", "Елена", "Ольга", "Наталья", "Екатерина", "Дарья",
+		"Ксения", "Юлия", "Светлана", "Алина", "Полина", "Ирина", "Татьяна",
+	}
+	// ruLastNames — мужские фамилии на -ов, -ев, -ин: женская форма
+	// получается добавлением «а»
+	ruLastNames = []string{
+		"Иванов", "Смирнов", "Кузнецов", "Попов", "Васильев", "Петров", "Соколов",
+		"Михайлов", "Новиков", "Фёдоров", "Морозов", "Волков", "Алексеев", "Лебедев",
+		"Семёнов", "Егоров", "Павлов", "Козлов", "Степанов", "Никитин", "Орлов",
+	}
+	enFirstNames = []string{
+		"James", "Emma", "Oliver", "Sophia", "Liam", "Ava", "Noah", "Mia",
+		"Lucas", "Isabella", "Ethan", "Charlotte", "Mason", "Amelia", "Henry", "Grace",
+	}
+	enLastNames = []string{
+		"Smith", "Johnson", "Williams", "Brown", "Jones", "Miller", "Davis", "Wilson",
+		"Anderson", "Taylor", "Thomas", "Moore", "Martin", "Clark", "Walker", "Young",
+	}
+)
+
+func ruFeminine(last string) string {
+	return last + "а"
+}
+
+// title собирает заголовок по шаблону
+func (g *Generator) title(c *corpus, topic, tech string) string {
+	return g.fill(c, pick(g.rnd, c.titles), topic, tech)
+}
+
+// content собирает Markdown-текст: вступление, несколько разделов с
+// абзацами, списками и кодом и заключение. Длина — от одного до
+// нескольких килобайт.
+func (g *Generator) content(c *corpus, topic, tech string) string {
+	var b strings.Builder
+	b.WriteString(g.paragraph(c, topic, tech))
+
+	sections := 2 + g.rnd.IntN(4)
+	for _, i := range g.rnd.Perm(len(c.headings))[:sections] {
+		b.WriteString("\n\n## ")
+		b.WriteString(c.headings[i])
+		for range 1 + g.rnd.IntN(3) {
+			b.WriteString("\n\n")
+			b.WriteString(g.paragraph(c, topic, tech))
+		}
+		if g.rnd.IntN(3) == 0 {
+			b.WriteString("\n")
+			for _, j := range g.rnd.Perm(len(c.listItems))[:3+g.rnd.IntN(3)] {
+				b.WriteString("\n- ")
+				b.WriteString(g.fill(c, c.listItems[j], topic, tech))
+			}
+		}
+		if g.rnd.IntN(4) == 0 {
+			b.WriteString("\n\n```")
+			b.WriteString(pick(g.rnd, snippets))
+			b.WriteString("\n```")
+		}
+	}
+
+	b.WriteString("\n\n")
+	b.WriteString(pick(g.rnd, c.conclusion))
+	return b.String()
+}
+
+// paragraph — от трех до шести неповторяющихся предложений
+func (g *Generator) paragraph(c *corpus, topic, tech string) string {
+	perm := g.rnd.Perm(len(c.sentences))[:3+g.rnd.IntN(4)]
+	sentences := make([]string, len(perm))
+	for i, j := range perm {
+		sentences[i] = g.fill(c, c.sentences[j], topic, tech)
+	}
+	return strings.Join(sentences, " ")
+}
+
+// fill подставляет тему, технологию и число в шаблон и делает первую букву
+// заглавной
+func (g *Generator) fill(c *corpus, template, topic, tech string) string {
+	n := 2 + g.rnd.IntN(9)
+	s := strings.NewReplacer(
+		"{topic}", topic,
+		"{Topic}", c.capitalize(topic),
+		"{tech}", tech,
+		"{n}", strconv.Itoa(n),
+	).Replace(template)
+	return capitalize(s)
+}
+
+// capitalize оформляет тему для подстановки {Topic}
+func (c *corpus) capitalize(topic string) string {
+	if c.titleCase {
+		return titleCase(topic)
+	}
+	return capitalize(topic)
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// titleCase делает заглавной первую букву каждого слова, как принято в
+// английских заголовках: "API design" → "API Design"
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		words[i] = capitalize(w)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,101 @@
+package feed
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() *Feed {
+	published := time.Date(2024, 5, 1, 9, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	items := []Item{{
+		ID:        TagURI("blog.example.com", published, "articles/42"),
+		Title:     "Привет & <мир>",
+		Link:      "https://blog.example.com/slugs/privet-mir",
+		Author:    "Alice",
+		Summary:   "Начало статьи…",
+		Published: published,
+		Updated:   published.Add(time.Hour),
+	}}
+	return &Feed{
+		ID:          "tag:blog.example.com,2024:feed",
+		Title:       "Blog",
+		Description: "Статьи",
+		Link:        "https://blog.example.com/",
+		Self:        "https://blog.example.com/feed/atom",
+		Updated:     LastUpdated(items, time.Time{}),
+		Items:       items,
+	}
+}
+
+func TestTagURI(t *testing.T) {
+	date := time.Date(2024, 5, 1, 1, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	if got, want := TagURI("example.com", date, "articles/7"), "tag:example.com,2024-04-30:articles/7"; got != want {
+		t.Fatalf("TagURI = %q, want %q", got, want)
+	}
+}
+
+func TestRSS(t *testing.T) {
+	body, err := testFeed().RSS()
+	if err != nil {
+		t.Fatalf("RSS: %v", err)
+	}
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title   string `xml:"title"`
+				GUID    string `xml:"guid"`
+				PubDate string `xml:"pubDate"`
+				Creator string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("RSS is not valid XML: %v\n%s", err, body)
+	}
+	if doc.Version != "2.0" || len(doc.Channel.Items) != 1 {
+		t.Fatalf("RSS = %+v", doc)
+	}
+	item := doc.Channel.Items[0]
+	if item.Title != "Привет & <мир>" || item.GUID != "tag:blog.example.com,2024-05-01:articles/42" || item.Creator != "Alice" {
+		t.Fatalf("RSS item = %+v", item)
+	}
+	if item.PubDate != "Wed, 01 May 2024 06:00:00 +0000" || doc.Channel.LastBuildDate != "Wed, 01 May 2024 07:00:00 +0000" {
+		t.Fatalf("RSS dates = %q, %q", item.PubDate, doc.Channel.LastBuildDate)
+	}
+	if !strings.Contains(string(body), `<guid isPermaLink="false">`) || !strings.Contains(string(body), `<atom:link href="https://blog.example.com/feed/atom" rel="self"`) {
+		t.Fatalf("RSS body:\n%s", body)
+	}
+}
+
+func TestAtom(t *testing.T) {
+	body, err := testFeed().Atom()
+	if err != nil {
+		t.Fatalf("Atom: %v", err)
+	}
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Updated string   `xml:"updated"`
+		Entries []struct {
+			ID        string `xml:"id"`
+			Published string `xml:"published"`
+			Updated   string `xml:"updated"`
+			Author    string `xml:"author>name"`
+			Summary   string `xml:"summary"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("Atom is not valid XML: %v\n%s", err, body)
+	}
+	if doc.Updated != "2024-05-01T07:00:00Z" || len(doc.Entries) != 1 {
+		t.Fatalf("Atom = %+v", doc)
+	}
+	entry := doc.Entries[0]
+	if entry.ID != "tag:blog.example.com,2024-05-01:articles/42" || entry.Published != "2024-05-01T06:00:00Z" ||
+		entry.Updated != "2024-05-01T07:00:00Z" || entry.Author != "Alice" || entry.Summary != "Начало статьи…" {
+		t.Fatalf("Atom entry = %+v", entry)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-articles-app/migrate"
+	"go-articles-app/migrations"
+	"strconv"
+)
+
+const migrateUsage = "usage: migrate up | down [N] | goto VERSION | force VERSION | status"
+
+// runMigrate выполняет команду migrate: up, down [N], goto, force и status
+func runMigrate(ctx context.Context, c *cli, args []string) error {
+	m, err := migrate.New(c.db, migrations.FS)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	version := func() (uint, error) {
+		if len(args) != 2 {
+			return 0, errors.New(migrateUsage)
+		}
+		v, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid version %q", args[1])
+		}
+		return uint(v), nil
+	}
+
+	switch args[0] {
+	case "up":
+		return m.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		return m.Down(ctx, steps)
+	case "goto":
+		v, err := version()
+		if err != nil {
+			return err
+		}
+		return m.Goto(ctx, v)
+	case "force":
+		v, err := version()
+		if err != nil {
+			return err
+		}
+		return m.Force(ctx, v)
+	case "status":
+		return printMigrateStatus(ctx, c, m)
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+func printMigrateStatus(ctx context.Context, c *cli, m *migrate.Migrator) error {
+	s, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "version: %d (latest %d)", s.Version, s.Latest)
+	if s.Dirty {
+		fmt.Fprint(c.stdout, ", dirty")
+	}
+	fmt.Fprintln(c.stdout)
+	for _, mg := range m.Migrations() {
+		mark := "  "
+		if mg.Version <= s.Version {
+			mark = "✓ "
+		}
+		fmt.Fprintln(c.stdout, mark+mg.String())
+	}
+	return nil
+}
+
+// checkSchema не дает запускать команды на отставшей или грязной схеме
+func checkSchema(ctx context.Context, database *sql.DB) error {
+	m, err := migrate.New(database, migrations.FS)
+	if err != nil {
+		return err
+	}
+	return m.Check(ctx)
+}
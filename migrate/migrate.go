@@ -0,0 +1,378 @@
+// Package migrate применяет встроенные SQL-миграции. Состояние хранится в
+// таблице schema_migrations в формате golang-migrate (одна строка: version,
+// dirty), поэтому базы, размеченные внешней утилитой migrate, продолжают
+// работать без изменений.
+//
+// Каждая миграция выполняется в транзакции вместе с записью новой версии:
+// при ошибке схема и версия остаются прежними. Флаг dirty выставляет только
+// golang-migrate, когда миграция упала на середине; такую базу нужно
+// исправить вручную и отметить версию командой force.
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrDirty          = errors.New("database schema is dirty")
+	ErrBehind         = errors.New("database schema is behind")
+	ErrUnknownVersion = errors.New("unknown migration version")
+)
+
+// lockKey — ключ pg_advisory_lock, под которым выполняются миграции.
+// Второй процесс, запустивший миграции одновременно, ждет первого.
+const lockKey int64 = 0x676f617274696d67 // "goartimg"
+
+// Migration — пара файлов NNNNNN_name.up.sql и NNNNNN_name.down.sql
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%06d_%s", m.Version, m.Name)
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Load читает миграции из корня fsys. У каждой версии должны быть оба файла.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	// has отмечает найденные файлы: пустой файл допустим, но отличается от
+	// отсутствующего
+	type entry struct {
+		Migration
+		hasUp, hasDown bool
+	}
+	byVersion := make(map[uint]*entry)
+	for _, file := range entries {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".sql") {
+			continue
+		}
+		match := fileName.FindStringSubmatch(file.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migrate: %s: name must look like 000001_name.up.sql", file.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("migrate: %s: version must be a positive number", file.Name())
+		}
+		data, err := fs.ReadFile(fsys, file.Name())
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %w", err)
+		}
+
+		e := byVersion[uint(version)]
+		if e == nil {
+			e = &entry{Migration: Migration{Version: uint(version), Name: match[2]}}
+			byVersion[e.Version] = e
+		}
+		if e.Name != match[2] {
+			return nil, fmt.Errorf("migrate: version %d has two names: %s and %s", version, e.Name, match[2])
+		}
+		dst, has := &e.Up, &e.hasUp
+		if match[3] == "down" {
+			dst, has = &e.Down, &e.hasDown
+		}
+		if *has {
+			return nil, fmt.Errorf("migrate: duplicate %s migration for version %d", match[3], version)
+		}
+		*dst, *has = string(data), true
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, e := range byVersion {
+		if !e.hasUp || !e.hasDown {
+			return nil, fmt.Errorf("migrate: %s: both up and down files are required", e.Migration)
+		}
+		migrations = append(migrations, e.Migration)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return cmp.Compare(a.Version, b.Version) })
+	return migrations, nil
+}
+
+// Migrator применяет миграции к базе
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Migrations возвращает известные миграции по возрастанию версии
+func (m *Migrator) Migrations() []Migration {
+	return slices.Clone(m.migrations)
+}
+
+// Latest — версия последней известной миграции, 0 если миграций нет
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status — состояние схемы базы. Version 0 — миграции не применялись.
+type Status struct {
+	Version uint
+	Dirty   bool
+	Latest  uint
+}
+
+// Status читает версию схемы без блокировки
+func (m *Migrator) Status(ctx context.Context) (Status, error) {
+	version, dirty, err := readVersion(ctx, m.db)
+	if err != nil {
+		return Status{}, err
+	}
+	return Status{Version: version, Dirty: dirty, Latest: m.Latest()}, nil
+}
+
+// Check проверяет при запуске, что схема не отстает от приложения и не
+// осталась в незавершенном состоянии. Схема новее приложения допустима:
+// так бывает при откате приложения на предыдущую версию.
+func (m *Migrator) Check(ctx context.Context) error {
+	s, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if s.Dirty {
+		return fmt.Errorf("%w: migration %d failed part-way; repair the schema by hand, then run \"migrate force <version>\"", ErrDirty, s.Version)
+	}
+	if s.Version < s.Latest {
+		return fmt.Errorf("%w: database is at version %d, the application needs %d; run \"migrate up\"", ErrBehind, s.Version, s.Latest)
+	}
+	return nil
+}
+
+// Up применяет все непримененные миграции
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.Goto(ctx, m.Latest())
+}
+
+// Down откатывает steps последних примененных миграций
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("migrate: steps must be positive, got %d", steps)
+	}
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := m.current(ctx, conn)
+		if err != nil {
+			return err
+		}
+		i := m.index(current)
+		target := uint(0)
+		if i-steps >= 0 {
+			target = m.migrations[i-steps].Version
+		}
+		return m.migrate(ctx, conn, current, target)
+	})
+}
+
+// Goto приводит схему к версии version вверх или вниз. 0 откатывает все.
+func (m *Migrator) Goto(ctx context.Context, version uint) error {
+	if version != 0 && m.index(version) < 0 {
+		return fmt.Errorf("migrate: %w %d", ErrUnknownVersion, version)
+	}
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := m.current(ctx, conn)
+		if err != nil {
+			return err
+		}
+		return m.migrate(ctx, conn, current, version)
+	})
+}
+
+// Force записывает версию и снимает dirty, не выполняя SQL. Нужна после
+// ручного исправления схемы, на которой упала миграция.
+func (m *Migrator) Force(ctx context.Context, version uint) error {
+	if version != 0 && m.index(version) < 0 {
+		return fmt.Errorf("migrate: %w %d", ErrUnknownVersion, version)
+	}
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("migrate: begin: %w", err)
+		}
+		defer tx.Rollback()
+		if err := setVersion(ctx, tx, version); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// index возвращает позицию миграции version или -1
+func (m *Migrator) index(version uint) int {
+	i, ok := slices.BinarySearchFunc(m.migrations, version, func(mg Migration, v uint) int {
+		return cmp.Compare(mg.Version, v)
+	})
+	if !ok {
+		return -1
+	}
+	return i
+}
+
+// current читает версию под блокировкой и проверяет, что с ней можно работать
+func (m *Migrator) current(ctx context.Context, conn *sql.Conn) (uint, error) {
+	version, dirty, err := readVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("migrate: %w at version %d; repair the schema by hand, then run \"migrate force <version>\"", ErrDirty, version)
+	}
+	if version != 0 && m.index(version) < 0 {
+		return 0, fmt.Errorf("migrate: database is at %w %d", ErrUnknownVersion, version)
+	}
+	return version, nil
+}
+
+// migrate применяет миграции от current до target по одной, каждую в
+// своей транзакции
+func (m *Migrator) migrate(ctx context.Context, conn *sql.Conn, current, target uint) error {
+	if target == current {
+		log.Printf("migrate: no change, at version %d", current)
+		return nil
+	}
+	if target > current {
+		for _, mg := range m.migrations {
+			if mg.Version > current && mg.Version <= target {
+				if err := apply(ctx, conn, mg, "up", mg.Up, mg.Version); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mg := m.migrations[i]
+		if mg.Version > current || mg.Version <= target {
+			continue
+		}
+		prev := uint(0)
+		if i > 0 {
+			prev = m.migrations[i-1].Version
+		}
+		if err := apply(ctx, conn, mg, "down", mg.Down, prev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func apply(ctx context.Context, conn *sql.Conn, mg Migration, direction, query string, version uint) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migrate: begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	if strings.TrimSpace(query) != "" {
+		// Без аргументов lib/pq отправляет простой запрос, в котором
+		// допустимо несколько команд через точку с запятой
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("migrate: %s %s: %w", mg, direction, err)
+		}
+	}
+	if err := setVersion(ctx, tx, version); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migrate: %s %s: commit: %w", mg, direction, err)
+	}
+	log.Printf("migrate: %s %s", mg, direction)
+	return nil
+}
+
+// withLock выполняет fn на отдельном соединении под advisory-блокировкой.
+// Блокировка сессионная, поэтому все запросы идут через одно соединение.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf("migrate: acquire lock: %w", err)
+	}
+	defer func() {
+		// Контекст мог быть отменен, но блокировку нужно снять до возврата
+		// соединения в пул
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey); err != nil {
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`); err != nil {
+		return fmt.Errorf("migrate: create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// readVersion читает версию из schema_migrations. Отсутствие таблицы или
+// строки означает версию 0.
+func readVersion(ctx context.Context, q querier) (uint, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := q.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	var pqErr *pq.Error
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, false, nil
+	case errors.As(err, &pqErr) && pqErr.Code == "42P01": // undefined_table
+		return 0, false, nil
+	case err != nil:
+		return 0, false, fmt.Errorf("migrate: read version: %w", err)
+	}
+	if version < 0 {
+		// golang-migrate пишет -1, когда откат всех миграций упал на середине
+		return 0, dirty, nil
+	}
+	return uint(version), dirty, nil
+}
+
+func setVersion(ctx context.Context, tx *sql.Tx, version uint) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return fmt.Errorf("migrate: set version: %w", err)
+	}
+	if version == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, int64(version)); err != nil {
+		return fmt.Errorf("migrate: set version: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package migrate
+
+import (
+	"go-articles-app/migrations"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000002_b.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+		"000002_b.down.sql": {Data: []byte("DROP TABLE b;")},
+		"000001_a.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+		"000001_a.down.sql": {Data: []byte("")},
+		"README.md":         {Data: []byte("ignored")},
+	}
+	got, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(got) != 2 || got[0].Version != 1 || got[1].Version != 2 {
+		t.Fatalf("Load = %+v, want versions 1, 2", got)
+	}
+	if got[0].String() != "000001_a" || got[1].Down != "DROP TABLE b;" {
+		t.Fatalf("Load = %+v", got)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{"missing down", fstest.MapFS{"000001_a.up.sql": {}}, "both up and down"},
+		{"bad name", fstest.MapFS{"create_users.sql": {}}, "name must look like"},
+		{"zero version", fstest.MapFS{"0_a.up.sql": {}, "0_a.down.sql": {}}, "positive number"},
+		{"two names", fstest.MapFS{"1_a.up.sql": {}, "1_b.down.sql": {}}, "two names"},
+		{"duplicate", fstest.MapFS{"1_a.up.sql": {}, "01_a.up.sql": {}, "1_a.down.sql": {}}, "duplicate up"},
+	}
+	for _, tt := range tests {
+		if _, err := Load(tt.fsys); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+// Встроенные миграции должны загружаться и идти без пропусков
+func TestEmbeddedMigrations(t *testing.T) {
+	got, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(got) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+	for i, m := range got {
+		if m.Version != uint(i+1) {
+			t.Fatalf("migration %d has version %d, want %d", i, m.Version, i+1)
+		}
+	}
+}
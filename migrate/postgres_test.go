@@ -0,0 +1,195 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-articles-app/migrations"
+	"maps"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TEST_DATABASE_URL — та же база, что и для тестов репозиториев. Миграции
+// прогоняются во временной схеме, которая удаляется после теста.
+const testDSNEnv = "TEST_DATABASE_URL"
+
+func openTestSchema(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("migrate_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() { admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`) })
+
+	if strings.Contains(dsn, "://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		dsn += sep + "search_path=" + schema
+	} else {
+		dsn += " search_path=" + schema
+	}
+	database, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func TestPostgresMigrator(t *testing.T) {
+	database := openTestSchema(t)
+	ctx := context.Background()
+	m, err := New(database, migrations.FS)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	latest := m.Latest()
+
+	if err := m.Check(ctx); !errors.Is(err, ErrBehind) {
+		t.Fatalf("Check on empty schema = %v, want ErrBehind", err)
+	}
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if err := m.Check(ctx); err != nil {
+		t.Fatalf("Check after Up: %v", err)
+	}
+
+	if err := m.Down(ctx, 2); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if s, _ := m.Status(ctx); s.Version != latest-2 {
+		t.Fatalf("version after Down(2) = %d, want %d", s.Version, latest-2)
+	}
+	if err := m.Goto(ctx, 0); err != nil {
+		t.Fatalf("Goto 0: %v", err)
+	}
+	var tables int
+	database.QueryRow(`SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name <> 'schema_migrations'`).Scan(&tables)
+	if tables != 0 {
+		t.Fatalf("%d tables left after Goto 0", tables)
+	}
+	if err := m.Goto(ctx, latest); err != nil {
+		t.Fatalf("Goto latest: %v", err)
+	}
+
+	if _, err := database.Exec(`UPDATE schema_migrations SET dirty = true`); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Check(ctx); !errors.Is(err, ErrDirty) {
+		t.Fatalf("Check on dirty schema = %v, want ErrDirty", err)
+	}
+	if err := m.Up(ctx); !errors.Is(err, ErrDirty) {
+		t.Fatalf("Up on dirty schema = %v, want ErrDirty", err)
+	}
+	if err := m.Force(ctx, latest); err != nil {
+		t.Fatalf("Force: %v", err)
+	}
+	if err := m.Check(ctx); err != nil {
+		t.Fatalf("Check after Force: %v", err)
+	}
+}
+
+func TestPostgresMigratorRollsBackFailedMigration(t *testing.T) {
+	database := openTestSchema(t)
+	ctx := context.Background()
+	m := &Migrator{db: database, migrations: []Migration{
+		{Version: 1, Name: "ok", Up: `CREATE TABLE a (id INT)`, Down: `DROP TABLE a`},
+		{Version: 2, Name: "broken", Up: `CREATE TABLE b (id INT); SELECT no_such_column FROM a`, Down: `DROP TABLE b`},
+	}}
+
+	if err := m.Up(ctx); err == nil || !strings.Contains(err.Error(), "000002_broken up") {
+		t.Fatalf("Up = %v, want failure in 000002_broken", err)
+	}
+	s, err := m.Status(ctx)
+	if err != nil || s.Version != 1 || s.Dirty {
+		t.Fatalf("Status = %+v, %v, want clean version 1", s, err)
+	}
+	var b sql.NullString
+	database.QueryRow(`SELECT to_regclass('b')::text`).Scan(&b)
+	if b.Valid {
+		t.Fatal("table b survived a failed migration")
+	}
+}
+
+// Повторяющийся заголовок получает суффикс -id, но он может совпасть с
+// основой другой статьи: вторая "Go" с id 42 и "Go 42" претендуют на "go-42"
+func TestPostgresSlugBackfill(t *testing.T) {
+	database := openTestSchema(t)
+	ctx := context.Background()
+	m, err := New(database, migrations.FS)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	var slugs Migration
+	for _, mig := range m.Migrations() {
+		if mig.Name == "add_article_slugs" {
+			slugs = mig
+		}
+	}
+	if slugs.Version == 0 {
+		t.Fatal("add_article_slugs migration not found")
+	}
+	if err := m.Goto(ctx, slugs.Version-1); err != nil {
+		t.Fatalf("Goto %d: %v", slugs.Version-1, err)
+	}
+
+	var authorID int
+	err = database.QueryRowContext(ctx, `INSERT INTO users (email, name) VALUES ('alice@example.com', 'Alice') RETURNING id`).Scan(&authorID)
+	if err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	titles := map[int]string{1: "Go", 2: "Go 42 2", 42: "Go", 43: "Go 42", 44: "Go 42"}
+	for id, title := range titles {
+		_, err := database.ExecContext(ctx, `INSERT INTO articles (id, title, content, author_id) VALUES ($1, $2, '', $3)`, id, title, authorID)
+		if err != nil {
+			t.Fatalf("insert article %d: %v", id, err)
+		}
+	}
+
+	if err := m.Goto(ctx, slugs.Version); err != nil {
+		t.Fatalf("Goto %d: %v", slugs.Version, err)
+	}
+	rows, err := database.QueryContext(ctx, `
+		SELECT a.id, a.slug FROM articles a
+		JOIN article_slugs s ON s.article_id = a.id AND s.slug = a.slug
+	`)
+	if err != nil {
+		t.Fatalf("query slugs: %v", err)
+	}
+	defer rows.Close()
+	got := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var slug string
+		if err := rows.Scan(&id, &slug); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		got[id] = slug
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows: %v", err)
+	}
+
+	want := map[int]string{1: "go", 2: "go-42-2", 42: "go-42-3", 43: "go-42", 44: "go-42-44"}
+	if !maps.Equal(got, want) {
+		t.Fatalf("slugs = %v, want %v", got, want)
+	}
+}
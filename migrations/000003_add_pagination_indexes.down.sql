@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_users_created_at_id;
+
+CREATE INDEX IF NOT EXISTS idx_articles_author_id ON articles(author_id);
+DROP INDEX IF EXISTS idx_articles_author_id_created_at_id;
+
+DROP INDEX IF EXISTS idx_articles_published_created_at_id;
@@ -0,0 +1,11 @@
+-- Индексы под keyset-пагинацию по (created_at, id)
+CREATE INDEX IF NOT EXISTS idx_articles_published_created_at_id
+    ON articles (created_at DESC, id DESC)
+    WHERE published = true;
+
+-- Покрывает и простой поиск по author_id, поэтому старый индекс не нужен
+CREATE INDEX IF NOT EXISTS idx_articles_author_id_created_at_id
+    ON articles (author_id, created_at DESC, id DESC);
+DROP INDEX IF EXISTS idx_articles_author_id;
+
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id ON users (created_at, id);
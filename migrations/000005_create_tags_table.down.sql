@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_article_tags_tag_id;
+DROP TABLE IF EXISTS article_tags;
+DROP INDEX IF EXISTS idx_tags_name_lower;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS tags (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(64) NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Имена тегов уникальны без учета регистра: "Go" и "go" — один тег
+CREATE UNIQUE INDEX idx_tags_name_lower ON tags (lower(name));
+
+CREATE TABLE IF NOT EXISTS article_tags (
+    article_id  INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    tag_id      INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (article_id, tag_id)
+);
+
+CREATE INDEX idx_article_tags_tag_id ON article_tags(tag_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_article_categories_category_id;
+DROP TABLE IF EXISTS article_categories;
+DROP INDEX IF EXISTS idx_categories_name_lower;
+DROP TABLE IF EXISTS categories;
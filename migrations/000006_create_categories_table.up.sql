@@ -0,0 +1,18 @@
+-- Рубрики статей. Имена уникальны без учета регистра, слаг — часть URL.
+CREATE TABLE IF NOT EXISTS categories (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(64) NOT NULL,
+    slug        VARCHAR(80) NOT NULL UNIQUE,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_categories_name_lower ON categories (lower(name));
+
+-- Первичный ключ по article_id: у статьи не больше одной рубрики. Удаление
+-- рубрики оставляет ее статьи без рубрики.
+CREATE TABLE IF NOT EXISTS article_categories (
+    article_id   INTEGER PRIMARY KEY REFERENCES articles(id) ON DELETE CASCADE,
+    category_id  INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_article_categories_category_id ON article_categories(category_id);
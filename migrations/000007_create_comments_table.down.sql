@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_comments_article_id_approved;
+DROP INDEX IF EXISTS idx_comments_status_created_at;
+DROP INDEX IF EXISTS idx_comments_author_id;
+DROP INDEX IF EXISTS idx_comments_parent_id;
+DROP INDEX IF EXISTS idx_comments_article_id;
+DROP TABLE IF EXISTS comments;
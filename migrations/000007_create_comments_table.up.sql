@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS comments (
+    id          SERIAL PRIMARY KEY,
+    article_id  INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    author_id   INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id   INTEGER,
+    body        TEXT NOT NULL,
+    status      VARCHAR(16) NOT NULL DEFAULT 'pending'
+                CHECK (status IN ('pending', 'approved', 'rejected', 'deleted')),
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    -- Ответ всегда относится к той же статье, что и родительский комментарий
+    UNIQUE (id, article_id),
+    FOREIGN KEY (parent_id, article_id) REFERENCES comments(id, article_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_comments_article_id ON comments(article_id) WHERE parent_id IS NULL;
+CREATE INDEX idx_comments_parent_id ON comments(parent_id);
+CREATE INDEX idx_comments_author_id ON comments(author_id);
+CREATE INDEX idx_comments_status_created_at ON comments(status, created_at);
+-- Подсчет одобренных комментариев для списков статей
+CREATE INDEX idx_comments_article_id_approved ON comments(article_id) WHERE status = 'approved';
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS article_revisions_immutable ON article_revisions;
+DROP FUNCTION IF EXISTS forbid_article_revision_update();
+DROP TABLE IF EXISTS article_revisions;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id          SERIAL PRIMARY KEY,
+    article_id  INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    revision    INTEGER NOT NULL,
+    title       VARCHAR(255) NOT NULL,
+    content     TEXT NOT NULL,
+    -- Автор правки; NULL, если он неизвестен или удален
+    editor_id   INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (article_id, revision)
+);
+
+-- Ревизии неизменяемы. Единственное допустимое изменение — обнуление
+-- editor_id внешним ключом при удалении пользователя.
+CREATE OR REPLACE FUNCTION forbid_article_revision_update() RETURNS trigger AS $$
+BEGIN
+    IF NEW.editor_id IS NULL
+        AND ROW(NEW.id, NEW.article_id, NEW.revision, NEW.title, NEW.content, NEW.created_at)
+            IS NOT DISTINCT FROM ROW(OLD.id, OLD.article_id, OLD.revision, OLD.title, OLD.content, OLD.created_at)
+    THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'article revisions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER article_revisions_immutable
+    BEFORE UPDATE ON article_revisions
+    FOR EACH ROW EXECUTE FUNCTION forbid_article_revision_update();
+
+-- Текущее состояние существующих статей становится первой ревизией
+INSERT INTO article_revisions (article_id, revision, title, content, editor_id, created_at)
+SELECT id, 1, title, content, author_id, updated_at
+FROM articles;
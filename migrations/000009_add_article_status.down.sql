@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS article_status_transitions;
+
+ALTER TABLE articles ADD COLUMN published BOOLEAN NOT NULL DEFAULT false;
+-- in_review и archived не видны читателям, поэтому становятся черновиками
+UPDATE articles SET published = (status = 'published');
+
+DROP INDEX IF EXISTS idx_articles_published_created_at_id;
+DROP INDEX IF EXISTS idx_articles_status;
+ALTER TABLE articles DROP COLUMN status, DROP COLUMN published_at;
+
+CREATE INDEX IF NOT EXISTS idx_articles_published ON articles(published);
+CREATE INDEX IF NOT EXISTS idx_articles_published_created_at_id
+    ON articles (created_at DESC, id DESC)
+    WHERE published = true;
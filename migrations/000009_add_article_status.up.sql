@@ -0,0 +1,31 @@
+-- Жизненный цикл статьи: draft -> in_review -> published -> archived.
+-- Допустимые переходы проверяются в Go (repository.CheckArticleTransition).
+ALTER TABLE articles
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'in_review', 'published', 'archived')),
+    ADD COLUMN published_at TIMESTAMP;
+
+-- Точное время публикации неизвестно, ближайшая оценка — последнее изменение
+UPDATE articles SET status = 'published', published_at = updated_at WHERE published;
+
+DROP INDEX IF EXISTS idx_articles_published_created_at_id;
+DROP INDEX IF EXISTS idx_articles_published;
+ALTER TABLE articles DROP COLUMN published;
+
+CREATE INDEX IF NOT EXISTS idx_articles_status ON articles(status);
+CREATE INDEX IF NOT EXISTS idx_articles_published_created_at_id
+    ON articles (created_at DESC, id DESC)
+    WHERE status = 'published';
+
+-- История переходов: кто и когда менял статус статьи
+CREATE TABLE IF NOT EXISTS article_status_transitions (
+    id           SERIAL PRIMARY KEY,
+    article_id   INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    from_status  VARCHAR(16) NOT NULL,
+    to_status    VARCHAR(16) NOT NULL,
+    actor_id     INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_article_status_transitions_article_id
+    ON article_status_transitions(article_id, created_at, id);
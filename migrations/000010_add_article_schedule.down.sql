@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_articles_unpublish_at;
+DROP INDEX IF EXISTS idx_articles_publish_at;
+
+ALTER TABLE articles
+    DROP CONSTRAINT IF EXISTS articles_schedule_order,
+    DROP COLUMN IF EXISTS unpublish_at,
+    DROP COLUMN IF EXISTS publish_at;
@@ -0,0 +1,16 @@
+-- Отложенная публикация и автоматическое снятие с публикации.
+-- TIMESTAMPTZ: время задают клиенты из разных часовых поясов, а планировщик
+-- сравнивает его с NOW() независимо от часового пояса сервера.
+ALTER TABLE articles
+    ADD COLUMN publish_at TIMESTAMPTZ,
+    ADD COLUMN unpublish_at TIMESTAMPTZ,
+    ADD CONSTRAINT articles_schedule_order
+        CHECK (publish_at IS NULL OR unpublish_at IS NULL OR unpublish_at > publish_at);
+
+-- Планировщик выбирает только строки с заданным временем
+CREATE INDEX IF NOT EXISTS idx_articles_publish_at
+    ON articles (publish_at, id)
+    WHERE publish_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_articles_unpublish_at
+    ON articles (unpublish_at, id)
+    WHERE unpublish_at IS NOT NULL;
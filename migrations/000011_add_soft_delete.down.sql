@@ -0,0 +1,14 @@
+-- Строки из корзины удаляются окончательно: без deleted_at их нельзя отличить
+DELETE FROM articles WHERE deleted_at IS NOT NULL;
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_articles_published_created_at_id;
+CREATE INDEX idx_articles_published_created_at_id
+    ON articles (created_at DESC, id DESC)
+    WHERE status = 'published';
+
+DROP INDEX IF EXISTS idx_articles_deleted_at;
+DROP INDEX IF EXISTS idx_users_deleted_at;
+
+ALTER TABLE articles DROP COLUMN deleted_with_author, DROP COLUMN deleted_at;
+ALTER TABLE users DROP COLUMN deleted_at;
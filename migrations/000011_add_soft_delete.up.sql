@@ -0,0 +1,18 @@
+-- Мягкое удаление: строки с deleted_at попадают в корзину и не видны обычным
+-- запросам, пока их не восстановят или не удалит окончательно очистка корзины.
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+
+-- deleted_with_author отличает статьи, попавшие в корзину вместе с автором:
+-- только они восстанавливаются при восстановлении пользователя.
+ALTER TABLE articles
+    ADD COLUMN deleted_at TIMESTAMP,
+    ADD COLUMN deleted_with_author BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_articles_deleted_at ON articles (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Ленты опубликованных статей не включают корзину
+DROP INDEX IF EXISTS idx_articles_published_created_at_id;
+CREATE INDEX idx_articles_published_created_at_id
+    ON articles (created_at DESC, id DESC)
+    WHERE status = 'published' AND deleted_at IS NULL;
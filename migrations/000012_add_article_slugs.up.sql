@@ -0,0 +1,65 @@
+-- Человекочитаемые адреса статей. articles.slug — текущий (канонический) слаг,
+-- article_slugs — все слаги, когда-либо выданные статье: старые продолжают
+-- разрешаться в статью после смены заголовка и не достаются другим статьям.
+ALTER TABLE articles ADD COLUMN slug TEXT;
+
+-- Слаги для существующих статей: та же транслитерация, что в пакете slug
+-- (без обрезки по границе слова). Первая статья с данной основой получает ее
+-- как есть, остальные — суффикс -id, а если и он занят (например, основой
+-- статьи "Go 42"), то еще -2, -3, как pickSlug в Go.
+CREATE TEMP TABLE article_slug_backfill ON COMMIT DROP AS
+WITH base AS (
+    SELECT id, COALESCE(NULLIF(trim(BOTH '-' FROM left(regexp_replace(
+        translate(
+            replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(replace(
+                lower(title),
+                'щ', 'shch'), 'ё', 'yo'), 'ж', 'zh'), 'х', 'kh'), 'ц', 'ts'), 'ч', 'ch'),
+                'ш', 'sh'), 'ю', 'yu'), 'я', 'ya'), 'ї', 'yi'), 'є', 'ye'),
+            -- ъ и ь не имеют пары во второй строке и удаляются
+            'абвгдезийклмнопрстуфыэіґъь', 'abvgdeziyklmnoprstufyeig'),
+        '[^a-z0-9]+', '-', 'g'), 80)), ''), 'article') AS slug
+    FROM articles
+)
+SELECT id, slug, row_number() OVER (PARTITION BY slug ORDER BY id) AS n
+FROM base;
+
+UPDATE articles a
+SET slug = b.slug
+FROM article_slug_backfill b
+WHERE a.id = b.id AND b.n = 1;
+
+-- Основы уже различны; ограничение заодно дает индекс для проверок ниже,
+-- а NULL у еще не получивших слаг статей ему не мешает
+ALTER TABLE articles ADD CONSTRAINT articles_slug_key UNIQUE (slug);
+
+DO $$
+DECLARE
+    r record;
+    with_id text;
+    candidate text;
+    attempt integer;
+BEGIN
+    FOR r IN SELECT id, slug FROM article_slug_backfill WHERE n > 1 ORDER BY id LOOP
+        with_id := r.slug || '-' || r.id;
+        candidate := with_id;
+        attempt := 1;
+        WHILE EXISTS (SELECT 1 FROM articles WHERE slug = candidate) LOOP
+            attempt := attempt + 1;
+            candidate := with_id || '-' || attempt;
+        END LOOP;
+        UPDATE articles SET slug = candidate WHERE id = r.id;
+    END LOOP;
+END $$;
+
+ALTER TABLE articles ALTER COLUMN slug SET NOT NULL;
+
+CREATE TABLE IF NOT EXISTS article_slugs (
+    slug TEXT PRIMARY KEY,
+    article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_slugs_article_id ON article_slugs (article_id);
+
+INSERT INTO article_slugs (slug, article_id)
+SELECT slug, id FROM articles;
@@ -0,0 +1,4 @@
+ALTER TABLE articles
+    DROP COLUMN IF EXISTS render_version,
+    DROP COLUMN IF EXISTS content_toc,
+    DROP COLUMN IF EXISTS content_html;
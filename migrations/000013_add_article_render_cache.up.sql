@@ -0,0 +1,7 @@
+-- Кэш отрендеренного Markdown. Заполняется при первом чтении
+-- (ArticleRepository.GetRendered) и сбрасывается в NULL, когда меняется
+-- content. render_version — render.Version, с которой построен кэш.
+ALTER TABLE articles
+    ADD COLUMN content_html TEXT,
+    ADD COLUMN content_toc JSONB,
+    ADD COLUMN render_version INTEGER;
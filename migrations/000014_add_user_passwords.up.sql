@@ -0,0 +1,3 @@
+-- Хэш пароля в формате PHC ($argon2id$...) или bcrypt ($2a$...). NULL —
+-- пароль не задан: такой пользователь не может войти.
+ALTER TABLE users ADD COLUMN password_hash TEXT;
@@ -0,0 +1,18 @@
+-- Сессии входа. token_hash — SHA-256 от случайного токена клиента: утечка
+-- таблицы не дает войти. previous_token_hash — токен до последней ротации;
+-- его повторное предъявление означает кражу, и сессия отзывается.
+CREATE TABLE IF NOT EXISTS sessions (
+    id                  SERIAL PRIMARY KEY,
+    user_id             INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash          BYTEA NOT NULL UNIQUE,
+    previous_token_hash BYTEA UNIQUE,
+    user_agent          TEXT NOT NULL DEFAULT '',
+    ip                  TEXT NOT NULL DEFAULT '',
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_seen_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at          TIMESTAMPTZ NOT NULL,
+    idle_expires_at     TIMESTAMPTZ NOT NULL,
+    revoked_at          TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
@@ -0,0 +1,5 @@
+-- Роль пользователя для проверки прав (пакет policy). Существующие
+-- пользователи и новые без явной роли становятся авторами.
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'author'
+    CHECK (role IN ('reader', 'author', 'editor', 'admin'));
@@ -0,0 +1,21 @@
+-- Глобальную уникальность нельзя вернуть, пока в корзине есть пользователи
+-- с адресом, занятым другим пользователем. Откат не удаляет их молча: такие
+-- записи нужно сначала очистить из корзины или сменить им адрес вручную.
+DO $$
+DECLARE
+    conflicts integer;
+BEGIN
+    SELECT count(*) INTO conflicts
+    FROM users t
+    WHERE t.deleted_at IS NOT NULL AND EXISTS (
+        SELECT 1 FROM users o WHERE o.email = t.email AND o.id <> t.id
+    );
+    IF conflicts > 0 THEN
+        RAISE EXCEPTION 'cannot restore users_email_key: % trashed users share an email with another user', conflicts
+            USING HINT = 'purge these users from the trash or change their email, then retry';
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS idx_users_email;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+CREATE INDEX idx_users_email ON users(email);
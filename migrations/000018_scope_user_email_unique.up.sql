@@ -0,0 +1,6 @@
+-- Email уникален только среди пользователей вне корзины: удаленный
+-- пользователь не мешает зарегистрироваться с его адресом. Восстановление
+-- пользователя, чей адрес уже занят, нарушает индекс.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX idx_users_email ON users (email) WHERE deleted_at IS NULL;
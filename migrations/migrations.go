@@ -0,0 +1,8 @@
+// Package migrations встраивает SQL-миграции в бинарник. Файлы именуются в
+// формате golang-migrate: NNNNNN_name.up.sql и NNNNNN_name.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -2,13 +2,45 @@ package models
 
 import "time"
 
+type ArticleStatus string
+
+const (
+	ArticleDraft     ArticleStatus = "draft"
+	ArticleInReview  ArticleStatus = "in_review"
+	ArticlePublished ArticleStatus = "published"
+	ArticleArchived  ArticleStatus = "archived"
+)
+
 type Article struct {
-	ID        int       `db:"id"`
-	Title     string    `db:"title"`
-	Content   string    `db:"content"`
-	AuthorID  int       `db:"author_id"`
-	Published bool      `db:"published"`
-	Views     int       `db:"views"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID       int           `db:"id" json:"id"`
+	Title    string        `db:"title" json:"title"`
+	Slug     string        `db:"slug" json:"slug"` // канонический адрес, выдается по заголовку
+	Content  string        `db:"content" json:"content"`
+	AuthorID int           `db:"author_id" json:"author_id"`
+	Status   ArticleStatus `db:"status" json:"status"`
+	// PublishedAt — время последней публикации; nil, если статья не публиковалась
+	PublishedAt *time.Time `db:"published_at" json:"published_at"`
+	// PublishAt и UnpublishAt — запланированные публикация и снятие с публикации
+	PublishAt   *time.Time `db:"publish_at" json:"publish_at"`
+	UnpublishAt *time.Time `db:"unpublish_at" json:"unpublish_at"`
+	Views       int        `db:"views" json:"views"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+	// DeletedAt — время перемещения в корзину; заполняется только в списках корзины
+	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+}
+
+func (a *Article) IsPublished() bool {
+	return a.Status == ArticlePublished
+}
+
+// ArticleTransition — запись истории смены статуса статьи
+type ArticleTransition struct {
+	ID         int           `db:"id" json:"id"`
+	ArticleID  int           `db:"article_id" json:"article_id"`
+	FromStatus ArticleStatus `db:"from_status" json:"from_status"`
+	ToStatus   ArticleStatus `db:"to_status" json:"to_status"`
+	// ActorID — кто выполнил переход; nil, если неизвестно
+	ActorID   *int      `db:"actor_id" json:"actor_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
 }
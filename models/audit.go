@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditAction — вид изменения в журнале аудита
+type AuditAction string
+
+const (
+	AuditCreate      AuditAction = "create"
+	AuditUpdate      AuditAction = "update"
+	AuditDelete      AuditAction = "delete"
+	AuditRestore     AuditAction = "restore"
+	AuditPurge       AuditAction = "purge"
+	AuditPublish     AuditAction = "publish"
+	AuditTransition  AuditAction = "transition"
+	AuditSchedule    AuditAction = "schedule"
+	AuditSetRole     AuditAction = "set_role"
+	AuditSetPassword AuditAction = "set_password"
+)
+
+// AuditEntity — тип измененной сущности
+type AuditEntity string
+
+const (
+	AuditUserEntity    AuditEntity = "user"
+	AuditArticleEntity AuditEntity = "article"
+)
+
+// Valid сообщает, что тип сущности известен
+func (e AuditEntity) Valid() bool {
+	return e == AuditUserEntity || e == AuditArticleEntity
+}
+
+// AuditEvent — запись журнала аудита. Before и After — JSON-снимки
+// измененных полей до и после операции; у создания нет Before, у удаления —
+// After. ActorID пуст для фоновых задач.
+type AuditEvent struct {
+	ID         int             `db:"id" json:"id"`
+	ActorID    *int            `db:"actor_id" json:"actor_id"`
+	Action     AuditAction     `db:"action" json:"action"`
+	EntityType AuditEntity     `db:"entity_type" json:"entity_type"`
+	EntityID   int             `db:"entity_id" json:"entity_id"`
+	Before     json.RawMessage `db:"before" json:"before,omitempty"`
+	After      json.RawMessage `db:"after" json:"after,omitempty"`
+	RequestID  string          `db:"request_id" json:"request_id,omitempty"`
+	IP         string          `db:"ip" json:"ip,omitempty"`
+	UserAgent  string          `db:"user_agent" json:"user_agent,omitempty"`
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Category — рубрика статьи. В отличие от тегов, у статьи не больше одной
+// рубрики, а список рубрик ведут редакторы.
+type Category struct {
+	ID        int       `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	Slug      string    `db:"slug" json:"slug"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+type CommentStatus string
+
+const (
+	CommentPending  CommentStatus = "pending"
+	CommentApproved CommentStatus = "approved"
+	CommentRejected CommentStatus = "rejected"
+	CommentDeleted  CommentStatus = "deleted"
+)
+
+type Comment struct {
+	ID        int           `db:"id" json:"id"`
+	ArticleID int           `db:"article_id" json:"article_id"`
+	AuthorID  int           `db:"author_id" json:"author_id"`
+	ParentID  *int          `db:"parent_id" json:"parent_id"`
+	Body      string        `db:"body" json:"body"`
+	Status    CommentStatus `db:"status" json:"status"`
+	// Depth — уровень вложенности в ветке (0 для корневых), заполняется при чтении ветки
+	Depth     int       `db:"-" json:"depth"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// ArticleRevision — неизменяемый снимок заголовка и текста статьи после сохранения
+type ArticleRevision struct {
+	ID        int    `db:"id" json:"id"`
+	ArticleID int    `db:"article_id" json:"article_id"`
+	Revision  int    `db:"revision" json:"revision"`
+	Title     string `db:"title" json:"title"`
+	Content   string `db:"content" json:"content"`
+	// EditorID — кто сохранил ревизию; nil, если неизвестно
+	EditorID  *int      `db:"editor_id" json:"editor_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
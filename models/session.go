@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Session — сессия входа. Сам токен хранится только в виде хэша и в модель
+// не попадает.
+type Session struct {
+	ID        int       `db:"id" json:"id"`
+	UserID    int       `db:"user_id" json:"user_id"`
+	UserAgent string    `db:"user_agent" json:"user_agent"`
+	IP        string    `db:"ip" json:"ip"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	// LastSeenAt обновляется не чаще раза в минуту
+	LastSeenAt time.Time `db:"last_seen_at" json:"last_seen_at"`
+	// ExpiresAt — предельный срок жизни, IdleExpiresAt — истечение без активности
+	ExpiresAt     time.Time  `db:"expires_at" json:"expires_at"`
+	IdleExpiresAt time.Time  `db:"idle_expires_at" json:"idle_expires_at"`
+	RevokedAt     *time.Time `db:"revoked_at" json:"revoked_at,omitempty"`
+}
+
+// Active сообщает, можно ли пользоваться сессией в момент now
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt) && now.Before(s.IdleExpiresAt)
+}
@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+type Tag struct {
+	ID        int       `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
@@ -2,10 +2,40 @@ package models
 
 import "time"
 
+// Role определяет, что пользователь может делать со статьями; права
+// проверяет пакет policy
+type Role string
+
+const (
+	// RoleReader читает опубликованное и комментирует
+	RoleReader Role = "reader"
+	// RoleAuthor пишет свои статьи и отправляет их на ревью
+	RoleAuthor Role = "author"
+	// RoleEditor правит, публикует и удаляет любые статьи, модерирует комментарии
+	RoleEditor Role = "editor"
+	// RoleAdmin вдобавок управляет пользователями и их ролями
+	RoleAdmin Role = "admin"
+)
+
+// DefaultRole получают пользователи, созданные без явной роли
+const DefaultRole = RoleAuthor
+
+// Valid сообщает, известна ли роль
+func (r Role) Valid() bool {
+	switch r {
+	case RoleReader, RoleAuthor, RoleEditor, RoleAdmin:
+		return true
+	}
+	return false
+}
+
 type User struct {
-	ID        int       `db:"id"`
-	Email     string    `db:"email"`
-	Name      string    `db:"name"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID        int       `db:"id" json:"id"`
+	Email     string    `db:"email" json:"email"`
+	Name      string    `db:"name" json:"name"`
+	Role      Role      `db:"role" json:"role"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	// DeletedAt — время перемещения в корзину; заполняется только в списках корзины
+	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
 }
@@ -0,0 +1,113 @@
+// Package policy решает, что пользователь может делать со статьями,
+// комментариями, тегами и другими пользователями. Решения зависят только от
+// роли пользователя и владельца объекта; nil означает анонимного посетителя.
+//
+//	reader  — читает опубликованное, комментирует
+//	author  — вдобавок пишет свои статьи, правит и удаляет их, отправляет на ревью
+//	editor  — правит, публикует и удаляет любые статьи, модерирует комментарии и теги
+//	admin   — вдобавок управляет пользователями и ролями
+package policy
+
+import (
+	"errors"
+	"go-articles-app/models"
+)
+
+// ErrForbidden — у пользователя нет права на действие
+var ErrForbidden = errors.New("forbidden")
+
+// Action — действие со статьей
+type Action string
+
+const (
+	// View — чтение статьи: опубликованную видят все, остальные — владелец и редакторы
+	View Action = "view"
+	// Edit — правка текста, тегов и восстановление ревизий
+	Edit Action = "edit"
+	// Reassign — смена автора статьи
+	Reassign Action = "reassign"
+	// Publish — публикация, архивирование и расписание публикаций
+	Publish Action = "publish"
+	// Delete — перемещение в корзину
+	Delete Action = "delete"
+	// Restore — возврат из корзины
+	Restore Action = "restore"
+)
+
+// rank упорядочивает роли: каждая следующая включает права предыдущей
+var rank = map[models.Role]int{
+	models.RoleReader: 1,
+	models.RoleAuthor: 2,
+	models.RoleEditor: 3,
+	models.RoleAdmin:  4,
+}
+
+// AtLeast сообщает, что у пользователя роль не ниже role
+func AtLeast(u *models.User, role models.Role) bool {
+	return u != nil && rank[u.Role] >= rank[role]
+}
+
+// owns — пользователь автор статьи и все еще может писать
+func owns(u *models.User, a *models.Article) bool {
+	return AtLeast(u, models.RoleAuthor) && a.AuthorID == u.ID
+}
+
+// CanArticle решает, может ли u выполнить action над статьей a
+func CanArticle(u *models.User, action Action, a *models.Article) bool {
+	switch action {
+	case View:
+		return a.IsPublished() || owns(u, a) || AtLeast(u, models.RoleEditor)
+	case Edit, Delete:
+		return owns(u, a) || AtLeast(u, models.RoleEditor)
+	case Reassign, Publish, Restore:
+		return AtLeast(u, models.RoleEditor)
+	}
+	return false
+}
+
+// CanCreateArticle — автор пишет от своего имени, редактор — от имени любого
+func CanCreateArticle(u *models.User, authorID int) bool {
+	if AtLeast(u, models.RoleEditor) {
+		return true
+	}
+	return AtLeast(u, models.RoleAuthor) && u.ID == authorID
+}
+
+// CanTransition решает, может ли u перевести статью a в статус to. Автор сам
+// отправляет черновик на ревью и забирает его обратно; остальные переходы —
+// редакторские.
+func CanTransition(u *models.User, a *models.Article, to models.ArticleStatus) bool {
+	if AtLeast(u, models.RoleEditor) {
+		return true
+	}
+	if !owns(u, a) {
+		return false
+	}
+	return (a.Status == models.ArticleDraft && to == models.ArticleInReview) ||
+		(a.Status == models.ArticleInReview && to == models.ArticleDraft)
+}
+
+// CanComment — комментировать может любой вошедший пользователь от своего имени
+func CanComment(u *models.User, authorID int) bool {
+	return u != nil && (u.ID == authorID || AtLeast(u, models.RoleEditor))
+}
+
+// CanDeleteComment — свой комментарий удаляет автор, любой — редактор
+func CanDeleteComment(u *models.User, c *models.Comment) bool {
+	return u != nil && (u.ID == c.AuthorID || AtLeast(u, models.RoleEditor))
+}
+
+// CanModerate — модерация комментариев, управление тегами, корзина статей
+func CanModerate(u *models.User) bool {
+	return AtLeast(u, models.RoleEditor)
+}
+
+// CanManageUser — профиль меняет и удаляет сам пользователь или администратор
+func CanManageUser(u *models.User, userID int) bool {
+	return u != nil && (u.ID == userID || AtLeast(u, models.RoleAdmin))
+}
+
+// CanAdminister — создание пользователей, смена ролей, корзина пользователей
+func CanAdminister(u *models.User) bool {
+	return AtLeast(u, models.RoleAdmin)
+}
@@ -0,0 +1,121 @@
+package policy
+
+import (
+	"go-articles-app/models"
+	"testing"
+)
+
+var (
+	reader = &models.User{ID: 1, Role: models.RoleReader}
+	author = &models.User{ID: 2, Role: models.RoleAuthor}
+	other  = &models.User{ID: 3, Role: models.RoleAuthor}
+	editor = &models.User{ID: 4, Role: models.RoleEditor}
+	admin  = &models.User{ID: 5, Role: models.RoleAdmin}
+	// demoted — бывший автор статьи, ставший читателем
+	demoted = &models.User{ID: 6, Role: models.RoleReader}
+)
+
+func TestCanArticle(t *testing.T) {
+	draft := &models.Article{AuthorID: author.ID, Status: models.ArticleDraft}
+	published := &models.Article{AuthorID: author.ID, Status: models.ArticlePublished}
+	demotedDraft := &models.Article{AuthorID: demoted.ID, Status: models.ArticleDraft}
+
+	tests := []struct {
+		name    string
+		user    *models.User
+		action  Action
+		article *models.Article
+		want    bool
+	}{
+		{"anonymous views published", nil, View, published, true},
+		{"anonymous views draft", nil, View, draft, false},
+		{"reader views draft", reader, View, draft, false},
+		{"owner views draft", author, View, draft, true},
+		{"other author views draft", other, View, draft, false},
+		{"editor views draft", editor, View, draft, true},
+
+		{"owner edits", author, Edit, published, true},
+		{"other author edits", other, Edit, published, false},
+		{"reader edits", reader, Edit, published, false},
+		{"editor edits", editor, Edit, published, true},
+		{"demoted owner edits", demoted, Edit, demotedDraft, false},
+		{"anonymous edits", nil, Edit, published, false},
+
+		{"owner deletes", author, Delete, draft, true},
+		{"other author deletes", other, Delete, draft, false},
+		{"admin deletes", admin, Delete, draft, true},
+
+		{"owner reassigns", author, Reassign, draft, false},
+		{"editor reassigns", editor, Reassign, draft, true},
+		{"owner publishes", author, Publish, draft, false},
+		{"editor publishes", editor, Publish, draft, true},
+		{"owner restores", author, Restore, draft, false},
+		{"admin restores", admin, Restore, draft, true},
+		{"unknown action", admin, Action("fly"), draft, false},
+	}
+	for _, tt := range tests {
+		if got := CanArticle(tt.user, tt.action, tt.article); got != tt.want {
+			t.Errorf("%s: CanArticle = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCanTransition(t *testing.T) {
+	draft := &models.Article{AuthorID: author.ID, Status: models.ArticleDraft}
+	review := &models.Article{AuthorID: author.ID, Status: models.ArticleInReview}
+
+	tests := []struct {
+		name    string
+		user    *models.User
+		article *models.Article
+		to      models.ArticleStatus
+		want    bool
+	}{
+		{"owner submits for review", author, draft, models.ArticleInReview, true},
+		{"owner withdraws from review", author, review, models.ArticleDraft, true},
+		{"owner publishes", author, review, models.ArticlePublished, false},
+		{"owner archives draft", author, draft, models.ArticleArchived, false},
+		{"other author submits", other, draft, models.ArticleInReview, false},
+		{"editor publishes", editor, review, models.ArticlePublished, true},
+	}
+	for _, tt := range tests {
+		if got := CanTransition(tt.user, tt.article, tt.to); got != tt.want {
+			t.Errorf("%s: CanTransition = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCanOthers(t *testing.T) {
+	comment := &models.Comment{AuthorID: reader.ID}
+
+	tests := []struct {
+		name      string
+		got, want bool
+	}{
+		{"author creates own article", CanCreateArticle(author, author.ID), true},
+		{"author creates for another", CanCreateArticle(author, other.ID), false},
+		{"reader creates article", CanCreateArticle(reader, reader.ID), false},
+		{"editor creates for another", CanCreateArticle(editor, author.ID), true},
+
+		{"reader comments as self", CanComment(reader, reader.ID), true},
+		{"reader comments as another", CanComment(reader, author.ID), false},
+		{"anonymous comments", CanComment(nil, 0), false},
+		{"comment author deletes", CanDeleteComment(reader, comment), true},
+		{"other user deletes comment", CanDeleteComment(author, comment), false},
+		{"editor deletes comment", CanDeleteComment(editor, comment), true},
+
+		{"author moderates", CanModerate(author), false},
+		{"editor moderates", CanModerate(editor), true},
+		{"user manages self", CanManageUser(reader, reader.ID), true},
+		{"editor manages another", CanManageUser(editor, reader.ID), false},
+		{"admin manages another", CanManageUser(admin, reader.ID), true},
+		{"editor administers", CanAdminister(editor), false},
+		{"admin administers", CanAdminister(admin), true},
+		{"unknown role", AtLeast(&models.User{Role: "owner"}, models.RoleReader), false},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
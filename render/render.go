@@ -0,0 +1,164 @@
+// Package render превращает Markdown-текст статьи в безопасный HTML и
+// оглавление. Поддерживается CommonMark с таблицами, блоками кода с языком
+// и сносками; результат всегда проходит через санитайзер, поэтому его можно
+// вставлять в страницу без дополнительной обработки.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"go-articles-app/slug"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// Version меняется при любом изменении вывода (расширения, политика
+// санитайзера, схема якорей): кэш с другой версией считается устаревшим.
+const Version = 1
+
+// Heading — пункт оглавления. ID совпадает с атрибутом id заголовка в HTML
+// и строится из его текста так же, как слаг статьи, поэтому не меняется,
+// пока не меняется текст заголовка.
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
+// Document — результат рендеринга
+type Document struct {
+	HTML string    `json:"html"`
+	TOC  []Heading `json:"toc"`
+}
+
+// headingFallback — основа якоря для заголовка без букв и цифр
+const headingFallback = "section"
+
+var (
+	markdown = goldmark.New(
+		goldmark.WithExtensions(
+			// Выравнивание атрибутом align: style санитайзер вырезает
+			extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+			extension.Footnote,
+		),
+	)
+	policy = newPolicy()
+)
+
+// newPolicy — политика UGC, дополненная тем, что выводит goldmark:
+// якоря заголовков и сносок, классы языка у кода и разметка сносок
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[a-z0-9:-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6", "li", "sup")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^(footnotes|footnote-ref|footnote-backref)$`)).OnElements("div", "a")
+	p.AllowAttrs("role").Matching(regexp.MustCompile(`^doc-(endnotes|noteref|backlink)$`)).OnElements("div", "a")
+	p.AllowAttrs("align").Matching(regexp.MustCompile(`^(left|center|right)$`)).OnElements("th", "td")
+	return p
+}
+
+// Markdown рендерит текст статьи. Сырой HTML в исходнике не выводится,
+// а ссылки получают rel="nofollow".
+func Markdown(source string) (*Document, error) {
+	src := []byte(source)
+	root := markdown.Parser().Parse(text.NewReader(src))
+	toc := assignHeadingIDs(root, src)
+
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, src, root); err != nil {
+		return nil, fmt.Errorf("render markdown: %w", err)
+	}
+	return &Document{HTML: policy.Sanitize(buf.String()), TOC: toc}, nil
+}
+
+// assignHeadingIDs проставляет заголовкам id из их текста и собирает
+// оглавление. Повторяющиеся id получают суффиксы -2, -3, ... в порядке
+// следования заголовков.
+func assignHeadingIDs(root ast.Node, src []byte) []Heading {
+	toc := []Heading{}
+	used := make(map[string]bool)
+	_ = ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		title := plainText(h, src)
+		id := slug.Unique(slug.MakeOr(title, headingFallback), func(s string) bool { return used[s] })
+		used[id] = true
+		h.SetAttributeString("id", []byte(id))
+		toc = append(toc, Heading{Level: h.Level, Text: title, ID: id})
+		return ast.WalkSkipChildren, nil
+	})
+	return toc
+}
+
+// plainText собирает видимый текст узла без разметки
+func plainText(n ast.Node, src []byte) string {
+	var b strings.Builder
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := c.(type) {
+		case *ast.Text:
+			b.Write(t.Value(src))
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(t.Value)
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// Excerpt возвращает начало текста статьи без разметки: абзацы, заголовки и
+// ячейки таблиц через пробел, без блоков кода и сносок. Текст длиннее limit
+// символов обрезается по границе слова и получает многоточие.
+func Excerpt(source string, limit int) string {
+	src := []byte(source)
+	root := markdown.Parser().Parse(text.NewReader(src))
+
+	var parts []string
+	_ = ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock, *ast.HTMLBlock, *east.Footnote, *east.FootnoteList:
+			return ast.WalkSkipChildren, nil
+		case *ast.Paragraph, *ast.TextBlock, *ast.Heading, *east.TableCell:
+			if s := plainText(n, src); s != "" {
+				parts = append(parts, s)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return truncate(strings.Join(parts, " "), limit)
+}
+
+// truncate обрезает s до limit символов по границе слова
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if limit <= 0 || len(runes) <= limit {
+		return s
+	}
+	cut := string(runes[:limit])
+	if runes[limit] != ' ' {
+		if i := strings.LastIndexByte(cut, ' '); i > 0 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRight(cut, " ,.;:-—") + "…"
+}
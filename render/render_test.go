@@ -0,0 +1,82 @@
+package render
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMarkdownExtensions(t *testing.T) {
+	doc, err := Markdown("Text[^1]\n\n| a | b |\n|:--|--:|\n| 1 | 2 |\n\n```go\nfmt.Println(\"<b>\")\n```\n\n[^1]: Note.\n")
+	if err != nil {
+		t.Fatalf("Markdown: %v", err)
+	}
+	for _, want := range []string{
+		`<th align="left">a</th>`,
+		`<td align="right">2</td>`,
+		`<code class="language-go">fmt.Println(&#34;&lt;b&gt;&#34;)`,
+		`<sup id="fnref:1"><a href="#fn:1" class="footnote-ref" role="doc-noteref"`,
+		`<li id="fn:1">`,
+	} {
+		if !strings.Contains(doc.HTML, want) {
+			t.Errorf("HTML does not contain %s:\n%s", want, doc.HTML)
+		}
+	}
+}
+
+func TestMarkdownSanitizes(t *testing.T) {
+	doc, err := Markdown("<script>alert(1)</script>\n\n[link](javascript:alert(1)) <img src=x onerror=alert(1)> [ok](https://example.com)\n")
+	if err != nil {
+		t.Fatalf("Markdown: %v", err)
+	}
+	for _, bad := range []string{"<script", "javascript:", "onerror"} {
+		if strings.Contains(doc.HTML, bad) {
+			t.Errorf("HTML contains %s:\n%s", bad, doc.HTML)
+		}
+	}
+	if want := `<a href="https://example.com" rel="nofollow">ok</a>`; !strings.Contains(doc.HTML, want) {
+		t.Errorf("HTML does not contain %s:\n%s", want, doc.HTML)
+	}
+}
+
+func TestMarkdownTOC(t *testing.T) {
+	doc, err := Markdown("# Привет, **мир**\n\ntext\n\n## Детали\n\n## Детали\n\n### `code` & more\n\n## !!!\n")
+	if err != nil {
+		t.Fatalf("Markdown: %v", err)
+	}
+	want := []Heading{
+		{Level: 1, Text: "Привет, мир", ID: "privet-mir"},
+		{Level: 2, Text: "Детали", ID: "detali"},
+		{Level: 2, Text: "Детали", ID: "detali-2"},
+		{Level: 3, Text: "code & more", ID: "code-more"},
+		{Level: 2, Text: "!!!", ID: "section"},
+	}
+	if !slices.Equal(doc.TOC, want) {
+		t.Fatalf("TOC = %+v, want %+v", doc.TOC, want)
+	}
+	for _, h := range want {
+		if !strings.Contains(doc.HTML, ` id="`+h.ID+`"`) {
+			t.Errorf("HTML has no anchor %s:\n%s", h.ID, doc.HTML)
+		}
+	}
+}
+
+func TestMarkdownEmpty(t *testing.T) {
+	doc, err := Markdown("")
+	if err != nil {
+		t.Fatalf("Markdown: %v", err)
+	}
+	if doc.HTML != "" || doc.TOC == nil || len(doc.TOC) != 0 {
+		t.Fatalf("Markdown(\"\") = %+v, want empty HTML and empty TOC", doc)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	source := "# Заголовок\n\nПервый **абзац** со [ссылкой](https://example.com)[^1].\n\n```go\ncode()\n```\n\nВторой абзац.\n\n[^1]: Сноска.\n"
+	if got, want := Excerpt(source, 0), "Заголовок Первый абзац со ссылкой. Второй абзац."; got != want {
+		t.Fatalf("Excerpt = %q, want %q", got, want)
+	}
+	if got, want := Excerpt(source, 22), "Заголовок Первый абзац…"; got != want {
+		t.Fatalf("Excerpt(22) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,25 @@
+package repository
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor помечает контекст ID пользователя, от имени которого выполняется
+// операция. Репозитории записывают его как автора правки.
+func WithActor(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// ActorFromContext возвращает пользователя, сохраненного WithActor
+func ActorFromContext(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(actorKey{}).(int)
+	return id, ok && id > 0
+}
+
+// actorID возвращает пользователя из контекста или nil для колонок, допускающих NULL
+func actorID(ctx context.Context) *int {
+	if id, ok := ActorFromContext(ctx); ok {
+		return &id
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/models"
+)
+
+// FeedQuery выбирает опубликованные статьи для RSS/Atom-ленты. Нулевые
+// AuthorID и TagID означают отсутствие условия.
+type FeedQuery struct {
+	AuthorID int
+	TagID    int
+	Limit    int
+}
+
+// ListFeed возвращает опубликованные статьи с авторами, последние
+// опубликованные первыми
+func (r *ArticleRepository) ListFeed(ctx context.Context, q FeedQuery) ([]*ArticleWithAuthor, error) {
+	var b sqlBuilder
+	b.where("a.status = 'published'")
+	b.where("a.deleted_at IS NULL")
+	if q.AuthorID != 0 {
+		b.where("a.author_id = " + b.arg(q.AuthorID))
+	}
+	if q.TagID != 0 {
+		b.where("EXISTS (SELECT 1 FROM article_tags at WHERE at.article_id = a.id AND at.tag_id = " + b.arg(q.TagID) + ")")
+	}
+
+	query := `
+		SELECT a.id, a.title, a.slug, a.content, a.author_id, a.status, a.published_at, a.publish_at, a.unpublish_at, a.views, a.created_at, a.updated_at,
+			u.name, u.email
+		FROM articles a
+		JOIN users u ON u.id = a.author_id` + b.whereClause() + `
+		ORDER BY COALESCE(a.published_at, a.created_at) DESC, a.id DESC
+		LIMIT ` + b.arg(PageRequest{Limit: q.Limit}.PageSize())
+
+	rows, err := r.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query feed: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*ArticleWithAuthor{}
+	for rows.Next() {
+		item := &ArticleWithAuthor{Article: &models.Article{}}
+		err := rows.Scan(
+			&item.Article.ID,
+			&item.Article.Title,
+			&item.Article.Slug,
+			&item.Article.Content,
+			&item.Article.AuthorID,
+			&item.Article.Status,
+			&item.Article.PublishedAt,
+			&item.Article.PublishAt,
+			&item.Article.UnpublishAt,
+			&item.Article.Views,
+			&item.Article.CreatedAt,
+			&item.Article.UpdatedAt,
+			&item.AuthorName,
+			&item.AuthorEmail,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan feed article: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-articles-app/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ArticleFilter описывает условия выборки статей. Нулевые значения полей
+// означают отсутствие условия; заданные условия объединяются через AND.
+type ArticleFilter struct {
+	AuthorIDs     []int                  // author_id входит в набор
+	Published     *bool                  // только опубликованные (true) или все неопубликованные (false)
+	Statuses      []models.ArticleStatus // status входит в набор
+	CreatedFrom   time.Time              // created_at >= CreatedFrom
+	CreatedTo     time.Time              // created_at < CreatedTo
+	MinViews      int                    // views >= MinViews
+	TitleContains string                 // подстрока заголовка без учета регистра
+}
+
+type ArticleSortField string
+
+const (
+	SortByCreatedAt ArticleSortField = "created_at"
+	SortByUpdatedAt ArticleSortField = "updated_at"
+	SortByViews     ArticleSortField = "views"
+)
+
+// ArticleQuery — фильтр, сортировка и окно выборки для ArticleRepository.Find.
+// По умолчанию сортировка по created_at по убыванию.
+type ArticleQuery struct {
+	Filter    ArticleFilter
+	SortBy    ArticleSortField
+	Ascending bool
+	Limit     int
+	Offset    int
+}
+
+// ArticleList — страница результатов Find и общее число статей под фильтром
+type ArticleList struct {
+	Articles []*models.Article `json:"items"`
+	Total    int               `json:"total"`
+}
+
+// Normalize проверяет запрос и подставляет значения по умолчанию
+func (q ArticleQuery) Normalize() (ArticleQuery, error) {
+	switch q.SortBy {
+	case "":
+		q.SortBy = SortByCreatedAt
+	case SortByCreatedAt, SortByUpdatedAt, SortByViews:
+	default:
+		return q, fmt.Errorf("%w: unknown sort field %q", ErrInvalidQuery, q.SortBy)
+	}
+	if q.Offset < 0 {
+		return q, fmt.Errorf("%w: negative offset", ErrInvalidQuery)
+	}
+	if q.Filter.MinViews < 0 {
+		return q, fmt.Errorf("%w: negative min views", ErrInvalidQuery)
+	}
+	if !q.Filter.CreatedFrom.IsZero() && !q.Filter.CreatedTo.IsZero() && !q.Filter.CreatedFrom.Before(q.Filter.CreatedTo) {
+		return q, fmt.Errorf("%w: empty created_at range", ErrInvalidQuery)
+	}
+	for _, st := range q.Filter.Statuses {
+		if _, ok := articleTransitions[st]; !ok {
+			return q, fmt.Errorf("%w: unknown article status %q", ErrInvalidQuery, st)
+		}
+	}
+	q.Limit = PageRequest{Limit: q.Limit}.PageSize()
+	return q, nil
+}
+
+// sqlBuilder накапливает условия WHERE и аргументы запроса.
+// Пользовательские значения всегда передаются через плейсхолдеры.
+type sqlBuilder struct {
+	conds []string
+	args  []any
+}
+
+func (b *sqlBuilder) arg(v any) string {
+	b.args = append(b.args, v)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+func (b *sqlBuilder) where(cond string) {
+	b.conds = append(b.conds, cond)
+}
+
+func (b *sqlBuilder) whereClause() string {
+	if len(b.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.conds, " AND ")
+}
+
+func (f ArticleFilter) build(b *sqlBuilder) {
+	if len(f.AuthorIDs) > 0 {
+		ids := make([]int64, len(f.AuthorIDs))
+		for i, id := range f.AuthorIDs {
+			ids[i] = int64(id)
+		}
+		b.where("author_id = ANY(" + b.arg(pq.Array(ids)) + "::int[])")
+	}
+	if f.Published != nil {
+		if *f.Published {
+			b.where("status = 'published'")
+		} else {
+			b.where("status <> 'published'")
+		}
+	}
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, len(f.Statuses))
+		for i, st := range f.Statuses {
+			statuses[i] = string(st)
+		}
+		b.where("status = ANY(" + b.arg(pq.Array(statuses)) + "::text[])")
+	}
+	if !f.CreatedFrom.IsZero() {
+		b.where("created_at >= " + b.arg(f.CreatedFrom))
+	}
+	if !f.CreatedTo.IsZero() {
+		b.where("created_at < " + b.arg(f.CreatedTo))
+	}
+	if f.MinViews > 0 {
+		b.where("views >= " + b.arg(f.MinViews))
+	}
+	if f.TitleContains != "" {
+		b.where("title ILIKE " + b.arg("%"+escapeLike(f.TitleContains)+"%"))
+	}
+}
+
+// escapeLike экранирует метасимволы LIKE (экранирующий символ по умолчанию — \)
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func (r *ArticleRepository) Find(ctx context.Context, q ArticleQuery) (*ArticleList, error) {
+	q, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	var b sqlBuilder
+	b.where("deleted_at IS NULL")
+	q.Filter.build(&b)
+	where := b.whereClause()
+
+	// Сортировка подставляется только из белого списка ArticleSortField
+	direction := "DESC"
+	if q.Ascending {
+		direction = "ASC"
+	}
+	orderBy := fmt.Sprintf(" ORDER BY %s %s, id %s", q.SortBy, direction, direction)
+
+	// Подсчет и выборка читают один снимок данных
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result := &ArticleList{}
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM articles`+where, b.args...).Scan(&result.Total); err != nil {
+		return nil, fmt.Errorf("failed to count articles: %w", err)
+	}
+
+	query := `
+		SELECT id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at
+		FROM articles` + where + orderBy +
+		` LIMIT ` + b.arg(q.Limit) + ` OFFSET ` + b.arg(q.Offset)
+
+	rows, err := tx.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query articles: %w", err)
+	}
+	defer rows.Close()
+
+	articles, err := scanArticles(rows)
+	if err != nil {
+		return nil, err
+	}
+	if articles == nil {
+		articles = []*models.Article{}
+	}
+	result.Articles = articles
+
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-articles-app/render"
+)
+
+// GetRendered возвращает содержание статьи в HTML с оглавлением. Результат
+// кэшируется в строке статьи; кэш сбрасывают Update и RestoreRevision при
+// смене содержания, а также смена render.Version.
+func (r *ArticleRepository) GetRendered(ctx context.Context, id int) (*render.Document, error) {
+	var (
+		content string
+		html    sql.NullString
+		toc     []byte
+		version sql.NullInt64
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT content, content_html, content_toc, render_version
+		FROM articles
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id).Scan(&content, &html, &toc, &version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("article")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get article content: %w", err)
+	}
+
+	if html.Valid && version.Int64 == render.Version {
+		doc := &render.Document{HTML: html.String}
+		if err := json.Unmarshal(toc, &doc.TOC); err != nil {
+			return nil, fmt.Errorf("failed to decode cached toc: %w", err)
+		}
+		return doc, nil
+	}
+
+	doc, err := render.Markdown(content)
+	if err != nil {
+		return nil, err
+	}
+	tocJSON, err := json.Marshal(doc.TOC)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode toc: %w", err)
+	}
+
+	// Кэш сохраняется, только если содержание не изменилось с момента чтения
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE articles
+		SET content_html = $1, content_toc = $2, render_version = $3
+		WHERE id = $4 AND content = $5
+	`, doc.HTML, tocJSON, render.Version, id, content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cache rendered content: %w", err)
+	}
+	return doc, nil
+}
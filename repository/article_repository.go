@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"go-articles-app/models"
 	"time"
@@ -16,55 +17,92 @@ func NewArticleRepository(db *sql.DB) *ArticleRepository {
 	return &ArticleRepository{db: db}
 }
 
+// Create добавляет статью и ее первую ревизию. Автором ревизии считается
+// пользователь из контекста (WithActor), а без него — автор статьи.
 func (r *ArticleRepository) Create(ctx context.Context, article *models.Article) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkActiveUser(ctx, tx, article.AuthorID, "author"); err != nil {
+		return err
+	}
+	article.Slug, err = pickSlug(ctx, tx, 0, article.Title)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO articles (title, content, author_id)
-		VALUES ($1, $2, $3)
-		RETURNING id, published, views, created_at, updated_at
+		INSERT INTO articles (title, slug, content, author_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at
 	`
-	err := r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		article.Title,
+		article.Slug,
 		article.Content,
 		article.AuthorID,
-		article.Published,
-		0,
-		article.CreatedAt,
-		article.UpdatedAt,
 	).Scan(&article.ID,
-		&article.Published,
+		&article.Status,
+		&article.PublishedAt,
+		&article.PublishAt,
+		&article.UnpublishAt,
 		&article.Views,
 		&article.CreatedAt,
 		&article.UpdatedAt,
 	)
 
 	if err != nil {
-		return fmt.Errorf("failed to create article: %w", err)
+		return fmt.Errorf("failed to create article: %w", pgError(err))
+	}
+	if err := recordSlug(ctx, tx, article.ID, article.Slug); err != nil {
+		return err
+	}
+
+	editorID := actorID(ctx)
+	if editorID == nil {
+		editorID = &article.AuthorID
+	}
+	if err := insertRevision(ctx, tx, article, editorID); err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, tx, models.AuditCreate, models.AuditArticleEntity, article.ID, nil, article); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
 	}
 	return nil
 }
 
 func (r *ArticleRepository) GetByID(ctx context.Context, id int) (*models.Article, error) {
-	query := `SELECT id, title, content, author_id, published, views, created_at, updated_at FROM articles WHERE id = $1`
+	query := `SELECT id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at FROM articles WHERE id = $1 AND deleted_at IS NULL`
 
 	article := &models.Article{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&article.ID,
 		&article.Title,
+		&article.Slug,
 		&article.Content,
 		&article.AuthorID,
-		&article.Published,
+		&article.Status,
+		&article.PublishedAt,
+		&article.PublishAt,
+		&article.UnpublishAt,
 		&article.Views,
 		&article.CreatedAt,
 		&article.UpdatedAt,
 	)
 
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("user not found")
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("article")
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to get article: %w", err)
 	}
 
 	return article, nil
@@ -72,9 +110,9 @@ func (r *ArticleRepository) GetByID(ctx context.Context, id int) (*models.Articl
 
 func (r *ArticleRepository) GetByAuthorID(ctx context.Context, authorID int) ([]*models.Article, error) {
 	query := `
-		SELECT id, title, content, author_id, published, views, created_at, updated_at
-		FROM articles 
-		WHERE author_id = $1
+		SELECT id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at
+		FROM articles
+		WHERE author_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 	rows, err := r.db.QueryContext(ctx, query, authorID)
@@ -89,9 +127,13 @@ func (r *ArticleRepository) GetByAuthorID(ctx context.Context, authorID int) ([]
 		err := rows.Scan(
 			&article.ID,
 			&article.Title,
+			&article.Slug,
 			&article.Content,
 			&article.AuthorID,
-			&article.Published,
+			&article.Status,
+			&article.PublishedAt,
+			&article.PublishAt,
+			&article.UnpublishAt,
 			&article.Views,
 			&article.CreatedAt,
 			&article.UpdatedAt,
@@ -111,9 +153,9 @@ func (r *ArticleRepository) GetByAuthorID(ctx context.Context, authorID int) ([]
 
 func (r *ArticleRepository) GetPublished(ctx context.Context) ([]*models.Article, error) {
 	query := `
-		SELECT id, title, content, author_id, published, views, created_at, updated_at
+		SELECT id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at
 		FROM articles
-		WHERE published = true
+		WHERE status = 'published' AND deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -129,9 +171,13 @@ func (r *ArticleRepository) GetPublished(ctx context.Context) ([]*models.Article
 		err := rows.Scan(
 			&article.ID,
 			&article.Title,
+			&article.Slug,
 			&article.Content,
 			&article.AuthorID,
-			&article.Published,
+			&article.Status,
+			&article.PublishedAt,
+			&article.PublishAt,
+			&article.UnpublishAt,
 			&article.Views,
 			&article.CreatedAt,
 			&article.UpdatedAt,
@@ -150,96 +196,90 @@ func (r *ArticleRepository) GetPublished(ctx context.Context) ([]*models.Article
 	return articles, nil
 }
 
+// Update сохраняет заголовок, текст и автора статьи и в той же транзакции
+// записывает новую ревизию с пользователем из контекста (WithActor) в качестве
+// автора правки. Статус меняется только через Transition. При смене заголовка
+// статья получает новый слаг, старый остается в истории; при смене
+// содержания сбрасывается кэш GetRendered.
 func (r *ArticleRepository) Update(ctx context.Context, article *models.Article) error {
-	query := `UPDATE articles SET 
-		title = $1, 
-		content = $2,
-		author_id = $3,
-		published = $4,
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkActiveUser(ctx, tx, article.AuthorID, "author"); err != nil {
+		return err
+	}
+
+	before, err := lockArticle(ctx, tx, article.ID)
+	if err != nil {
+		return err
+	}
+	oldSlug := before.Slug
+	article.Slug, err = reslug(ctx, tx, article.ID, oldSlug, before.Title, article.Title)
+	if err != nil {
+		return err
+	}
+
+	// Кэш рендеринга сбрасывается, если меняется содержание; справа от =
+	// в SET видны старые значения строки
+	query := `UPDATE articles SET
+		title = $1,
+		slug = $2,
+		content = $3,
+		content_html = CASE WHEN content = $3 THEN content_html END,
+		content_toc = CASE WHEN content = $3 THEN content_toc END,
+		render_version = CASE WHEN content = $3 THEN render_version END,
+		author_id = $4,
 		updated_at = $5
 		WHERE id = $6
+		RETURNING status, published_at, publish_at, unpublish_at
 	`
 
 	article.UpdatedAt = time.Now()
-	result, err := r.db.ExecContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		article.Title,
+		article.Slug,
 		article.Content,
 		article.AuthorID,
-		article.Published,
 		article.UpdatedAt,
 		article.ID,
-	)
-
-	if err != nil {
-		return fmt.Errorf("failed to update article: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
+	).Scan(&article.Status, &article.PublishedAt, &article.PublishAt, &article.UnpublishAt)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return fmt.Errorf("failed to update article: %w", pgError(err))
 	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("article not found")
+	if article.Slug != oldSlug {
+		if err := recordSlug(ctx, tx, article.ID, article.Slug); err != nil {
+			return err
+		}
 	}
 
-	return nil
-}
-
-func (r *ArticleRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM articles WHERE id = $1`
-
-	result, err := r.db.ExecContext(ctx, query, id)
-
-	if err != nil {
-		return fmt.Errorf("failed to delete article: %w", err)
+	if err := insertRevision(ctx, tx, article, actorID(ctx)); err != nil {
+		return err
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	after := *article
+	after.Views, after.CreatedAt = before.Views, before.CreatedAt
+	if err := recordAudit(ctx, tx, models.AuditUpdate, models.AuditArticleEntity, article.ID, before, &after); err != nil {
+		return err
 	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("article not found")
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
 	}
-
 	return nil
 }
 
+// Publish публикует статью переходом в статус published. Для уже
+// опубликованной статьи возвращается ErrAlreadyPublished.
 func (r *ArticleRepository) Publish(ctx context.Context, id int) error {
-	query := `UPDATE articles SET published = true, updated_at = NOW() WHERE id = $1 AND published = false`
-	result, err := r.db.ExecContext(ctx, query, id)
-
-	if err != nil {
-		return fmt.Errorf("failed to publish article: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("article not found")
-	}
-
-	if rowsAffected == 0 {
-		var exists bool
-		r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM articles WHERE id = $1)", id).Scan(&exists)
-		if !exists {
-			return fmt.Errorf("article not found")
-		}
-		return nil
-	}
-
-	return nil
+	_, err := r.Transition(ctx, id, models.ArticlePublished)
+	return err
 }
 
 func (r *ArticleRepository) IncrementViews(ctx context.Context, id int) error {
-	query := `UPDATE articles SET views = views + 1 WHERE id = $1`
+	query := `UPDATE articles SET views = views + 1 WHERE id = $1 AND deleted_at IS NULL`
 
 	result, err := r.db.ExecContext(ctx, query, id)
 
@@ -253,7 +293,7 @@ func (r *ArticleRepository) IncrementViews(ctx context.Context, id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("article not found")
+		return notFound("article")
 	}
 
 	return nil
@@ -265,7 +305,7 @@ func (r *ArticleRepository) CreateArticleWithAuthor(
 ) (*models.User, *models.Article, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error: %w", err)
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
 	defer func() {
@@ -278,52 +318,82 @@ func (r *ArticleRepository) CreateArticleWithAuthor(
 	}()
 
 	var user models.User
-	queryUser := `SELECT id, email, name, created_at, updated_at FROM users WHERE email = $1`
-	err = tx.QueryRowContext(ctx, queryUser, userEmail).Scan(&user.ID, &user.Email, &user.Name, &user.CreatedAt, &user.UpdatedAt)
+	created := false
+	queryUser := `SELECT id, email, name, role, created_at, updated_at FROM users WHERE email = $1 AND deleted_at IS NULL`
+	err = tx.QueryRowContext(ctx, queryUser, userEmail).Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 
-	if err == sql.ErrNoRows {
+	if errors.Is(err, sql.ErrNoRows) {
 
 		err = tx.QueryRowContext(
 			ctx,
 			`INSERT INTO users (email, name, created_at, updated_at)
 			VALUES ($1, $2, NOW(), NOW())
-			RETURNING id
+			RETURNING id, role, created_at, updated_at
 			`, userEmail, userName,
-		).Scan(&user.ID)
+		).Scan(&user.ID, &user.Role, &user.CreatedAt, &user.UpdatedAt)
 
 		if err != nil {
-			return nil, nil, fmt.Errorf("create user: %w", err)
+			return nil, nil, fmt.Errorf("create user: %w", pgError(err))
 		}
 
 		user.Name = userName
 		user.Email = userEmail
+		created = true
 	} else if err != nil {
 
 		return nil, nil, fmt.Errorf("check user: %w", err)
 	}
 
+	articleSlug, err := pickSlug(ctx, tx, 0, articleTitle)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var article models.Article
 	err = tx.QueryRowContext(
 		ctx,
-		`INSERT INTO articles (title, content, author_id, created_at, updated_at)
-		  VALUES ($1, $2, $3, NOW(), NOW()) RETURNING id, title, content, author_id, published, views, created_at, updated_at`,
-		articleTitle, articleContent, user.ID).Scan(
+		`INSERT INTO articles (title, slug, content, author_id, created_at, updated_at)
+		  VALUES ($1, $2, $3, $4, NOW(), NOW()) RETURNING id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at`,
+		articleTitle, articleSlug, articleContent, user.ID).Scan(
 		&article.ID,
 		&article.Title,
+		&article.Slug,
 		&article.Content,
 		&article.AuthorID,
-		&article.Published,
+		&article.Status,
+		&article.PublishedAt,
+		&article.PublishAt,
+		&article.UnpublishAt,
 		&article.Views,
 		&article.CreatedAt,
 		&article.UpdatedAt,
 	)
 
 	if err != nil {
-		return nil, nil, fmt.Errorf("create article: %w", err)
+		return nil, nil, fmt.Errorf("create article: %w", pgError(err))
+	}
+	if err = recordSlug(ctx, tx, article.ID, article.Slug); err != nil {
+		return nil, nil, err
+	}
+
+	editorID := actorID(ctx)
+	if editorID == nil {
+		editorID = &user.ID
+	}
+	if err = insertRevision(ctx, tx, &article, editorID); err != nil {
+		return nil, nil, err
+	}
+	if created {
+		if err = recordAudit(ctx, tx, models.AuditCreate, models.AuditUserEntity, user.ID, nil, &user); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err = recordAudit(ctx, tx, models.AuditCreate, models.AuditArticleEntity, article.ID, nil, &article); err != nil {
+		return nil, nil, err
 	}
 
 	if err = tx.Commit(); err != nil {
-		return nil, nil, fmt.Errorf("commit: %w", err)
+		return nil, nil, fmt.Errorf("commit: %w", pgError(err))
 	}
 
 	return &user, &article, nil
@@ -340,16 +410,20 @@ func (r *ArticleRepository) GetArticleWithAuthor(ctx context.Context, id int) (*
 	query := ` SELECT 	
 			articles.id, 
 			articles.title, 
+			articles.slug,
 			articles.content, 
 			articles.author_id,
-			articles.published,
+			articles.status,
+			articles.published_at,
+			articles.publish_at,
+			articles.unpublish_at,
 			articles.views,
 			articles.created_at,
 			articles.updated_at,
 			users.name,
 			users.email
 	      FROM articles JOIN users ON articles.author_id = users.id
-	      WHERE articles.id = $1`
+	      WHERE articles.id = $1 AND articles.deleted_at IS NULL`
 
 	var result ArticleWithAuthor
 	result.Article = &models.Article{}
@@ -357,9 +431,13 @@ func (r *ArticleRepository) GetArticleWithAuthor(ctx context.Context, id int) (*
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&result.Article.ID,
 		&result.Article.Title,
+		&result.Article.Slug,
 		&result.Article.Content,
 		&result.Article.AuthorID,
-		&result.Article.Published,
+		&result.Article.Status,
+		&result.Article.PublishedAt,
+		&result.Article.PublishAt,
+		&result.Article.UnpublishAt,
 		&result.Article.Views,
 		&result.Article.CreatedAt,
 		&result.Article.UpdatedAt,
@@ -367,8 +445,8 @@ func (r *ArticleRepository) GetArticleWithAuthor(ctx context.Context, id int) (*
 		&result.AuthorEmail,
 	)
 
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("article not found")
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("article")
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get article with author: %w", err)
@@ -377,3 +455,111 @@ func (r *ArticleRepository) GetArticleWithAuthor(ctx context.Context, id int) (*
 	return &result, nil
 
 }
+
+func (r *ArticleRepository) GetPublishedPage(ctx context.Context, page PageRequest) (*Page[*models.Article], error) {
+	return r.listPage(ctx, `status = 'published'`, nil, page)
+}
+
+func (r *ArticleRepository) GetByAuthorIDPage(ctx context.Context, authorID int, includeDrafts bool, page PageRequest) (*Page[*models.Article], error) {
+	where := `author_id = $1`
+	if !includeDrafts {
+		where += ` AND status = 'published'`
+	}
+	return r.listPage(ctx, where, []any{authorID}, page)
+}
+
+// listPage выбирает страницу статей в порядке (created_at, id) DESC.
+// where — фиксированное условие с плейсхолдерами $1..$len(args).
+func (r *ArticleRepository) listPage(ctx context.Context, where string, args []any, page PageRequest) (*Page[*models.Article], error) {
+	cursor, err := decodePageCursor(page)
+	if err != nil {
+		return nil, err
+	}
+	size := page.PageSize()
+
+	query := `
+		SELECT id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at
+		FROM articles
+		WHERE deleted_at IS NULL AND ` + where
+	if cursor != nil {
+		query += fmt.Sprintf(` AND (created_at, id) < ($%d, $%d)`, len(args)+1, len(args)+2)
+		args = append(args, cursor.CreatedAt, cursor.ID)
+	}
+	query += fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT $%d`, len(args)+1)
+	args = append(args, size+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query articles: %w", err)
+	}
+	defer rows.Close()
+
+	articles, err := scanArticles(rows)
+	if err != nil {
+		return nil, err
+	}
+	return NewPage(articles, size, ArticleCursor), nil
+}
+
+func ArticleCursor(a *models.Article) Cursor {
+	return Cursor{CreatedAt: a.CreatedAt, ID: a.ID}
+}
+
+// lockArticle читает статью не из корзины и блокирует строку до конца транзакции
+func lockArticle(ctx context.Context, tx *sql.Tx, id int) (*models.Article, error) {
+	article, err := scanArticle(tx.QueryRowContext(ctx, `
+		SELECT id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at
+		FROM articles
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("article")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock article: %w", err)
+	}
+	return article, nil
+}
+
+// scanArticle читает статью из строки со стандартным списком колонок:
+// id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at,
+// views, created_at, updated_at
+func scanArticle(row rowScanner) (*models.Article, error) {
+	article := &models.Article{}
+	err := row.Scan(
+		&article.ID,
+		&article.Title,
+		&article.Slug,
+		&article.Content,
+		&article.AuthorID,
+		&article.Status,
+		&article.PublishedAt,
+		&article.PublishAt,
+		&article.UnpublishAt,
+		&article.Views,
+		&article.CreatedAt,
+		&article.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+func scanArticles(rows *sql.Rows) ([]*models.Article, error) {
+	var articles []*models.Article
+	for rows.Next() {
+		article, err := scanArticle(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan article: %w", err)
+		}
+		articles = append(articles, article)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return articles, nil
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-articles-app/diff"
+	"go-articles-app/models"
+)
+
+// RevisionDiff — построчная разница заголовка и текста между двумя ревизиями
+type RevisionDiff struct {
+	ArticleID    int         `json:"article_id"`
+	FromRevision int         `json:"from_revision"`
+	ToRevision   int         `json:"to_revision"`
+	Title        []diff.Line `json:"title"`
+	Content      []diff.Line `json:"content"`
+}
+
+// NewRevisionDiff сравнивает ревизии from и to одной статьи
+func NewRevisionDiff(from, to *models.ArticleRevision) *RevisionDiff {
+	return &RevisionDiff{
+		ArticleID:    from.ArticleID,
+		FromRevision: from.Revision,
+		ToRevision:   to.Revision,
+		Title:        diff.Lines(from.Title, to.Title),
+		Content:      diff.Lines(from.Content, to.Content),
+	}
+}
+
+// insertRevision записывает следующую по номеру ревизию статьи. Вызывается в
+// транзакции после UPDATE/INSERT строки статьи: блокировка строки упорядочивает
+// параллельные правки, поэтому MAX(revision) + 1 не повторяется.
+func insertRevision(ctx context.Context, tx *sql.Tx, article *models.Article, editorID *int) error {
+	query := `
+		INSERT INTO article_revisions (article_id, revision, title, content, editor_id)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4
+		FROM article_revisions
+		WHERE article_id = $1
+	`
+	_, err := tx.ExecContext(ctx, query, article.ID, article.Title, article.Content, editorID)
+	if err != nil {
+		return fmt.Errorf("failed to save revision: %w", pgError(err))
+	}
+	return nil
+}
+
+// ListRevisions возвращает ревизии статьи, новые первыми
+func (r *ArticleRepository) ListRevisions(ctx context.Context, articleID int) ([]*models.ArticleRevision, error) {
+	query := `
+		SELECT rev.id, rev.article_id, rev.revision, rev.title, rev.content, rev.editor_id, rev.created_at
+		FROM article_revisions rev
+		JOIN articles a ON a.id = rev.article_id AND a.deleted_at IS NULL
+		WHERE rev.article_id = $1
+		ORDER BY rev.revision DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []*models.ArticleRevision{}
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	if len(revisions) == 0 {
+		var exists bool
+		err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM articles WHERE id = $1 AND deleted_at IS NULL)", articleID).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check article: %w", err)
+		}
+		if !exists {
+			return nil, notFound("article")
+		}
+	}
+	return revisions, nil
+}
+
+func (r *ArticleRepository) GetRevision(ctx context.Context, articleID, revision int) (*models.ArticleRevision, error) {
+	query := `
+		SELECT rev.id, rev.article_id, rev.revision, rev.title, rev.content, rev.editor_id, rev.created_at
+		FROM article_revisions rev
+		JOIN articles a ON a.id = rev.article_id AND a.deleted_at IS NULL
+		WHERE rev.article_id = $1 AND rev.revision = $2
+	`
+	rev, err := scanRevision(r.db.QueryRowContext(ctx, query, articleID, revision))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("revision")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+	return rev, nil
+}
+
+// DiffRevisions сравнивает две ревизии статьи; from может быть больше to
+func (r *ArticleRepository) DiffRevisions(ctx context.Context, articleID, from, to int) (*RevisionDiff, error) {
+	fromRev, err := r.GetRevision(ctx, articleID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := r.GetRevision(ctx, articleID, to)
+	if err != nil {
+		return nil, err
+	}
+	return NewRevisionDiff(fromRev, toRev), nil
+}
+
+// RestoreRevision возвращает статье заголовок и текст старой ревизии. Это
+// обычная правка: она создает новую ревизию, история не переписывается.
+func (r *ArticleRepository) RestoreRevision(ctx context.Context, articleID, revision int) (*models.Article, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := lockArticle(ctx, tx, articleID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, notFound("revision")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var revTitle string
+	err = tx.QueryRowContext(ctx, `
+		SELECT title FROM article_revisions WHERE article_id = $1 AND revision = $2
+	`, articleID, revision).Scan(&revTitle)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("revision")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+	oldSlug := before.Slug
+	newSlug, err := reslug(ctx, tx, articleID, oldSlug, before.Title, revTitle)
+	if err != nil {
+		return nil, err
+	}
+
+	article := &models.Article{}
+	err = tx.QueryRowContext(ctx, `
+		UPDATE articles a
+		SET title = rev.title, slug = $3, content = rev.content, updated_at = NOW(),
+			content_html = CASE WHEN a.content = rev.content THEN a.content_html END,
+			content_toc = CASE WHEN a.content = rev.content THEN a.content_toc END,
+			render_version = CASE WHEN a.content = rev.content THEN a.render_version END
+		FROM article_revisions rev
+		WHERE a.id = $1 AND rev.article_id = a.id AND rev.revision = $2
+		RETURNING a.id, a.title, a.slug, a.content, a.author_id, a.status, a.published_at, a.publish_at, a.unpublish_at, a.views, a.created_at, a.updated_at
+	`, articleID, revision, newSlug).Scan(
+		&article.ID,
+		&article.Title,
+		&article.Slug,
+		&article.Content,
+		&article.AuthorID,
+		&article.Status,
+		&article.PublishedAt,
+		&article.PublishAt,
+		&article.UnpublishAt,
+		&article.Views,
+		&article.CreatedAt,
+		&article.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("revision")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore revision: %w", pgError(err))
+	}
+	if newSlug != oldSlug {
+		if err := recordSlug(ctx, tx, articleID, newSlug); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := insertRevision(ctx, tx, article, actorID(ctx)); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, tx, models.AuditUpdate, models.AuditArticleEntity, articleID, before, article); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", pgError(err))
+	}
+	return article, nil
+}
+
+func scanRevision(row rowScanner) (*models.ArticleRevision, error) {
+	rev := &models.ArticleRevision{}
+	var editorID sql.NullInt64
+	err := row.Scan(
+		&rev.ID,
+		&rev.ArticleID,
+		&rev.Revision,
+		&rev.Title,
+		&rev.Content,
+		&editorID,
+		&rev.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if editorID.Valid {
+		id := int(editorID.Int64)
+		rev.EditorID = &id
+	}
+	return rev, nil
+}
@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ScheduleAction — что запланировано для статьи: публикация или снятие с публикации
+type ScheduleAction string
+
+const (
+	SchedulePublish   ScheduleAction = "publish"
+	ScheduleUnpublish ScheduleAction = "unpublish"
+)
+
+// scheduleColumns — колонка articles и целевой статус для каждого действия.
+// Имя колонки подставляется в SQL только из этой таблицы.
+var scheduleColumns = map[ScheduleAction]struct {
+	column string
+	to     models.ArticleStatus
+}{
+	SchedulePublish:   {"publish_at", models.ArticlePublished},
+	ScheduleUnpublish: {"unpublish_at", models.ArticleArchived},
+}
+
+// ScheduleRun — итог одного прохода планировщика
+type ScheduleRun struct {
+	Published   int // опубликовано по publish_at
+	Unpublished int // снято с публикации по unpublish_at
+	Skipped     int // расписание снято без смены статуса: переход уже не нужен
+}
+
+// Total возвращает число обработанных строк
+func (r ScheduleRun) Total() int {
+	return r.Published + r.Unpublished + r.Skipped
+}
+
+// CheckSchedule проверяет, можно ли запланировать action на время at для статьи
+// в ее текущем состоянии. Снятие с публикации планируется только для
+// опубликованной статьи или статьи с запланированной публикацией и должно идти
+// после публикации.
+func CheckSchedule(article *models.Article, action ScheduleAction, at time.Time) error {
+	if _, ok := scheduleColumns[action]; !ok {
+		return fmt.Errorf("%w: unknown schedule action %q", ErrInvalidInput, action)
+	}
+	if at.IsZero() {
+		return fmt.Errorf("%w: schedule time is required", ErrInvalidInput)
+	}
+
+	switch action {
+	case SchedulePublish:
+		if err := CheckArticleTransition(article.Status, models.ArticlePublished); err != nil {
+			return err
+		}
+		if article.UnpublishAt != nil && !article.UnpublishAt.After(at) {
+			return fmt.Errorf("%w: publish time must be before unpublish time", ErrInvalidInput)
+		}
+	case ScheduleUnpublish:
+		if !article.IsPublished() && article.PublishAt == nil {
+			return fmt.Errorf("%w: article is neither published nor scheduled for publishing", ErrConflict)
+		}
+		if article.PublishAt != nil && !at.After(*article.PublishAt) {
+			return fmt.Errorf("%w: unpublish time must be after publish time", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// Schedule планирует публикацию или снятие с публикации статьи на время at.
+// Повторный вызов переносит время.
+func (r *ArticleRepository) Schedule(ctx context.Context, id int, action ScheduleAction, at time.Time) (*models.Article, error) {
+	target, ok := scheduleColumns[action]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown schedule action %q", ErrInvalidInput, action)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	current := &models.Article{}
+	err = tx.QueryRowContext(ctx, `
+		SELECT status, publish_at, unpublish_at FROM articles WHERE id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, id).Scan(&current.Status, &current.PublishAt, &current.UnpublishAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("article")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get article: %w", err)
+	}
+	if err := CheckSchedule(current, action, at); err != nil {
+		return nil, err
+	}
+
+	article, err := scanArticle(tx.QueryRowContext(ctx, `
+		UPDATE articles SET `+target.column+` = $1
+		WHERE id = $2
+		RETURNING id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at
+	`, at, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to schedule article: %w", pgError(err))
+	}
+	err = recordAudit(ctx, tx, models.AuditSchedule, models.AuditArticleEntity, id,
+		AuditSchedule{current.PublishAt, current.UnpublishAt}, AuditSchedule{article.PublishAt, article.UnpublishAt})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", pgError(err))
+	}
+	return article, nil
+}
+
+// CancelSchedule отменяет запланированное действие; отмена отсутствующего
+// расписания не считается ошибкой
+func (r *ArticleRepository) CancelSchedule(ctx context.Context, id int, action ScheduleAction) (*models.Article, error) {
+	target, ok := scheduleColumns[action]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown schedule action %q", ErrInvalidInput, action)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := lockArticle(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	article, err := scanArticle(tx.QueryRowContext(ctx, `
+		UPDATE articles SET `+target.column+` = NULL
+		WHERE id = $1
+		RETURNING id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at
+	`, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel schedule: %w", err)
+	}
+	err = recordAudit(ctx, tx, models.AuditSchedule, models.AuditArticleEntity, id,
+		AuditSchedule{before.PublishAt, before.UnpublishAt}, AuditSchedule{article.PublishAt, article.UnpublishAt})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", pgError(err))
+	}
+	return article, nil
+}
+
+// ProcessDueSchedules выполняет не больше limit наступивших публикаций и столько
+// же снятий с публикации в одной транзакции. Строки захватываются через
+// FOR UPDATE SKIP LOCKED, поэтому несколько экземпляров приложения могут
+// обрабатывать расписание параллельно, не трогая одни и те же статьи.
+// Публикации обрабатываются первыми: статья, у которой наступили оба времени,
+// за один проход публикуется и снимается с публикации.
+func (r *ArticleRepository) ProcessDueSchedules(ctx context.Context, limit int) (ScheduleRun, error) {
+	var run ScheduleRun
+	limit = PageRequest{Limit: limit}.PageSize()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return run, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	moved, skipped, err := processDue(ctx, tx, SchedulePublish, limit)
+	if err != nil {
+		return run, err
+	}
+	run.Published, run.Skipped = moved, skipped
+
+	moved, skipped, err = processDue(ctx, tx, ScheduleUnpublish, limit)
+	if err != nil {
+		return run, err
+	}
+	run.Unpublished, run.Skipped = moved, run.Skipped+skipped
+
+	if err := tx.Commit(); err != nil {
+		return run, fmt.Errorf("commit: %w", pgError(err))
+	}
+	return run, nil
+}
+
+// processDue захватывает наступившие строки для action, переводит в целевой
+// статус те, для которых переход допустим, и снимает расписание у остальных
+func processDue(ctx context.Context, tx *sql.Tx, action ScheduleAction, limit int) (moved, skipped int, err error) {
+	target := scheduleColumns[action]
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, status FROM articles
+		WHERE `+target.column+` <= NOW() AND deleted_at IS NULL
+		ORDER BY `+target.column+`, id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to claim scheduled articles: %w", pgError(err))
+	}
+	defer rows.Close()
+
+	var moveIDs, skipIDs []int64
+	var froms []string
+	for rows.Next() {
+		var id int64
+		var from models.ArticleStatus
+		if err := rows.Scan(&id, &from); err != nil {
+			return 0, 0, fmt.Errorf("failed to scan scheduled article: %w", err)
+		}
+		if CheckArticleTransition(from, target.to) != nil {
+			skipIDs = append(skipIDs, id)
+			continue
+		}
+		moveIDs = append(moveIDs, id)
+		froms = append(froms, string(from))
+	}
+	if err := rows.Err(); err != nil {
+		return 0, 0, fmt.Errorf("rows iteration error: %w", err)
+	}
+	rows.Close()
+
+	if len(moveIDs) > 0 {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE articles SET
+				status = $1,
+				published_at = CASE WHEN $2 THEN NOW() ELSE published_at END,
+				`+target.column+` = NULL,
+				updated_at = NOW()
+			WHERE id = ANY($3::int[])
+		`, target.to, target.to == models.ArticlePublished, pq.Array(moveIDs))
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to apply schedule: %w", pgError(err))
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO article_status_transitions (article_id, from_status, to_status, actor_id)
+			SELECT t.id, t.from_status, $3, $4
+			FROM unnest($1::int[], $2::text[]) AS t(id, from_status)
+		`, pq.Array(moveIDs), pq.Array(froms), target.to, actorID(ctx))
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to save status transitions: %w", pgError(err))
+		}
+
+		events := make([]*models.AuditEvent, len(moveIDs))
+		for i, id := range moveIDs {
+			from := AuditStatus{models.ArticleStatus(froms[i])}
+			events[i], err = NewAuditEvent(ctx, StatusAction(target.to), models.AuditArticleEntity, int(id), from, AuditStatus{target.to})
+			if err != nil {
+				return 0, 0, err
+			}
+		}
+		if err := insertAuditEvents(ctx, tx, events); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if len(skipIDs) > 0 {
+		_, err = tx.ExecContext(ctx, `UPDATE articles SET `+target.column+` = NULL WHERE id = ANY($1::int[])`, pq.Array(skipIDs))
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to clear schedule: %w", pgError(err))
+		}
+	}
+	return len(moveIDs), len(skipIDs), nil
+}
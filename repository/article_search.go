@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/models"
+	"html"
+	"strings"
+	"unicode"
+)
+
+type SearchMode string
+
+const (
+	// SearchModeWeb — синтаксис веб-поиска: "точная фраза", or, -исключение
+	SearchModeWeb SearchMode = "web"
+	// SearchModePhrase — все слова подряд в указанном порядке
+	SearchModePhrase SearchMode = "phrase"
+	// SearchModePrefix — каждое слово как префикс (поиск по мере ввода)
+	SearchModePrefix SearchMode = "prefix"
+)
+
+const searchConfig = "russian"
+
+// Границы совпадений в ts_headline — символы из области частного
+// использования Unicode. Они вырезаются из текста перед подсветкой, а после
+// экранирования заменяются на <mark>, поэтому разметка из статьи в сниппет
+// не попадает.
+const (
+	markStart = "\uE000"
+	markStop  = "\uE001"
+)
+
+var headlineOptions = `StartSel="` + markStart + `", StopSel="` + markStop + `", MaxWords=35, MinWords=15, MaxFragments=2`
+
+// highlightSnippet экранирует результат ts_headline и превращает границы
+// совпадений в <mark>
+func highlightSnippet(headline string) string {
+	return strings.NewReplacer(markStart, "<mark>", markStop, "</mark>").Replace(html.EscapeString(headline))
+}
+
+type SearchQuery struct {
+	Query         string
+	Mode          SearchMode
+	IncludeDrafts bool
+	Limit         int
+	Offset        int
+}
+
+type SearchResult struct {
+	Article *models.Article `json:"article"`
+	Rank    float64         `json:"rank"`
+	// Snippet — HTML-фрагмент content: текст статьи экранирован, из
+	// разметки есть только <mark>...</mark> вокруг совпадений
+	Snippet string `json:"snippet"`
+}
+
+// ArticleSearcher реализуется хранилищами с полнотекстовым поиском
+type ArticleSearcher interface {
+	Search(ctx context.Context, q SearchQuery) ([]*SearchResult, error)
+}
+
+var _ ArticleSearcher = (*ArticleRepository)(nil)
+
+// tsQueryExpr возвращает SQL-выражение tsquery и его аргумент для режима поиска
+func (q SearchQuery) tsQueryExpr() (expr, arg string, err error) {
+	text := strings.TrimSpace(q.Query)
+	if text == "" {
+		return "", "", fmt.Errorf("%w: empty search query", ErrInvalidQuery)
+	}
+
+	switch q.Mode {
+	case "", SearchModeWeb:
+		return "websearch_to_tsquery('" + searchConfig + "', $1)", text, nil
+	case SearchModePhrase:
+		return "phraseto_tsquery('" + searchConfig + "', $1)", text, nil
+	case SearchModePrefix:
+		prefix := prefixTSQuery(text)
+		if prefix == "" {
+			return "", "", fmt.Errorf("%w: search query has no words", ErrInvalidQuery)
+		}
+		return "to_tsquery('" + searchConfig + "', $1)", prefix, nil
+	default:
+		return "", "", fmt.Errorf("%w: unknown search mode %q", ErrInvalidQuery, q.Mode)
+	}
+}
+
+// prefixTSQuery строит "слово1:* & слово2:*". В слова попадают только буквы
+// и цифры, поэтому операторы tsquery из пользовательского ввода не проходят.
+func prefixTSQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = strings.ToLower(w) + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// Search ищет статьи по заголовку и содержанию. Совпадения в заголовке
+// (вес A) ранжируются выше совпадений в тексте (вес B). По умолчанию
+// ищутся только опубликованные статьи.
+func (r *ArticleRepository) Search(ctx context.Context, q SearchQuery) ([]*SearchResult, error) {
+	tsquery, arg, err := q.tsQueryExpr()
+	if err != nil {
+		return nil, err
+	}
+	if q.Offset < 0 {
+		return nil, fmt.Errorf("%w: negative offset", ErrInvalidQuery)
+	}
+	limit := PageRequest{Limit: q.Limit}.PageSize()
+
+	publishedOnly := ""
+	if !q.IncludeDrafts {
+		publishedOnly = " AND a.status = 'published'"
+	}
+
+	// ts_headline дорогой, поэтому считается только для строк страницы
+	query := `
+		SELECT id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at, rank,
+			ts_headline('` + searchConfig + `', translate(content, $4, ''), query, $5)
+		FROM (
+			SELECT a.id, a.title, a.slug, a.content, a.author_id, a.status, a.published_at, a.publish_at, a.unpublish_at, a.views,
+				a.created_at, a.updated_at, ts_rank_cd(a.search_vector, query) AS rank, query
+			FROM articles a, ` + tsquery + ` query
+			WHERE a.search_vector @@ query AND a.deleted_at IS NULL` + publishedOnly + `
+			ORDER BY rank DESC, a.id DESC
+			LIMIT $2 OFFSET $3
+		) ranked
+		ORDER BY rank DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, arg, limit, q.Offset, markStart+markStop, headlineOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search articles: %w", err)
+	}
+	defer rows.Close()
+
+	results := []*SearchResult{}
+	for rows.Next() {
+		res := &SearchResult{Article: &models.Article{}}
+		err := rows.Scan(
+			&res.Article.ID,
+			&res.Article.Title,
+			&res.Article.Slug,
+			&res.Article.Content,
+			&res.Article.AuthorID,
+			&res.Article.Status,
+			&res.Article.PublishedAt,
+			&res.Article.PublishAt,
+			&res.Article.UnpublishAt,
+			&res.Article.Views,
+			&res.Article.CreatedAt,
+			&res.Article.UpdatedAt,
+			&res.Rank,
+			&res.Snippet,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		res.Snippet = highlightSnippet(res.Snippet)
+		results = append(results, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrefixTSQuery(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"go", "go:*"},
+		{"Postgre SQL", "postgre:* & sql:*"},
+		{"Микросерв архит", "микросерв:* & архит:*"},
+		{"go & !(x | y):* <-> z", "go:* & x:* & y:* & z:*"},
+		{"'; DROP TABLE", "drop:* & table:*"},
+		{"&|!", ""},
+	}
+	for _, tt := range tests {
+		if got := prefixTSQuery(tt.in); got != tt.want {
+			t.Errorf("prefixTSQuery(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSearchQueryValidation(t *testing.T) {
+	bad := []SearchQuery{
+		{Query: "   "},
+		{Query: "go", Mode: "regex"},
+		{Query: "()", Mode: SearchModePrefix},
+	}
+	for _, q := range bad {
+		if _, _, err := q.tsQueryExpr(); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("tsQueryExpr(%+v): err = %v, want ErrInvalidQuery", q, err)
+		}
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	headline := `<script>alert("x")</script> ` + markStart + `postgres` + markStop + ` & <b>indexes</b>`
+	want := `&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; <mark>postgres</mark> &amp; &lt;b&gt;indexes&lt;/b&gt;`
+	if got := highlightSnippet(headline); got != want {
+		t.Fatalf("highlightSnippet = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/slug"
+)
+
+// slugLockKey — ключ advisory-блокировки, под которой выбираются слаги.
+// Выдача слагов сериализуется целиком: основы разных заголовков могут
+// пересекаться ("Go 2" и второй "Go" претендуют на go-2).
+const slugLockKey = 0x736c7567 // "slug"
+
+// pickSlug выбирает свободный слаг для заголовка. Слаги, которые уже
+// принадлежат статье articleID (в том числе старые), считаются свободными;
+// для новой статьи articleID = 0. Блокировка держится до конца транзакции.
+func pickSlug(ctx context.Context, tx *sql.Tx, articleID int, title string) (string, error) {
+	if err := lockSlugs(ctx, tx); err != nil {
+		return "", err
+	}
+	return uniqueSlug(ctx, tx, articleID, slug.Make(title), nil)
+}
+
+// lockSlugs берет блокировку slugLockKey до конца транзакции
+func lockSlugs(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, slugLockKey); err != nil {
+		return fmt.Errorf("failed to lock slugs: %w", err)
+	}
+	return nil
+}
+
+// uniqueSlug возвращает base или первый вариант base-2, base-3, ..., который
+// не занят другой статьей и не входит в reserved. Вызывается под lockSlugs.
+func uniqueSlug(ctx context.Context, tx *sql.Tx, articleID int, base string, reserved map[string]bool) (string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT slug FROM article_slugs
+		WHERE (slug = $1 OR slug LIKE $2) AND article_id <> $3
+	`, base, escapeLike(base)+"-%", articleID)
+	if err != nil {
+		return "", fmt.Errorf("failed to query slugs: %w", err)
+	}
+	defer rows.Close()
+
+	taken := make(map[string]bool)
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return "", fmt.Errorf("failed to scan slug: %w", err)
+		}
+		taken[s] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return slug.Unique(base, func(s string) bool { return taken[s] || reserved[s] }), nil
+}
+
+// recordSlug добавляет слаг в историю статьи. Слаг, который статья уже
+// носила раньше, снова становится каноническим без новой записи.
+func recordSlug(ctx context.Context, tx *sql.Tx, articleID int, s string) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO article_slugs (slug, article_id) VALUES ($1, $2)
+		ON CONFLICT (slug) DO NOTHING
+	`, s, articleID)
+	if err != nil {
+		return fmt.Errorf("failed to record slug: %w", pgError(err))
+	}
+	return nil
+}
+
+// reslug возвращает слаг статьи после смены заголовка. Слаг меняется, только
+// если меняется его основа: правка знаков препинания или регистра адрес не
+// трогает.
+func reslug(ctx context.Context, tx *sql.Tx, articleID int, current, oldTitle, newTitle string) (string, error) {
+	if slug.Make(oldTitle) == slug.Make(newTitle) {
+		return current, nil
+	}
+	return pickSlug(ctx, tx, articleID, newTitle)
+}
+
+// GetBySlug находит статью по текущему или одному из прежних слагов.
+// Канонический адрес — article.Slug: если он отличается от запрошенного,
+// клиента нужно перенаправить (301).
+func (r *ArticleRepository) GetBySlug(ctx context.Context, s string) (*models.Article, error) {
+	query := `
+		SELECT a.id, a.title, a.slug, a.content, a.author_id, a.status, a.published_at, a.publish_at, a.unpublish_at, a.views, a.created_at, a.updated_at
+		FROM article_slugs s
+		JOIN articles a ON a.id = s.article_id
+		WHERE s.slug = $1 AND a.deleted_at IS NULL
+	`
+	article, err := scanArticle(r.db.QueryRowContext(ctx, query, s))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("article")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get article by slug: %w", err)
+	}
+	return article, nil
+}
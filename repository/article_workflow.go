@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+)
+
+// articleTransitions — допустимые переходы статуса статьи. Публикация без
+// ревью (draft -> published) разрешена, обратные переходы возвращают статью
+// в черновики или снова публикуют ее из архива.
+var articleTransitions = map[models.ArticleStatus][]models.ArticleStatus{
+	models.ArticleDraft:     {models.ArticleInReview, models.ArticlePublished},
+	models.ArticleInReview:  {models.ArticleDraft, models.ArticlePublished},
+	models.ArticlePublished: {models.ArticleArchived, models.ArticleDraft},
+	models.ArticleArchived:  {models.ArticleDraft, models.ArticlePublished},
+}
+
+// AllowedArticleTransitions возвращает статусы, в которые можно перейти из from
+func AllowedArticleTransitions(from models.ArticleStatus) []models.ArticleStatus {
+	return append([]models.ArticleStatus(nil), articleTransitions[from]...)
+}
+
+// CheckArticleTransition проверяет переход по таблице articleTransitions.
+// Повторная публикация возвращает ErrAlreadyPublished, как и раньше делал Publish.
+func CheckArticleTransition(from, to models.ArticleStatus) error {
+	if _, ok := articleTransitions[to]; !ok {
+		return fmt.Errorf("%w: unknown article status %q", ErrInvalidInput, to)
+	}
+	if from == to && to == models.ArticlePublished {
+		return ErrAlreadyPublished
+	}
+	for _, allowed := range articleTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: article cannot move from %s to %s", ErrConflict, from, to)
+}
+
+// Transition переводит статью в статус to и записывает переход в историю вместе
+// с пользователем из контекста (WithActor). При публикации обновляется published_at
+// и снимается запланированная публикация, при снятии с публикации — запланированное
+// снятие.
+func (r *ArticleRepository) Transition(ctx context.Context, id int, to models.ArticleStatus) (*models.Article, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var from models.ArticleStatus
+	err = tx.QueryRowContext(ctx, `SELECT status FROM articles WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&from)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("article")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get article: %w", err)
+	}
+	if err := CheckArticleTransition(from, to); err != nil {
+		return nil, err
+	}
+
+	article := &models.Article{}
+	err = tx.QueryRowContext(ctx, `
+		UPDATE articles SET
+			status = $1,
+			published_at = CASE WHEN $2 THEN NOW() ELSE published_at END,
+			publish_at = CASE WHEN $2 THEN NULL ELSE publish_at END,
+			unpublish_at = CASE WHEN $3 THEN NULL ELSE unpublish_at END,
+			updated_at = NOW()
+		WHERE id = $4
+		RETURNING id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at
+	`, to, to == models.ArticlePublished, from == models.ArticlePublished, id).Scan(
+		&article.ID,
+		&article.Title,
+		&article.Slug,
+		&article.Content,
+		&article.AuthorID,
+		&article.Status,
+		&article.PublishedAt,
+		&article.PublishAt,
+		&article.UnpublishAt,
+		&article.Views,
+		&article.CreatedAt,
+		&article.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to change article status: %w", pgError(err))
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO article_status_transitions (article_id, from_status, to_status, actor_id)
+		VALUES ($1, $2, $3, $4)
+	`, id, from, to, actorID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save status transition: %w", pgError(err))
+	}
+	if err := recordAudit(ctx, tx, StatusAction(to), models.AuditArticleEntity, id, AuditStatus{from}, AuditStatus{to}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", pgError(err))
+	}
+	return article, nil
+}
+
+// ListTransitions возвращает историю смены статуса статьи, старые первыми
+func (r *ArticleRepository) ListTransitions(ctx context.Context, articleID int) ([]*models.ArticleTransition, error) {
+	query := `
+		SELECT tr.id, tr.article_id, tr.from_status, tr.to_status, tr.actor_id, tr.created_at
+		FROM article_status_transitions tr
+		JOIN articles a ON a.id = tr.article_id AND a.deleted_at IS NULL
+		WHERE tr.article_id = $1
+		ORDER BY tr.created_at, tr.id
+	`
+	rows, err := r.db.QueryContext(ctx, query, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status transitions: %w", err)
+	}
+	defer rows.Close()
+
+	transitions := []*models.ArticleTransition{}
+	for rows.Next() {
+		tr := &models.ArticleTransition{}
+		var actorID sql.NullInt64
+		if err := rows.Scan(&tr.ID, &tr.ArticleID, &tr.FromStatus, &tr.ToStatus, &actorID, &tr.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan status transition: %w", err)
+		}
+		if actorID.Valid {
+			id := int(actorID.Int64)
+			tr.ActorID = &id
+		}
+		transitions = append(transitions, tr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	if len(transitions) == 0 {
+		var exists bool
+		err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM articles WHERE id = $1 AND deleted_at IS NULL)", articleID).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check article: %w", err)
+		}
+		if !exists {
+			return nil, notFound("article")
+		}
+	}
+	return transitions, nil
+}
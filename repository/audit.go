@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"go-articles-app/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Журнал аудита: каждое изменение пользователя или статьи записывается в
+// audit_events в той же транзакции, что и само изменение. Автора берет из
+// контекста WithActor, метаданные запроса — из WithRequestInfo.
+
+// RequestInfo — метаданные HTTP-запроса, попадающие в журнал аудита
+type RequestInfo struct {
+	ID        string
+	IP        string
+	UserAgent string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo сохраняет в контексте метаданные запроса для журнала аудита
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext возвращает метаданные, сохраненные WithRequestInfo
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
+
+// Снимки для событий, затрагивающих отдельные поля: в Before и After
+// попадает только то, что изменилось
+
+// AuditStatus — снимок статуса статьи для publish и transition
+type AuditStatus struct {
+	Status models.ArticleStatus `json:"status"`
+}
+
+// AuditSchedule — снимок расписания статьи для schedule
+type AuditSchedule struct {
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+// AuditRole — снимок роли пользователя для set_role
+type AuditRole struct {
+	Role models.Role `json:"role"`
+}
+
+// StatusAction — действие журнала для перехода статьи в статус to
+func StatusAction(to models.ArticleStatus) models.AuditAction {
+	if to == models.ArticlePublished {
+		return models.AuditPublish
+	}
+	return models.AuditTransition
+}
+
+// NewAuditEvent собирает событие журнала с автором и метаданными запроса из
+// контекста. before и after сериализуются в JSON; nil означает отсутствие снимка.
+func NewAuditEvent(ctx context.Context, action models.AuditAction, entity models.AuditEntity, id int, before, after any) (*models.AuditEvent, error) {
+	event := &models.AuditEvent{
+		ActorID:    actorID(ctx),
+		Action:     action,
+		EntityType: entity,
+		EntityID:   id,
+	}
+	var err error
+	if event.Before, err = auditSnapshot(before); err != nil {
+		return nil, err
+	}
+	if event.After, err = auditSnapshot(after); err != nil {
+		return nil, err
+	}
+	info := RequestInfoFromContext(ctx)
+	event.RequestID, event.IP, event.UserAgent = info.ID, info.IP, info.UserAgent
+	return event, nil
+}
+
+func auditSnapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit snapshot: %w", err)
+	}
+	if string(data) == "null" {
+		return nil, nil
+	}
+	return data, nil
+}
+
+// recordAudit пишет событие журнала в транзакции изменения
+func recordAudit(ctx context.Context, tx *sql.Tx, action models.AuditAction, entity models.AuditEntity, id int, before, after any) error {
+	event, err := NewAuditEvent(ctx, action, entity, id, before, after)
+	if err != nil {
+		return err
+	}
+	return insertAuditEvents(ctx, tx, []*models.AuditEvent{event})
+}
+
+// insertAuditEvents пишет события, собранные NewAuditEvent, одним запросом в
+// транзакции изменения
+func insertAuditEvents(ctx context.Context, tx *sql.Tx, events []*models.AuditEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	n := len(events)
+	actors := make([]sql.NullInt64, n)
+	actions, entities := make([]string, n), make([]string, n)
+	ids := make([]int64, n)
+	befores, afters := make([]sql.NullString, n), make([]sql.NullString, n)
+	requestIDs, ips, agents := make([]string, n), make([]string, n), make([]string, n)
+	// Снимки передаются как текст: []byte lib/pq отправил бы как bytea
+	for i, e := range events {
+		if e.ActorID != nil {
+			actors[i] = sql.NullInt64{Int64: int64(*e.ActorID), Valid: true}
+		}
+		actions[i], entities[i], ids[i] = string(e.Action), string(e.EntityType), int64(e.EntityID)
+		befores[i] = sql.NullString{String: string(e.Before), Valid: e.Before != nil}
+		afters[i] = sql.NullString{String: string(e.After), Valid: e.After != nil}
+		requestIDs[i], ips[i], agents[i] = e.RequestID, e.IP, e.UserAgent
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO audit_events (actor_id, action, entity_type, entity_id, before, after, request_id, ip, user_agent)
+		SELECT * FROM unnest($1::int[], $2::text[], $3::text[], $4::int[], $5::jsonb[], $6::jsonb[], $7::text[], $8::text[], $9::text[])
+	`, pq.Array(actors), pq.Array(actions), pq.Array(entities), pq.Array(ids),
+		pq.Array(befores), pq.Array(afters), pq.Array(requestIDs), pq.Array(ips), pq.Array(agents))
+	if err != nil {
+		return fmt.Errorf("failed to save audit events: %w", pgError(err))
+	}
+	return nil
+}
+
+// auditPurged оборачивает DELETE ... RETURNING id в запрос, который пишет
+// событие purge на каждую удаленную строку; RowsAffected — число удаленных.
+// Каскадно удаленные строки отдельных событий не получают.
+func auditPurged(deleteQuery string, entity models.AuditEntity) string {
+	return `WITH purged AS (` + deleteQuery + `)
+		INSERT INTO audit_events (actor_id, action, entity_type, entity_id, request_id, ip, user_agent)
+		SELECT $2, '` + string(models.AuditPurge) + `', '` + string(entity) + `', id, $3, $4, $5 FROM purged`
+}
+
+// purgeArgs дополняет параметр запроса очистки ($1) автором и метаданными
+// запроса для auditPurged
+func purgeArgs(ctx context.Context, retention any) []any {
+	info := RequestInfoFromContext(ctx)
+	return []any{retention, actorID(ctx), info.ID, info.IP, info.UserAgent}
+}
+
+// AuditQuery — фильтр журнала аудита. Пустые поля не ограничивают выборку;
+// EntityID имеет смысл только вместе с EntityType. Since включается,
+// Until — нет.
+type AuditQuery struct {
+	EntityType models.AuditEntity
+	EntityID   int
+	ActorID    int
+	Action     models.AuditAction
+	Since      time.Time
+	Until      time.Time
+	Page       PageRequest
+}
+
+// Validate проверяет согласованность фильтра
+func (q AuditQuery) Validate() error {
+	if q.EntityType != "" && !q.EntityType.Valid() {
+		return fmt.Errorf("%w: unknown entity type %q", ErrInvalidQuery, q.EntityType)
+	}
+	if q.EntityID != 0 && q.EntityType == "" {
+		return fmt.Errorf("%w: entity id requires entity type", ErrInvalidQuery)
+	}
+	if q.EntityID < 0 || q.ActorID < 0 {
+		return fmt.Errorf("%w: ids must be positive", ErrInvalidQuery)
+	}
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Since.Before(q.Until) {
+		return fmt.Errorf("%w: since must be before until", ErrInvalidQuery)
+	}
+	return nil
+}
+
+// AuditCursor — позиция события для постраничного чтения журнала
+func AuditCursor(e *models.AuditEvent) Cursor {
+	return Cursor{CreatedAt: e.CreatedAt, ID: e.ID}
+}
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// List возвращает страницу журнала, новые события первыми
+func (r *AuditRepository) List(ctx context.Context, q AuditQuery) (*Page[*models.AuditEvent], error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	cursor, err := decodePageCursor(q.Page)
+	if err != nil {
+		return nil, err
+	}
+	size := q.Page.PageSize()
+
+	query := `
+		SELECT id, actor_id, action, entity_type, entity_id, before, after, request_id, ip, user_agent, created_at
+		FROM audit_events
+		WHERE true`
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if q.EntityType != "" {
+		query += ` AND entity_type = ` + arg(q.EntityType)
+	}
+	if q.EntityID != 0 {
+		query += ` AND entity_id = ` + arg(q.EntityID)
+	}
+	if q.ActorID != 0 {
+		query += ` AND actor_id = ` + arg(q.ActorID)
+	}
+	if q.Action != "" {
+		query += ` AND action = ` + arg(q.Action)
+	}
+	if !q.Since.IsZero() {
+		query += ` AND created_at >= ` + arg(q.Since)
+	}
+	if !q.Until.IsZero() {
+		query += ` AND created_at < ` + arg(q.Until)
+	}
+	if cursor != nil {
+		query += ` AND (created_at, id) < (` + arg(cursor.CreatedAt) + `, ` + arg(cursor.ID) + `)`
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ` + arg(size+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*models.AuditEvent
+	for rows.Next() {
+		e := &models.AuditEvent{}
+		var actorID sql.NullInt64
+		var before, after []byte
+		err := rows.Scan(&e.ID, &actorID, &e.Action, &e.EntityType, &e.EntityID, &before, &after,
+			&e.RequestID, &e.IP, &e.UserAgent, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		if actorID.Valid {
+			id := int(actorID.Int64)
+			e.ActorID = &id
+		}
+		e.Before, e.After = before, after
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return NewPage(events, size, AuditCursor), nil
+}
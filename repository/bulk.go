@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/slug"
+
+	"github.com/lib/pq"
+)
+
+// CheckBulkArticle проверяет статью перед массовой загрузкой: известный
+// статус, непустой автор, published_at у опубликованных и архивных статей
+// и порядок временных меток.
+func CheckBulkArticle(a *models.Article) error {
+	if _, ok := articleTransitions[a.Status]; !ok {
+		return fmt.Errorf("%w: unknown article status %q", ErrInvalidInput, a.Status)
+	}
+	if a.AuthorID == 0 {
+		return fmt.Errorf("%w: article %q has no author", ErrInvalidInput, a.Title)
+	}
+	published := a.Status == models.ArticlePublished || a.Status == models.ArticleArchived
+	if published != (a.PublishedAt != nil) {
+		return fmt.Errorf("%w: article %q: published_at does not match status %s", ErrInvalidInput, a.Title, a.Status)
+	}
+	if a.Views < 0 || a.UpdatedAt.Before(a.CreatedAt) {
+		return fmt.Errorf("%w: article %q: negative views or updated_at before created_at", ErrInvalidInput, a.Title)
+	}
+	return nil
+}
+
+// BulkRepository загружает готовые строки командой COPY. Нужен генератору
+// тестовых данных: в отличие от Create, статус, просмотры и временные
+// метки берутся из переданных структур, а журнал аудита и история статусов
+// не пишутся.
+type BulkRepository struct {
+	db *sql.DB
+}
+
+func NewBulkRepository(db *sql.DB) *BulkRepository {
+	return &BulkRepository{db: db}
+}
+
+// InsertUsers добавляет пользователей одной транзакцией и заполняет их ID.
+// Пустая роль заменяется на models.DefaultRole; CreatedAt и UpdatedAt
+// сохраняются как есть.
+func (r *BulkRepository) InsertUsers(ctx context.Context, users []*models.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+	for _, u := range users {
+		if err := defaultRole(u); err != nil {
+			return err
+		}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids, err := reserveIDs(ctx, tx, "users", len(users))
+	if err != nil {
+		return err
+	}
+	err = copyIn(ctx, tx, "users", []string{"id", "email", "name", "role", "created_at", "updated_at"}, len(users), func(i int) []any {
+		u := users[i]
+		return []any{ids[i], u.Email, u.Name, u.Role, u.CreatedAt, u.UpdatedAt}
+	})
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
+	}
+	for i, u := range users {
+		u.ID = ids[i]
+	}
+	return nil
+}
+
+// InsertArticles добавляет статьи одной транзакцией вместе с первой ревизией
+// от автора и заполняет ID и Slug. Запланированные публикации не загружаются.
+func (r *BulkRepository) InsertArticles(ctx context.Context, articles []*models.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	for _, a := range articles {
+		if err := CheckBulkArticle(a); err != nil {
+			return err
+		}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids, err := reserveIDs(ctx, tx, "articles", len(articles))
+	if err != nil {
+		return err
+	}
+	slugs, err := bulkSlugs(ctx, tx, articles, ids)
+	if err != nil {
+		return err
+	}
+
+	err = copyIn(ctx, tx, "articles",
+		[]string{"id", "title", "slug", "content", "author_id", "status", "views", "published_at", "created_at", "updated_at"},
+		len(articles), func(i int) []any {
+			a := articles[i]
+			return []any{ids[i], a.Title, slugs[i], a.Content, a.AuthorID, a.Status, a.Views, a.PublishedAt, a.CreatedAt, a.UpdatedAt}
+		})
+	if err != nil {
+		return err
+	}
+	err = copyIn(ctx, tx, "article_slugs", []string{"slug", "article_id", "created_at"}, len(articles), func(i int) []any {
+		return []any{slugs[i], ids[i], articles[i].CreatedAt}
+	})
+	if err != nil {
+		return err
+	}
+	err = copyIn(ctx, tx, "article_revisions",
+		[]string{"article_id", "revision", "title", "content", "editor_id", "created_at"},
+		len(articles), func(i int) []any {
+			a := articles[i]
+			return []any{ids[i], 1, a.Title, a.Content, a.AuthorID, a.CreatedAt}
+		})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
+	}
+	for i, a := range articles {
+		a.ID = ids[i]
+		a.Slug = slugs[i]
+		a.PublishAt = nil
+		a.UnpublishAt = nil
+	}
+	return nil
+}
+
+// bulkSlugs строит слаги вида slug.WithID. Номер статьи не гарантирует
+// свободный слаг: Create мог выдать "go-5" статье с заголовком "Go 5",
+// поэтому занятые слаги заменяются первым свободным вариантом, как в
+// pickSlug. Между собой слаги пачки не совпадают: последний сегмент — id.
+func bulkSlugs(ctx context.Context, tx *sql.Tx, articles []*models.Article, ids []int) ([]string, error) {
+	if err := lockSlugs(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	slugs := make([]string, len(articles))
+	reserved := make(map[string]bool, len(articles))
+	for i, a := range articles {
+		slugs[i] = slug.WithID(a.Title, ids[i])
+		reserved[slugs[i]] = true
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT slug FROM article_slugs WHERE slug = ANY($1)`, pq.Array(slugs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query slugs: %w", err)
+	}
+	defer rows.Close()
+
+	taken := make(map[string]bool)
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, fmt.Errorf("failed to scan slug: %w", err)
+		}
+		taken[s] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	rows.Close()
+
+	for i, s := range slugs {
+		if !taken[s] {
+			continue
+		}
+		if slugs[i], err = uniqueSlug(ctx, tx, 0, s, reserved); err != nil {
+			return nil, err
+		}
+		reserved[slugs[i]] = true
+	}
+	return slugs, nil
+}
+
+// reserveIDs забирает n значений из последовательности SERIAL-колонки id
+// таблицы, чтобы связанные строки можно было загрузить COPY без RETURNING
+func reserveIDs(ctx context.Context, tx *sql.Tx, table string, n int) ([]int, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT nextval(pg_get_serial_sequence($1, 'id'))
+		FROM generate_series(1, $2)
+	`, table, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve %s ids: %w", table, err)
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0, n)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return ids, nil
+}
+
+// copyIn загружает n строк в table одной командой COPY; row возвращает
+// значения i-й строки в порядке columns
+func copyIn(ctx context.Context, tx *sql.Tx, table string, columns []string, n int, row func(i int) []any) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return fmt.Errorf("failed to start copy into %s: %w", table, err)
+	}
+	defer stmt.Close()
+
+	for i := range n {
+		if _, err := stmt.ExecContext(ctx, row(i)...); err != nil {
+			return fmt.Errorf("failed to copy into %s: %w", table, pgError(err))
+		}
+	}
+	// Вызов без аргументов отправляет буфер и завершает COPY
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("failed to copy into %s: %w", table, pgError(err))
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("failed to copy into %s: %w", table, pgError(err))
+	}
+	return nil
+}
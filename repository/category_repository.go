@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/slug"
+	"strings"
+	"unicode/utf8"
+)
+
+const MaxCategoryNameLength = 64
+
+// CategoryFallbackSlug — слаг рубрики, в имени которой нет букв и цифр
+const CategoryFallbackSlug = "category"
+
+type CategoryUsage struct {
+	Category     *models.Category `json:"category"`
+	ArticleCount int              `json:"article_count"`
+}
+
+type CategoryRepository struct {
+	db *sql.DB
+}
+
+func NewCategoryRepository(db *sql.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// NormalizeCategory убирает лишние пробелы из имени рубрики и проверяет
+// слаг. Пустой слаг строится из имени: "C++" и "C" дают один слаг, поэтому
+// его можно задать явно.
+func NormalizeCategory(c *models.Category) error {
+	c.Name = strings.Join(strings.Fields(c.Name), " ")
+	if c.Name == "" {
+		return fmt.Errorf("%w: empty category name", ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(c.Name) > MaxCategoryNameLength {
+		return fmt.Errorf("%w: category name %q exceeds %d characters", ErrInvalidInput, c.Name, MaxCategoryNameLength)
+	}
+	if c.Slug == "" {
+		c.Slug = slug.MakeOr(c.Name, CategoryFallbackSlug)
+	}
+	if !slug.Valid(c.Slug) || len(c.Slug) > slug.MaxLength {
+		return fmt.Errorf("%w: invalid category slug %q", ErrInvalidInput, c.Slug)
+	}
+	return nil
+}
+
+// categoryError уточняет нарушение уникальности имени или слага
+func categoryError(c *models.Category, action string, err error) error {
+	err = pgError(err)
+	if errors.Is(err, ErrAlreadyExists) {
+		return fmt.Errorf("category %q (%s): %w", c.Name, c.Slug, err)
+	}
+	return fmt.Errorf("failed to %s category: %w", action, err)
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	if err := NormalizeCategory(category); err != nil {
+		return err
+	}
+
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO categories (name, slug) VALUES ($1, $2) RETURNING id, created_at`,
+		category.Name, category.Slug,
+	).Scan(&category.ID, &category.CreatedAt)
+	if err != nil {
+		return categoryError(category, "create", err)
+	}
+	return nil
+}
+
+func (r *CategoryRepository) GetBySlug(ctx context.Context, s string) (*models.Category, error) {
+	category, err := scanCategory(r.db.QueryRowContext(ctx,
+		`SELECT id, name, slug, created_at FROM categories WHERE slug = $1`, s,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("category")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	return category, nil
+}
+
+// List возвращает все рубрики по имени с числом опубликованных статей
+func (r *CategoryRepository) List(ctx context.Context) ([]*CategoryUsage, error) {
+	query := `
+		SELECT c.id, c.name, c.slug, c.created_at, COUNT(a.id)
+		FROM categories c
+		LEFT JOIN article_categories ac ON ac.category_id = c.id
+		LEFT JOIN articles a ON a.id = ac.article_id
+			AND a.status = 'published' AND a.deleted_at IS NULL
+		GROUP BY c.id
+		ORDER BY lower(c.name)
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query categories: %w", err)
+	}
+	defer rows.Close()
+
+	usages := []*CategoryUsage{}
+	for rows.Next() {
+		u := &CategoryUsage{Category: &models.Category{}}
+		err := rows.Scan(&u.Category.ID, &u.Category.Name, &u.Category.Slug, &u.Category.CreatedAt, &u.ArticleCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		usages = append(usages, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return usages, nil
+}
+
+// Update меняет имя и слаг рубрики; статьи остаются в ней. Старый слаг
+// перестает работать.
+func (r *CategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	if err := NormalizeCategory(category); err != nil {
+		return err
+	}
+
+	err := r.db.QueryRowContext(ctx,
+		`UPDATE categories SET name = $1, slug = $2 WHERE id = $3 RETURNING created_at`,
+		category.Name, category.Slug, category.ID,
+	).Scan(&category.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound("category")
+	}
+	if err != nil {
+		return categoryError(category, "update", err)
+	}
+	return nil
+}
+
+// Delete удаляет рубрику; ее статьи остаются без рубрики
+func (r *CategoryRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return notFound("category")
+	}
+
+	return nil
+}
+
+// GetArticleCategory возвращает рубрику статьи или ErrNotFound, если ее нет
+func (r *CategoryRepository) GetArticleCategory(ctx context.Context, articleID int) (*models.Category, error) {
+	category, err := scanCategory(r.db.QueryRowContext(ctx, `
+		SELECT c.id, c.name, c.slug, c.created_at
+		FROM categories c
+		JOIN article_categories ac ON ac.category_id = c.id
+		WHERE ac.article_id = $1
+	`, articleID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("category")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get article category: %w", err)
+	}
+	return category, nil
+}
+
+// SetArticleCategory переносит статью в рубрику categoryID или, при nil,
+// убирает ее из рубрики. Возвращает новую рубрику (nil без рубрики);
+// несуществующая рубрика — ErrConflict, как нарушение внешнего ключа.
+func (r *CategoryRepository) SetArticleCategory(ctx context.Context, articleID int, categoryID *int) (*models.Category, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Блокировка не дает переместить статью в корзину до конца транзакции
+	var id int
+	err = tx.QueryRowContext(ctx, `SELECT id FROM articles WHERE id = $1 AND deleted_at IS NULL FOR SHARE`, articleID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("article")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock article: %w", err)
+	}
+
+	var category *models.Category
+	if categoryID == nil {
+		_, err = tx.ExecContext(ctx, `DELETE FROM article_categories WHERE article_id = $1`, articleID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to clear article category: %w", err)
+		}
+	} else {
+		category, err = scanCategory(tx.QueryRowContext(ctx, `
+			WITH upsert AS (
+				INSERT INTO article_categories (article_id, category_id) VALUES ($1, $2)
+				ON CONFLICT (article_id) DO UPDATE SET category_id = EXCLUDED.category_id
+			)
+			SELECT id, name, slug, created_at FROM categories WHERE id = $2
+		`, articleID, *categoryID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to set article category: %w", pgError(err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", pgError(err))
+	}
+	return category, nil
+}
+
+// GetArticlesByCategory возвращает статьи рубрики, новые первыми
+func (r *CategoryRepository) GetArticlesByCategory(ctx context.Context, categoryID int) ([]*models.Article, error) {
+	query := `
+		SELECT a.id, a.title, a.slug, a.content, a.author_id, a.status, a.published_at, a.publish_at, a.unpublish_at, a.views, a.created_at, a.updated_at
+		FROM articles a
+		JOIN article_categories ac ON ac.article_id = a.id
+		WHERE ac.category_id = $1 AND a.deleted_at IS NULL
+		ORDER BY a.created_at DESC, a.id DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query articles: %w", err)
+	}
+	defer rows.Close()
+
+	articles, err := scanArticles(rows)
+	if err != nil {
+		return nil, err
+	}
+	if articles == nil {
+		articles = []*models.Article{}
+	}
+	return articles, nil
+}
+
+func scanCategory(row rowScanner) (*models.Category, error) {
+	c := &models.Category{}
+	if err := row.Scan(&c.ID, &c.Name, &c.Slug, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,339 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+)
+
+const (
+	MaxCommentLength = 10000
+	// DeletedCommentBody заменяет текст удаленного комментария в публичной ветке
+	DeletedCommentBody = "[deleted]"
+)
+
+// commentTransitions — допустимые переходы модерации
+var commentTransitions = map[models.CommentStatus][]models.CommentStatus{
+	models.CommentPending:  {models.CommentApproved, models.CommentRejected, models.CommentDeleted},
+	models.CommentApproved: {models.CommentRejected, models.CommentDeleted},
+	models.CommentRejected: {models.CommentApproved, models.CommentDeleted},
+}
+
+// CheckCommentTransition проверяет, можно ли перевести комментарий из from в to
+func CheckCommentTransition(from, to models.CommentStatus) error {
+	switch to {
+	case models.CommentPending, models.CommentApproved, models.CommentRejected, models.CommentDeleted:
+	default:
+		return fmt.Errorf("%w: unknown comment status %q", ErrInvalidInput, to)
+	}
+	for _, allowed := range commentTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: comment cannot move from %s to %s", ErrConflict, from, to)
+}
+
+// NormalizeCommentBody обрезает пробелы и проверяет длину текста комментария
+func NormalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: empty comment body", ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(body) > MaxCommentLength {
+		return "", fmt.Errorf("%w: comment exceeds %d characters", ErrInvalidInput, MaxCommentLength)
+	}
+	return body, nil
+}
+
+// VisibleCommentStatuses возвращает статусы, попадающие в ветку.
+// Публично видны одобренные комментарии и заглушки удаленных.
+func VisibleCommentStatuses(includeHidden bool) []models.CommentStatus {
+	if includeHidden {
+		return []models.CommentStatus{models.CommentPending, models.CommentApproved, models.CommentRejected, models.CommentDeleted}
+	}
+	return []models.CommentStatus{models.CommentApproved, models.CommentDeleted}
+}
+
+type CommentRepository struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// Create добавляет комментарий. Ответ (ParentID != nil) должен относиться к той же
+// статье, что и родитель; на отклоненные и удаленные комментарии отвечать нельзя.
+// Пустой Status означает pending.
+func (r *CommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	body, err := NormalizeCommentBody(comment.Body)
+	if err != nil {
+		return err
+	}
+	if comment.Status == "" {
+		comment.Status = models.CommentPending
+	}
+	if comment.Status != models.CommentPending && comment.Status != models.CommentApproved {
+		return fmt.Errorf("%w: new comment cannot be %s", ErrInvalidInput, comment.Status)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM articles WHERE id = $1 AND deleted_at IS NULL)`, comment.ArticleID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check article: %w", err)
+	}
+	if !exists {
+		return notFound("article")
+	}
+	if err := checkActiveUser(ctx, tx, comment.AuthorID, "author"); err != nil {
+		return err
+	}
+
+	if comment.ParentID != nil {
+		var parentArticleID int
+		var parentStatus models.CommentStatus
+		// FOR SHARE не дает параллельно удалить родителя до вставки ответа
+		err := tx.QueryRowContext(ctx,
+			`SELECT article_id, status FROM comments WHERE id = $1 FOR SHARE`, *comment.ParentID,
+		).Scan(&parentArticleID, &parentStatus)
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFound("parent comment")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get parent comment: %w", err)
+		}
+		if parentArticleID != comment.ArticleID {
+			return fmt.Errorf("%w: parent comment belongs to another article", ErrInvalidInput)
+		}
+		if parentStatus == models.CommentRejected || parentStatus == models.CommentDeleted {
+			return fmt.Errorf("%w: cannot reply to a %s comment", ErrConflict, parentStatus)
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO comments (article_id, author_id, parent_id, body, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, comment.ArticleID, comment.AuthorID, comment.ParentID, body, comment.Status,
+	).Scan(&comment.ID, &comment.CreatedAt, &comment.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create comment: %w", pgError(err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
+	}
+
+	comment.Body = body
+	comment.Depth = 0
+	return nil
+}
+
+func (r *CommentRepository) GetByID(ctx context.Context, id int) (*models.Comment, error) {
+	query := `
+		SELECT id, article_id, author_id, parent_id, body, status, 0, created_at, updated_at
+		FROM comments
+		WHERE id = $1
+	`
+	comment, err := scanComment(r.db.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("comment")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	return comment, nil
+}
+
+// GetThread возвращает все комментарии статьи одним рекурсивным запросом в порядке
+// обхода дерева (родитель, затем его ответы по времени). Без includeHidden в ветку
+// попадают только одобренные комментарии и заглушки удаленных; ответы на скрытые
+// комментарии не показываются.
+func (r *CommentRepository) GetThread(ctx context.Context, articleID int, includeHidden bool) ([]*models.Comment, error) {
+	query := `
+		WITH RECURSIVE thread AS (
+			SELECT id, article_id, author_id, parent_id, body, status, created_at, updated_at,
+				0 AS depth, ARRAY[id] AS path
+			FROM comments
+			WHERE article_id = $1 AND parent_id IS NULL AND status = ANY($2::text[])
+				AND EXISTS (SELECT 1 FROM articles WHERE id = $1 AND deleted_at IS NULL)
+
+			UNION ALL
+
+			SELECT c.id, c.article_id, c.author_id, c.parent_id, c.body, c.status, c.created_at, c.updated_at,
+				t.depth + 1, t.path || c.id
+			FROM comments c
+			JOIN thread t ON c.parent_id = t.id
+			WHERE c.status = ANY($2::text[])
+		)
+		SELECT id, article_id, author_id, parent_id,
+			CASE WHEN status = 'deleted' AND NOT $3 THEN $4 ELSE body END,
+			status, depth, created_at, updated_at
+		FROM thread
+		ORDER BY path
+	`
+
+	statuses := VisibleCommentStatuses(includeHidden)
+	rows, err := r.db.QueryContext(ctx, query, articleID, pq.Array(statuses), includeHidden, DeletedCommentBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer rows.Close()
+
+	return scanComments(rows)
+}
+
+// ListByStatus возвращает очередь модерации: комментарии со статусом status, старые первыми
+func (r *CommentRepository) ListByStatus(ctx context.Context, status models.CommentStatus, limit int) ([]*models.Comment, error) {
+	query := `
+		SELECT id, article_id, author_id, parent_id, body, status, 0, created_at, updated_at
+		FROM comments
+		WHERE status = $1
+			AND article_id IN (SELECT id FROM articles WHERE deleted_at IS NULL)
+		ORDER BY created_at, id
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, status, PageRequest{Limit: limit}.PageSize())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query comments: %w", err)
+	}
+	defer rows.Close()
+
+	return scanComments(rows)
+}
+
+// Moderate переводит комментарий в новый статус с проверкой допустимости перехода
+func (r *CommentRepository) Moderate(ctx context.Context, id int, status models.CommentStatus) (*models.Comment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var current models.CommentStatus
+	err = tx.QueryRowContext(ctx, `SELECT status FROM comments WHERE id = $1 FOR UPDATE`, id).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("comment")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	if err := CheckCommentTransition(current, status); err != nil {
+		return nil, err
+	}
+
+	comment, err := scanComment(tx.QueryRowContext(ctx, `
+		UPDATE comments SET status = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING id, article_id, author_id, parent_id, body, status, 0, created_at, updated_at
+	`, status, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to moderate comment: %w", pgError(err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", pgError(err))
+	}
+	return comment, nil
+}
+
+// Delete помечает комментарий удаленным. Строка остается, чтобы ответы
+// сохранили свое место в ветке, а в публичной ветке показывается заглушка.
+func (r *CommentRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.Moderate(ctx, id, models.CommentDeleted)
+	return err
+}
+
+// CountByArticles возвращает число одобренных комментариев для каждой статьи из ids
+func (r *CommentRepository) CountByArticles(ctx context.Context, articleIDs []int) (map[int]int, error) {
+	counts := make(map[int]int, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return counts, nil
+	}
+
+	ids := make([]int64, len(articleIDs))
+	for i, id := range articleIDs {
+		ids[i] = int64(id)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT article_id, COUNT(*)
+		FROM comments
+		WHERE article_id = ANY($1::int[]) AND status = 'approved'
+		GROUP BY article_id
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleID, count int
+		if err := rows.Scan(&articleID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan comment count: %w", err)
+		}
+		counts[articleID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return counts, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanComment(row rowScanner) (*models.Comment, error) {
+	comment := &models.Comment{}
+	var parentID sql.NullInt64
+	err := row.Scan(
+		&comment.ID,
+		&comment.ArticleID,
+		&comment.AuthorID,
+		&parentID,
+		&comment.Body,
+		&comment.Status,
+		&comment.Depth,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		comment.ParentID = &id
+	}
+	return comment, nil
+}
+
+func scanComments(rows *sql.Rows) ([]*models.Comment, error) {
+	comments := []*models.Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return comments, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrAlreadyExists    = errors.New("already exists")
+	ErrConflict         = errors.New("conflict")
+	ErrAlreadyPublished = errors.New("article already published")
+	ErrInvalidQuery     = errors.New("invalid query")
+	ErrInvalidInput     = errors.New("invalid input")
+)
+
+// Коды SQLSTATE, которые переводятся в sentinel-ошибки
+const (
+	codeUniqueViolation      = "23505"
+	codeForeignKeyViolation  = "23503"
+	codeCheckViolation       = "23514"
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+)
+
+func notFound(entity string) error {
+	return fmt.Errorf("%s %w", entity, ErrNotFound)
+}
+
+// pgError сопоставляет ошибку PostgreSQL с sentinel-ошибкой по коду SQLSTATE.
+// Исходная *pq.Error остается в цепочке и доступна через errors.As.
+func pgError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code {
+	case codeUniqueViolation:
+		return fmt.Errorf("%w: %w", ErrAlreadyExists, err)
+	case codeForeignKeyViolation, codeCheckViolation, codeSerializationFailure, codeDeadlockDetected:
+		return fmt.Errorf("%w: %w", ErrConflict, err)
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"slices"
+	"sort"
+	"strings"
+)
+
+func (r *ArticleRepository) Find(ctx context.Context, q repository.ArticleQuery) (*repository.ArticleList, error) {
+	q, err := q.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	articles, err := r.list(ctx, func(a *models.Article) bool { return matchFilter(q.Filter, a) })
+	if err != nil {
+		return nil, err
+	}
+
+	less := sortKey(q.SortBy)
+	sort.SliceStable(articles, func(i, j int) bool {
+		if q.Ascending {
+			return less(articles[i], articles[j])
+		}
+		return less(articles[j], articles[i])
+	})
+
+	result := &repository.ArticleList{Total: len(articles), Articles: []*models.Article{}}
+	if q.Offset < len(articles) {
+		end := min(q.Offset+q.Limit, len(articles))
+		result.Articles = articles[q.Offset:end]
+	}
+	return result, nil
+}
+
+func matchFilter(f repository.ArticleFilter, a *models.Article) bool {
+	if len(f.AuthorIDs) > 0 && !slices.Contains(f.AuthorIDs, a.AuthorID) {
+		return false
+	}
+	if f.Published != nil && a.IsPublished() != *f.Published {
+		return false
+	}
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, a.Status) {
+		return false
+	}
+	if !f.CreatedFrom.IsZero() && a.CreatedAt.Before(f.CreatedFrom) {
+		return false
+	}
+	if !f.CreatedTo.IsZero() && !a.CreatedAt.Before(f.CreatedTo) {
+		return false
+	}
+	if a.Views < f.MinViews {
+		return false
+	}
+	if f.TitleContains != "" && !strings.Contains(strings.ToLower(a.Title), strings.ToLower(f.TitleContains)) {
+		return false
+	}
+	return true
+}
+
+// sortKey возвращает сравнение "a < b" по полю сортировки с id для равных значений
+func sortKey(field repository.ArticleSortField) func(a, b *models.Article) bool {
+	return func(a, b *models.Article) bool {
+		switch field {
+		case repository.SortByViews:
+			if a.Views != b.Views {
+				return a.Views < b.Views
+			}
+		case repository.SortByUpdatedAt:
+			if !a.UpdatedAt.Equal(b.UpdatedAt) {
+				return a.UpdatedAt.Before(b.UpdatedAt)
+			}
+		default:
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+		}
+		return a.ID < b.ID
+	}
+}
@@ -0,0 +1,245 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"sort"
+)
+
+type ArticleRepository struct {
+	store *Store
+}
+
+var _ repository.ArticleStore = (*ArticleRepository)(nil)
+
+func (r *ArticleRepository) Create(ctx context.Context, article *models.Article) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.insertArticle(article, actorOr(ctx, article.AuthorID)); err != nil {
+		return err
+	}
+	return r.store.record(ctx, models.AuditCreate, models.AuditArticleEntity, article.ID, nil, article)
+}
+
+func (r *ArticleRepository) GetByID(ctx context.Context, id int) (*models.Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	a, ok := r.store.articles[id]
+	if !ok {
+		return nil, notFound("article")
+	}
+	article := *a
+	return &article, nil
+}
+
+func (r *ArticleRepository) GetByAuthorID(ctx context.Context, authorID int) ([]*models.Article, error) {
+	return r.list(ctx, func(a *models.Article) bool { return a.AuthorID == authorID })
+}
+
+func (r *ArticleRepository) GetPublished(ctx context.Context) ([]*models.Article, error) {
+	return r.list(ctx, func(a *models.Article) bool { return a.IsPublished() })
+}
+
+// list возвращает копии статей, отсортированные по created_at DESC
+func (r *ArticleRepository) list(ctx context.Context, match func(*models.Article) bool) ([]*models.Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var articles []*models.Article
+	for _, a := range r.store.articles {
+		if match(a) {
+			article := *a
+			articles = append(articles, &article)
+		}
+	}
+	sort.Slice(articles, func(i, j int) bool {
+		if !articles[i].CreatedAt.Equal(articles[j].CreatedAt) {
+			return articles[i].CreatedAt.After(articles[j].CreatedAt)
+		}
+		return articles[i].ID > articles[j].ID
+	})
+	return articles, nil
+}
+
+func (r *ArticleRepository) Update(ctx context.Context, article *models.Article) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.articles[article.ID]
+	if !ok {
+		return notFound("article")
+	}
+	if err := checkVarchar(article.Title); err != nil {
+		return fmt.Errorf("failed to update article: %w", err)
+	}
+	if _, ok := r.store.users[article.AuthorID]; !ok {
+		return fmt.Errorf("failed to update article: author %d: %w", article.AuthorID, repository.ErrConflict)
+	}
+	editorID := actorID(ctx)
+	if err := r.store.checkEditor(editorID); err != nil {
+		return err
+	}
+
+	before := *stored
+	article.UpdatedAt = r.store.now()
+	r.store.reslug(stored, article.Title)
+	r.store.invalidateRendered(stored, article.Content)
+	stored.Title = article.Title
+	stored.Content = article.Content
+	stored.AuthorID = article.AuthorID
+	stored.UpdatedAt = article.UpdatedAt
+	// Статус и расписание меняются только через Transition и Schedule
+	article.Slug = stored.Slug
+	article.Status = stored.Status
+	article.PublishedAt = stored.PublishedAt
+	article.PublishAt = stored.PublishAt
+	article.UnpublishAt = stored.UnpublishAt
+	r.store.addRevision(stored, editorID)
+	return r.store.record(ctx, models.AuditUpdate, models.AuditArticleEntity, article.ID, &before, stored)
+}
+
+func (r *ArticleRepository) Publish(ctx context.Context, id int) error {
+	_, err := r.Transition(ctx, id, models.ArticlePublished)
+	return err
+}
+
+func (r *ArticleRepository) IncrementViews(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	a, ok := r.store.articles[id]
+	if !ok {
+		return notFound("article")
+	}
+	a.Views++
+	return nil
+}
+
+func (r *ArticleRepository) CreateArticleWithAuthor(
+	ctx context.Context,
+	userName, userEmail, articleTitle, articleContent string,
+) (*models.User, *models.Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// Вся операция выполняется под одной блокировкой, что эквивалентно транзакции
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var user models.User
+	created := false
+	if u := r.store.userByEmail(userEmail); u != nil {
+		user = *u
+	} else {
+		user = models.User{Email: userEmail, Name: userName}
+		if err := r.store.insertUser(&user); err != nil {
+			return nil, nil, fmt.Errorf("create user: %w", err)
+		}
+		created = true
+	}
+
+	article := models.Article{Title: articleTitle, Content: articleContent, AuthorID: user.ID}
+	if err := r.store.insertArticle(&article, actorOr(ctx, user.ID)); err != nil {
+		// Откат: пользователь, созданный в этой "транзакции", удаляется,
+		// а счетчик id, как и sequence в PostgreSQL, не возвращается
+		if created {
+			delete(r.store.users, user.ID)
+		}
+		return nil, nil, fmt.Errorf("create article: %w", err)
+	}
+
+	if created {
+		if err := r.store.record(ctx, models.AuditCreate, models.AuditUserEntity, user.ID, nil, &user); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := r.store.record(ctx, models.AuditCreate, models.AuditArticleEntity, article.ID, nil, &article); err != nil {
+		return nil, nil, err
+	}
+	return &user, &article, nil
+}
+
+func (r *ArticleRepository) GetArticleWithAuthor(ctx context.Context, id int) (*repository.ArticleWithAuthor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	a, ok := r.store.articles[id]
+	if !ok {
+		return nil, notFound("article")
+	}
+	author, ok := r.store.users[a.AuthorID]
+	if !ok {
+		return nil, notFound("article")
+	}
+
+	article := *a
+	return &repository.ArticleWithAuthor{
+		Article:     &article,
+		AuthorName:  author.Name,
+		AuthorEmail: author.Email,
+	}, nil
+}
+
+func (r *ArticleRepository) GetByAuthorIDPage(ctx context.Context, authorID int, includeDrafts bool, page repository.PageRequest) (*repository.Page[*models.Article], error) {
+	return r.listPage(ctx, func(a *models.Article) bool {
+		return a.AuthorID == authorID && (includeDrafts || a.IsPublished())
+	}, page)
+}
+
+func (r *ArticleRepository) GetPublishedPage(ctx context.Context, page repository.PageRequest) (*repository.Page[*models.Article], error) {
+	return r.listPage(ctx, func(a *models.Article) bool { return a.IsPublished() }, page)
+}
+
+func (r *ArticleRepository) listPage(ctx context.Context, match func(*models.Article) bool, page repository.PageRequest) (*repository.Page[*models.Article], error) {
+	var cursor *repository.Cursor
+	if page.Cursor != "" {
+		c, err := repository.DecodeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &c
+	}
+
+	// list уже отсортирован по (created_at, id) DESC
+	articles, err := r.list(ctx, func(a *models.Article) bool {
+		return match(a) && (cursor == nil || cursorBefore(repository.ArticleCursor(a), *cursor))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	size := page.PageSize()
+	if len(articles) > size+1 {
+		articles = articles[:size+1]
+	}
+	return repository.NewPage(articles, size, repository.ArticleCursor), nil
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"slices"
+)
+
+type AuditRepository struct {
+	store *Store
+}
+
+var _ repository.AuditStore = (*AuditRepository)(nil)
+
+// record дописывает событие в журнал. Вызывается под блокировкой на запись
+// в той же критической секции, что и изменение.
+func (s *Store) record(ctx context.Context, action models.AuditAction, entity models.AuditEntity, id int, before, after any) error {
+	event, err := repository.NewAuditEvent(ctx, action, entity, id, before, after)
+	if err != nil {
+		return err
+	}
+	s.lastAuditID++
+	event.ID = s.lastAuditID
+	event.CreatedAt = s.now()
+	s.audit = append(s.audit, event)
+	return nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, q repository.AuditQuery) (*repository.Page[*models.AuditEvent], error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	var cursor *repository.Cursor
+	if q.Page.Cursor != "" {
+		c, err := repository.DecodeCursor(q.Page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &c
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var events []*models.AuditEvent
+	for _, e := range r.store.audit {
+		if matchAudit(e, q) && (cursor == nil || cursorBefore(repository.AuditCursor(e), *cursor)) {
+			events = append(events, copyAuditEvent(e))
+		}
+	}
+	slices.SortFunc(events, func(a, b *models.AuditEvent) int {
+		switch {
+		case cursorBefore(repository.AuditCursor(b), repository.AuditCursor(a)):
+			return -1
+		case cursorBefore(repository.AuditCursor(a), repository.AuditCursor(b)):
+			return 1
+		}
+		return 0
+	})
+
+	size := q.Page.PageSize()
+	if len(events) > size+1 {
+		events = events[:size+1]
+	}
+	return repository.NewPage(events, size, repository.AuditCursor), nil
+}
+
+func matchAudit(e *models.AuditEvent, q repository.AuditQuery) bool {
+	switch {
+	case q.EntityType != "" && e.EntityType != q.EntityType:
+		return false
+	case q.EntityID != 0 && e.EntityID != q.EntityID:
+		return false
+	case q.ActorID != 0 && (e.ActorID == nil || *e.ActorID != q.ActorID):
+		return false
+	case q.Action != "" && e.Action != q.Action:
+		return false
+	case !q.Since.IsZero() && e.CreatedAt.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && !e.CreatedAt.Before(q.Until):
+		return false
+	}
+	return true
+}
+
+func copyAuditEvent(e *models.AuditEvent) *models.AuditEvent {
+	c := *e
+	c.ActorID = copyIntPtr(e.ActorID)
+	c.Before = slices.Clone(e.Before)
+	c.After = slices.Clone(e.After)
+	return &c
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"go-articles-app/slug"
+)
+
+type BulkRepository struct {
+	store *Store
+}
+
+var _ repository.BulkStore = (*BulkRepository)(nil)
+
+func (r *BulkRepository) InsertUsers(ctx context.Context, users []*models.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Сначала проверяются все строки, чтобы ошибка не оставила часть пачки
+	emails := make(map[string]bool, len(users))
+	for _, u := range users {
+		if u.Role == "" {
+			u.Role = models.DefaultRole
+		}
+		if !u.Role.Valid() {
+			return fmt.Errorf("%w: unknown role %q", repository.ErrInvalidInput, u.Role)
+		}
+		if err := checkVarchar(u.Email); err != nil {
+			return err
+		}
+		if err := checkVarchar(u.Name); err != nil {
+			return err
+		}
+		if emails[u.Email] || r.store.emailTaken(u.Email, 0) {
+			return fmt.Errorf("user with email %s: %w", u.Email, repository.ErrAlreadyExists)
+		}
+		emails[u.Email] = true
+	}
+
+	for _, u := range users {
+		r.store.lastUserID++
+		u.ID = r.store.lastUserID
+		stored := *u
+		stored.DeletedAt = nil
+		r.store.users[u.ID] = &stored
+	}
+	return nil
+}
+
+func (r *BulkRepository) InsertArticles(ctx context.Context, articles []*models.Article) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, a := range articles {
+		if err := repository.CheckBulkArticle(a); err != nil {
+			return err
+		}
+		if err := checkVarchar(a.Title); err != nil {
+			return err
+		}
+		if !r.store.userExists(a.AuthorID) {
+			return fmt.Errorf("failed to copy into articles: author %d: %w", a.AuthorID, repository.ErrConflict)
+		}
+	}
+
+	for _, a := range articles {
+		r.store.lastArticleID++
+		a.ID = r.store.lastArticleID
+		a.Slug = r.store.uniqueSlug(a.ID, slug.WithID(a.Title, a.ID))
+		a.PublishAt = nil
+		a.UnpublishAt = nil
+
+		stored := *a
+		stored.DeletedAt = nil
+		r.store.articles[a.ID] = &stored
+		r.store.slugs[a.Slug] = a.ID
+
+		// Первая ревизия датируется созданием статьи, как в PostgreSQL-реализации
+		rev := stored
+		rev.UpdatedAt = stored.CreatedAt
+		r.store.addRevision(&rev, &a.AuthorID)
+	}
+	return nil
+}
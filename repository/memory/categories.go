@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"sort"
+	"strings"
+)
+
+type CategoryRepository struct {
+	store *Store
+}
+
+var _ repository.CategoryStore = (*CategoryRepository)(nil)
+
+// checkCategoryUnique повторяет уникальные индексы по lower(name) и slug.
+// Вызывается под блокировкой.
+func (s *Store) checkCategoryUnique(c *models.Category) error {
+	for _, other := range s.categories {
+		if other.ID != c.ID && (strings.EqualFold(other.Name, c.Name) || other.Slug == c.Slug) {
+			return fmt.Errorf("category %q (%s): %w", c.Name, c.Slug, repository.ErrAlreadyExists)
+		}
+	}
+	return nil
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	if err := repository.NormalizeCategory(category); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	category.ID = 0
+	if err := r.store.checkCategoryUnique(category); err != nil {
+		return err
+	}
+	r.store.lastCategoryID++
+	category.ID = r.store.lastCategoryID
+	category.CreatedAt = r.store.now()
+
+	stored := *category
+	r.store.categories[category.ID] = &stored
+	return nil
+}
+
+func (r *CategoryRepository) GetBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, c := range r.store.categories {
+		if c.Slug == slug {
+			category := *c
+			return &category, nil
+		}
+	}
+	return nil, notFound("category")
+}
+
+func (r *CategoryRepository) List(ctx context.Context) ([]*repository.CategoryUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[int]int)
+	for articleID, categoryID := range r.store.articleCats {
+		// Черновики и статьи из корзины не учитываются
+		if a, ok := r.store.articles[articleID]; ok && a.Status == models.ArticlePublished {
+			counts[categoryID]++
+		}
+	}
+
+	usages := []*repository.CategoryUsage{}
+	for _, c := range r.store.categories {
+		category := *c
+		usages = append(usages, &repository.CategoryUsage{Category: &category, ArticleCount: counts[c.ID]})
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		return strings.ToLower(usages[i].Category.Name) < strings.ToLower(usages[j].Category.Name)
+	})
+	return usages, nil
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	if err := repository.NormalizeCategory(category); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.categories[category.ID]
+	if !ok {
+		return notFound("category")
+	}
+	if err := r.store.checkCategoryUnique(category); err != nil {
+		return err
+	}
+	stored.Name = category.Name
+	stored.Slug = category.Slug
+	category.CreatedAt = stored.CreatedAt
+	return nil
+}
+
+func (r *CategoryRepository) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.categories[id]; !ok {
+		return notFound("category")
+	}
+	delete(r.store.categories, id)
+
+	// ON DELETE CASCADE для article_categories
+	for articleID, categoryID := range r.store.articleCats {
+		if categoryID == id {
+			delete(r.store.articleCats, articleID)
+		}
+	}
+	return nil
+}
+
+func (r *CategoryRepository) GetArticleCategory(ctx context.Context, articleID int) (*models.Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	categoryID, ok := r.store.articleCats[articleID]
+	if !ok {
+		return nil, notFound("category")
+	}
+	category := *r.store.categories[categoryID]
+	return &category, nil
+}
+
+func (r *CategoryRepository) SetArticleCategory(ctx context.Context, articleID int, categoryID *int) (*models.Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.articles[articleID]; !ok {
+		return nil, notFound("article")
+	}
+	if categoryID == nil {
+		delete(r.store.articleCats, articleID)
+		return nil, nil
+	}
+
+	c, ok := r.store.categories[*categoryID]
+	if !ok {
+		return nil, fmt.Errorf("failed to set article category: category %d: %w", *categoryID, repository.ErrConflict)
+	}
+	r.store.articleCats[articleID] = c.ID
+	category := *c
+	return &category, nil
+}
+
+func (r *CategoryRepository) GetArticlesByCategory(ctx context.Context, categoryID int) ([]*models.Article, error) {
+	articles, err := r.store.Articles().list(ctx, func(a *models.Article) bool {
+		// Вызывается под RLock из list
+		id, ok := r.store.articleCats[a.ID]
+		return ok && id == categoryID
+	})
+	if err != nil {
+		return nil, err
+	}
+	if articles == nil {
+		articles = []*models.Article{}
+	}
+	return articles, nil
+}
@@ -0,0 +1,219 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"sort"
+)
+
+type CommentRepository struct {
+	store *Store
+}
+
+var _ repository.CommentStore = (*CommentRepository)(nil)
+
+func (r *CommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	body, err := repository.NormalizeCommentBody(comment.Body)
+	if err != nil {
+		return err
+	}
+	if comment.Status == "" {
+		comment.Status = models.CommentPending
+	}
+	if comment.Status != models.CommentPending && comment.Status != models.CommentApproved {
+		return fmt.Errorf("%w: new comment cannot be %s", repository.ErrInvalidInput, comment.Status)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.articles[comment.ArticleID]; !ok {
+		return notFound("article")
+	}
+	if comment.ParentID != nil {
+		parent, ok := r.store.comments[*comment.ParentID]
+		if !ok {
+			return notFound("parent comment")
+		}
+		if parent.ArticleID != comment.ArticleID {
+			return fmt.Errorf("%w: parent comment belongs to another article", repository.ErrInvalidInput)
+		}
+		if parent.Status == models.CommentRejected || parent.Status == models.CommentDeleted {
+			return fmt.Errorf("%w: cannot reply to a %s comment", repository.ErrConflict, parent.Status)
+		}
+	}
+	if _, ok := r.store.users[comment.AuthorID]; !ok {
+		return fmt.Errorf("failed to create comment: author %d: %w", comment.AuthorID, repository.ErrConflict)
+	}
+
+	now := r.store.now()
+	r.store.lastCommentID++
+	comment.ID = r.store.lastCommentID
+	comment.Body = body
+	comment.Depth = 0
+	comment.CreatedAt = now
+	comment.UpdatedAt = now
+
+	stored := *comment
+	if comment.ParentID != nil {
+		parentID := *comment.ParentID
+		stored.ParentID = &parentID
+	}
+	r.store.comments[comment.ID] = &stored
+	return nil
+}
+
+func (r *CommentRepository) GetByID(ctx context.Context, id int) (*models.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	c, ok := r.store.comments[id]
+	if !ok {
+		return nil, notFound("comment")
+	}
+	return copyComment(c), nil
+}
+
+func (r *CommentRepository) GetThread(ctx context.Context, articleID int, includeHidden bool) ([]*models.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	visible := make(map[models.CommentStatus]bool)
+	for _, status := range repository.VisibleCommentStatuses(includeHidden) {
+		visible[status] = true
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// parent_id -> ответы; корневые комментарии лежат под ключом 0
+	children := make(map[int][]*models.Comment)
+	_, live := r.store.articles[articleID]
+	for _, c := range r.store.comments {
+		if !live || c.ArticleID != articleID || !visible[c.Status] {
+			continue
+		}
+		parentID := 0
+		if c.ParentID != nil {
+			parentID = *c.ParentID
+		}
+		children[parentID] = append(children[parentID], c)
+	}
+	for _, list := range children {
+		sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	}
+
+	thread := []*models.Comment{}
+	var walk func(parentID, depth int)
+	walk = func(parentID, depth int) {
+		for _, c := range children[parentID] {
+			comment := copyComment(c)
+			comment.Depth = depth
+			if comment.Status == models.CommentDeleted && !includeHidden {
+				comment.Body = repository.DeletedCommentBody
+			}
+			thread = append(thread, comment)
+			walk(c.ID, depth+1)
+		}
+	}
+	walk(0, 0)
+	return thread, nil
+}
+
+func (r *CommentRepository) ListByStatus(ctx context.Context, status models.CommentStatus, limit int) ([]*models.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	comments := []*models.Comment{}
+	for _, c := range r.store.comments {
+		// Комментарии к статьям из корзины не попадают в очередь модерации
+		if _, live := r.store.articles[c.ArticleID]; live && c.Status == status {
+			comments = append(comments, copyComment(c))
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		return cursorBefore(commentCursor(comments[i]), commentCursor(comments[j]))
+	})
+
+	if size := (repository.PageRequest{Limit: limit}).PageSize(); len(comments) > size {
+		comments = comments[:size]
+	}
+	return comments, nil
+}
+
+func (r *CommentRepository) Moderate(ctx context.Context, id int, status models.CommentStatus) (*models.Comment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	c, ok := r.store.comments[id]
+	if !ok {
+		return nil, notFound("comment")
+	}
+	if err := repository.CheckCommentTransition(c.Status, status); err != nil {
+		return nil, err
+	}
+
+	c.Status = status
+	c.UpdatedAt = r.store.now()
+	return copyComment(c), nil
+}
+
+func (r *CommentRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.Moderate(ctx, id, models.CommentDeleted)
+	return err
+}
+
+func (r *CommentRepository) CountByArticles(ctx context.Context, articleIDs []int) (map[int]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[int]bool, len(articleIDs))
+	for _, id := range articleIDs {
+		wanted[id] = true
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[int]int, len(articleIDs))
+	for _, c := range r.store.comments {
+		if wanted[c.ArticleID] && c.Status == models.CommentApproved {
+			counts[c.ArticleID]++
+		}
+	}
+	return counts, nil
+}
+
+// copyComment копирует комментарий вместе с ParentID, чтобы вызывающий код
+// не мог изменить хранимое значение
+func copyComment(c *models.Comment) *models.Comment {
+	comment := *c
+	if c.ParentID != nil {
+		parentID := *c.ParentID
+		comment.ParentID = &parentID
+	}
+	return &comment
+}
+
+func commentCursor(c *models.Comment) repository.Cursor {
+	return repository.Cursor{CreatedAt: c.CreatedAt, ID: c.ID}
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"sort"
+	"time"
+)
+
+func (r *ArticleRepository) ListFeed(ctx context.Context, q repository.FeedQuery) ([]*repository.ArticleWithAuthor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	items := []*repository.ArticleWithAuthor{}
+	for _, a := range r.store.articles {
+		if !a.IsPublished() || (q.AuthorID != 0 && a.AuthorID != q.AuthorID) {
+			continue
+		}
+		if q.TagID != 0 && !r.store.articleTags[a.ID][q.TagID] {
+			continue
+		}
+		author, ok := r.store.users[a.AuthorID]
+		if !ok {
+			continue
+		}
+		article := *a
+		items = append(items, &repository.ArticleWithAuthor{
+			Article:     &article,
+			AuthorName:  author.Name,
+			AuthorEmail: author.Email,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		ti, tj := feedTime(items[i].Article), feedTime(items[j].Article)
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return items[i].Article.ID > items[j].Article.ID
+	})
+	if limit := (repository.PageRequest{Limit: q.Limit}).PageSize(); len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// feedTime повторяет COALESCE(published_at, created_at)
+func feedTime(a *models.Article) time.Time {
+	if a.PublishedAt != nil {
+		return *a.PublishedAt
+	}
+	return a.CreatedAt
+}
@@ -0,0 +1,24 @@
+package memory_test
+
+import (
+	"go-articles-app/repository/memory"
+	"go-articles-app/repository/storetest"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		store := memory.NewStore()
+		return storetest.Stores{
+			Users:      store.Users(),
+			Articles:   store.Articles(),
+			Tags:       store.Tags(),
+			Categories: store.Categories(),
+			Comments:   store.Comments(),
+			Sessions:   store.Sessions(),
+			Audit:      store.Audit(),
+			Stats:      store.Stats(),
+			Bulk:       store.Bulk(),
+		}
+	})
+}
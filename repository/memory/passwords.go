@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"go-articles-app/models"
+)
+
+func (r *UserRepository) CreateWithPassword(ctx context.Context, user *models.User, passwordHash string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.insertUser(user); err != nil {
+		return err
+	}
+	r.store.passwords[user.ID] = passwordHash
+	return r.store.record(ctx, models.AuditCreate, models.AuditUserEntity, user.ID, nil, user)
+}
+
+func (r *UserRepository) GetCredentials(ctx context.Context, email string) (*models.User, string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	u := r.store.userByEmail(email)
+	if u == nil {
+		return nil, "", notFound("user")
+	}
+	user := *u
+	return &user, r.store.passwords[u.ID], nil
+}
+
+func (r *UserRepository) SetPasswordHash(ctx context.Context, id int, passwordHash string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	u, ok := r.store.users[id]
+	if !ok {
+		return notFound("user")
+	}
+	u.UpdatedAt = r.store.now()
+	r.store.passwords[id] = passwordHash
+	return r.store.record(ctx, models.AuditSetPassword, models.AuditUserEntity, id, nil, nil)
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"go-articles-app/models"
+	"go-articles-app/render"
+	"slices"
+)
+
+// GetRendered возвращает содержание статьи в HTML с оглавлением. Markdown
+// рендерится вне блокировки; результат кэшируется, только если содержание
+// за это время не изменилось.
+func (r *ArticleRepository) GetRendered(ctx context.Context, id int) (*render.Document, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	a, ok := r.store.articles[id]
+	if !ok {
+		r.store.mu.RUnlock()
+		return nil, notFound("article")
+	}
+	content := a.Content
+	cached := r.store.rendered[id]
+	r.store.mu.RUnlock()
+
+	if cached != nil {
+		return copyDocument(cached), nil
+	}
+
+	doc, err := render.Markdown(content)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	if a, ok := r.store.articles[id]; ok && a.Content == content {
+		r.store.rendered[id] = copyDocument(doc)
+	}
+	r.store.mu.Unlock()
+	return doc, nil
+}
+
+// invalidateRendered сбрасывает кэш рендеринга, если content меняет
+// содержание статьи. Вызывается под блокировкой на запись.
+func (s *Store) invalidateRendered(a *models.Article, content string) {
+	if a.Content != content {
+		delete(s.rendered, a.ID)
+	}
+}
+
+func copyDocument(doc *render.Document) *render.Document {
+	return &render.Document{HTML: doc.HTML, TOC: slices.Clone(doc.TOC)}
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+)
+
+func (r *ArticleRepository) ListRevisions(ctx context.Context, articleID int) ([]*models.ArticleRevision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if _, ok := r.store.articles[articleID]; !ok {
+		return nil, notFound("article")
+	}
+	stored := r.store.revisions[articleID]
+	revisions := make([]*models.ArticleRevision, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		revisions = append(revisions, copyRevision(stored[i]))
+	}
+	return revisions, nil
+}
+
+func (r *ArticleRepository) GetRevision(ctx context.Context, articleID, revision int) (*models.ArticleRevision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rev := r.store.revision(articleID, revision)
+	if rev == nil {
+		return nil, notFound("revision")
+	}
+	return copyRevision(rev), nil
+}
+
+func (r *ArticleRepository) DiffRevisions(ctx context.Context, articleID, from, to int) (*repository.RevisionDiff, error) {
+	fromRev, err := r.GetRevision(ctx, articleID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := r.GetRevision(ctx, articleID, to)
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewRevisionDiff(fromRev, toRev), nil
+}
+
+func (r *ArticleRepository) RestoreRevision(ctx context.Context, articleID, revision int) (*models.Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rev := r.store.revision(articleID, revision)
+	if rev == nil {
+		return nil, notFound("revision")
+	}
+	editorID := actorID(ctx)
+	if err := r.store.checkEditor(editorID); err != nil {
+		return nil, err
+	}
+
+	stored := r.store.articles[articleID]
+	before := *stored
+	r.store.reslug(stored, rev.Title)
+	r.store.invalidateRendered(stored, rev.Content)
+	stored.Title = rev.Title
+	stored.Content = rev.Content
+	stored.UpdatedAt = r.store.now()
+	r.store.addRevision(stored, editorID)
+	if err := r.store.record(ctx, models.AuditUpdate, models.AuditArticleEntity, articleID, &before, stored); err != nil {
+		return nil, err
+	}
+
+	article := *stored
+	return &article, nil
+}
+
+// revision вызывается под блокировкой
+func (s *Store) revision(articleID, revision int) *models.ArticleRevision {
+	// Ревизии статей из корзины не видны
+	if _, ok := s.articles[articleID]; !ok {
+		return nil
+	}
+	revisions := s.revisions[articleID]
+	if revision < 1 || revision > len(revisions) {
+		return nil
+	}
+	return revisions[revision-1]
+}
+
+func copyRevision(rev *models.ArticleRevision) *models.ArticleRevision {
+	c := *rev
+	c.EditorID = copyIntPtr(rev.EditorID)
+	return &c
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"slices"
+	"time"
+)
+
+func (r *ArticleRepository) Schedule(ctx context.Context, id int, action repository.ScheduleAction, at time.Time) (*models.Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	a, ok := r.store.articles[id]
+	if !ok {
+		return nil, notFound("article")
+	}
+	if err := repository.CheckSchedule(a, action, at); err != nil {
+		return nil, err
+	}
+
+	before := repository.AuditSchedule{PublishAt: a.PublishAt, UnpublishAt: a.UnpublishAt}
+	if action == repository.SchedulePublish {
+		a.PublishAt = &at
+	} else {
+		a.UnpublishAt = &at
+	}
+	after := repository.AuditSchedule{PublishAt: a.PublishAt, UnpublishAt: a.UnpublishAt}
+	if err := r.store.record(ctx, models.AuditSchedule, models.AuditArticleEntity, id, before, after); err != nil {
+		return nil, err
+	}
+	article := *a
+	return &article, nil
+}
+
+func (r *ArticleRepository) CancelSchedule(ctx context.Context, id int, action repository.ScheduleAction) (*models.Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	a, ok := r.store.articles[id]
+	switch {
+	case action != repository.SchedulePublish && action != repository.ScheduleUnpublish:
+		return nil, fmt.Errorf("%w: unknown schedule action %q", repository.ErrInvalidInput, action)
+	case !ok:
+		return nil, notFound("article")
+	}
+	before := repository.AuditSchedule{PublishAt: a.PublishAt, UnpublishAt: a.UnpublishAt}
+	if action == repository.SchedulePublish {
+		a.PublishAt = nil
+	} else {
+		a.UnpublishAt = nil
+	}
+	after := repository.AuditSchedule{PublishAt: a.PublishAt, UnpublishAt: a.UnpublishAt}
+	if err := r.store.record(ctx, models.AuditSchedule, models.AuditArticleEntity, id, before, after); err != nil {
+		return nil, err
+	}
+	article := *a
+	return &article, nil
+}
+
+func (r *ArticleRepository) ProcessDueSchedules(ctx context.Context, limit int) (repository.ScheduleRun, error) {
+	var run repository.ScheduleRun
+	if err := ctx.Err(); err != nil {
+		return run, err
+	}
+	limit = repository.PageRequest{Limit: limit}.PageSize()
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := r.store.now()
+	actor := actorID(ctx)
+
+	// Публикации первыми, как в PostgreSQL-реализации
+	for _, a := range r.store.dueArticles(func(a *models.Article) *time.Time { return a.PublishAt }, now, limit) {
+		a.PublishAt = nil
+		if repository.CheckArticleTransition(a.Status, models.ArticlePublished) != nil {
+			run.Skipped++
+			continue
+		}
+		from := a.Status
+		r.store.addTransition(a, models.ArticlePublished, actor, now)
+		a.PublishedAt = &now
+		if err := r.store.recordTransition(ctx, a.ID, from, models.ArticlePublished); err != nil {
+			return run, err
+		}
+		run.Published++
+	}
+	for _, a := range r.store.dueArticles(func(a *models.Article) *time.Time { return a.UnpublishAt }, now, limit) {
+		a.UnpublishAt = nil
+		if repository.CheckArticleTransition(a.Status, models.ArticleArchived) != nil {
+			run.Skipped++
+			continue
+		}
+		from := a.Status
+		r.store.addTransition(a, models.ArticleArchived, actor, now)
+		if err := r.store.recordTransition(ctx, a.ID, from, models.ArticleArchived); err != nil {
+			return run, err
+		}
+		run.Unpublished++
+	}
+	return run, nil
+}
+
+// dueArticles возвращает не больше limit статей, у которых время из field
+// наступило, в порядке (время, id). Вызывается под блокировкой.
+func (s *Store) dueArticles(field func(*models.Article) *time.Time, now time.Time, limit int) []*models.Article {
+	var due []*models.Article
+	for _, a := range s.articles {
+		if at := field(a); at != nil && !at.After(now) {
+			due = append(due, a)
+		}
+	}
+	slices.SortFunc(due, func(a, b *models.Article) int {
+		if c := field(a).Compare(*field(b)); c != 0 {
+			return c
+		}
+		return a.ID - b.ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due
+}
@@ -0,0 +1,226 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"sort"
+	"time"
+)
+
+// sessionRow — сессия вместе с хэшами токенов, которых нет в модели
+type sessionRow struct {
+	session      models.Session
+	tokenHash    []byte
+	previousHash []byte
+}
+
+type SessionRepository struct {
+	store *Store
+}
+
+var _ repository.SessionStore = (*SessionRepository)(nil)
+
+// sessionByHash вызывается под блокировкой
+func (s *Store) sessionByHash(hash []byte, previous bool) *sessionRow {
+	for _, row := range s.sessions {
+		h := row.tokenHash
+		if previous {
+			h = row.previousHash
+		}
+		if h != nil && bytes.Equal(h, hash) {
+			return row
+		}
+	}
+	return nil
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session, tokenHash []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.userExists(session.UserID) {
+		return fmt.Errorf("failed to create session: user %d: %w", session.UserID, repository.ErrConflict)
+	}
+	if r.store.sessionByHash(tokenHash, false) != nil || r.store.sessionByHash(tokenHash, true) != nil {
+		return fmt.Errorf("failed to create session: %w", repository.ErrAlreadyExists)
+	}
+
+	r.store.lastSessionID++
+	session.ID = r.store.lastSessionID
+	r.store.sessions[session.ID] = &sessionRow{session: *session, tokenHash: bytes.Clone(tokenHash)}
+	return nil
+}
+
+func (r *SessionRepository) GetByToken(ctx context.Context, tokenHash []byte) (*models.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row := r.store.sessionByHash(tokenHash, false)
+	if row == nil {
+		return nil, notFound("session")
+	}
+	return copySession(&row.session), nil
+}
+
+func (r *SessionRepository) Touch(ctx context.Context, id int, seenAt, idleExpiresAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.sessions[id]
+	if !ok || row.session.RevokedAt != nil {
+		return notFound("session")
+	}
+	row.session.LastSeenAt = seenAt
+	row.session.IdleExpiresAt = idleExpiresAt
+	return nil
+}
+
+func (r *SessionRepository) Rotate(ctx context.Context, id int, oldHash, newHash []byte, seenAt, idleExpiresAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.sessions[id]
+	if !ok || row.session.RevokedAt != nil || !bytes.Equal(row.tokenHash, oldHash) {
+		return fmt.Errorf("%w: session %d token was already rotated or revoked", repository.ErrConflict, id)
+	}
+	row.previousHash = row.tokenHash
+	row.tokenHash = bytes.Clone(newHash)
+	row.session.LastSeenAt = seenAt
+	row.session.IdleExpiresAt = idleExpiresAt
+	return nil
+}
+
+func (r *SessionRepository) RevokeByPreviousToken(ctx context.Context, tokenHash []byte) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row := r.store.sessionByHash(tokenHash, true)
+	if row == nil {
+		return 0, notFound("session")
+	}
+	if row.session.RevokedAt == nil {
+		now := r.store.now()
+		row.session.RevokedAt = &now
+	}
+	return row.session.ID, nil
+}
+
+func (r *SessionRepository) ListActive(ctx context.Context, userID int, now time.Time) ([]*models.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	sessions := []*models.Session{}
+	for _, row := range r.store.sessions {
+		if row.session.UserID == userID && row.session.Active(now) {
+			sessions = append(sessions, copySession(&row.session))
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].LastSeenAt.Equal(sessions[j].LastSeenAt) {
+			return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+		}
+		return sessions[i].ID > sessions[j].ID
+	})
+	return sessions, nil
+}
+
+func (r *SessionRepository) Revoke(ctx context.Context, userID, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.sessions[id]
+	if !ok || row.session.UserID != userID || row.session.RevokedAt != nil {
+		return notFound("session")
+	}
+	now := r.store.now()
+	row.session.RevokedAt = &now
+	return nil
+}
+
+func (r *SessionRepository) RevokeAll(ctx context.Context, userID int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := r.store.now()
+	revoked := 0
+	for _, row := range r.store.sessions {
+		if row.session.UserID == userID && row.session.RevokedAt == nil {
+			row.session.RevokedAt = &now
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (r *SessionRepository) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if retention < 0 {
+		return 0, fmt.Errorf("%w: negative retention", repository.ErrInvalidInput)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	cutoff := r.store.now().Add(-retention)
+	purged := 0
+	for id, row := range r.store.sessions {
+		end := row.session.ExpiresAt
+		if row.session.IdleExpiresAt.Before(end) {
+			end = row.session.IdleExpiresAt
+		}
+		if row.session.RevokedAt != nil && row.session.RevokedAt.Before(end) {
+			end = *row.session.RevokedAt
+		}
+		if !end.After(cutoff) {
+			delete(r.store.sessions, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func copySession(s *models.Session) *models.Session {
+	c := *s
+	if s.RevokedAt != nil {
+		at := *s.RevokedAt
+		c.RevokedAt = &at
+	}
+	return &c
+}
@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"go-articles-app/models"
+	"go-articles-app/slug"
+)
+
+// GetBySlug находит статью по текущему или прежнему слагу
+func (r *ArticleRepository) GetBySlug(ctx context.Context, s string) (*models.Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	a, ok := r.store.articles[r.store.slugs[s]]
+	if !ok {
+		return nil, notFound("article")
+	}
+	article := *a
+	return &article, nil
+}
+
+// pickSlug выбирает свободный слаг; слаги статьи articleID считаются
+// свободными. Вызывается под блокировкой.
+func (s *Store) pickSlug(articleID int, title string) string {
+	return s.uniqueSlug(articleID, slug.Make(title))
+}
+
+// uniqueSlug — base или первый свободный вариант base-2, base-3, ...
+func (s *Store) uniqueSlug(articleID int, base string) string {
+	return slug.Unique(base, func(candidate string) bool {
+		owner, ok := s.slugs[candidate]
+		return ok && owner != articleID
+	})
+}
+
+// reslug меняет слаг статьи перед сменой заголовка на newTitle, если меняется
+// основа слага. Прежний слаг остается за статьей. Вызывается под блокировкой
+// на запись.
+func (s *Store) reslug(article *models.Article, newTitle string) {
+	if slug.Make(article.Title) == slug.Make(newTitle) {
+		return
+	}
+	article.Slug = s.pickSlug(article.ID, newTitle)
+	s.slugs[article.Slug] = article.ID
+}
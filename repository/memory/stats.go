@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"go-articles-app/repository"
+	"slices"
+)
+
+type StatsRepository struct {
+	store *Store
+}
+
+var _ repository.StatsStore = (*StatsRepository)(nil)
+
+func (r *StatsRepository) Get(ctx context.Context, top int) (*repository.Stats, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if top <= 0 {
+		top = repository.DefaultTopAuthors
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stats := repository.NewStats()
+	for _, u := range r.store.users {
+		stats.UsersByRole[u.Role]++
+		stats.Users++
+	}
+	stats.TrashedUsers = len(r.store.trashedUsers)
+
+	authors := make(map[int]*repository.AuthorStats)
+	for _, a := range r.store.articles {
+		stats.ArticlesByStatus[a.Status]++
+		stats.Articles++
+		stats.Views += int64(a.Views)
+
+		author := authors[a.AuthorID]
+		if author == nil {
+			author = &repository.AuthorStats{ID: a.AuthorID, Name: r.store.users[a.AuthorID].Name}
+			authors[a.AuthorID] = author
+		}
+		author.Articles++
+		author.Views += int64(a.Views)
+	}
+	stats.TrashedArticles = len(r.store.trashedArticles)
+
+	for _, c := range r.store.comments {
+		if _, live := r.store.articles[c.ArticleID]; live {
+			stats.CommentsByStatus[c.Status]++
+		}
+	}
+	stats.Tags = len(r.store.tags)
+
+	for _, a := range authors {
+		stats.TopAuthors = append(stats.TopAuthors, *a)
+	}
+	slices.SortFunc(stats.TopAuthors, func(a, b repository.AuthorStats) int {
+		return cmp.Or(cmp.Compare(b.Articles, a.Articles), cmp.Compare(b.Views, a.Views), cmp.Compare(a.ID, b.ID))
+	})
+	if len(stats.TopAuthors) > top {
+		stats.TopAuthors = stats.TopAuthors[:top]
+	}
+	return stats, nil
+}
@@ -0,0 +1,310 @@
+// Package memory содержит потокобезопасную in-memory реализацию хранилищ
+// repository.UserStore, repository.ArticleStore, repository.TagStore,
+// repository.CategoryStore, repository.CommentStore, repository.SessionStore, repository.AuditStore,
+// repository.StatsStore и repository.BulkStore.
+// Она повторяет семантику PostgreSQL-схемы (уникальный email, внешние ключи
+// с ON DELETE CASCADE, значения по умолчанию status/views) и предназначена
+// для тестов.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/render"
+	"go-articles-app/repository"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const varcharLimit = 255
+
+// Store хранит общее состояние пользователей и статей, чтобы каскадное
+// удаление и проверка внешних ключей работали так же, как в базе.
+type Store struct {
+	mu sync.RWMutex
+
+	users       map[int]*models.User
+	articles    map[int]*models.Article
+	tags        map[int]*models.Tag
+	articleTags map[int]map[int]bool // article_id -> набор tag_id
+	categories  map[int]*models.Category
+	articleCats map[int]int // article_id -> category_id
+	comments    map[int]*models.Comment
+	revisions   map[int][]*models.ArticleRevision   // article_id -> ревизии по возрастанию номера
+	transitions map[int][]*models.ArticleTransition // article_id -> история статусов
+	slugs       map[string]int                      // текущие и прежние слаги -> article_id
+	rendered    map[int]*render.Document            // article_id -> кэш GetRendered
+	passwords   map[int]string                      // user_id -> хэш пароля
+	sessions    map[int]*sessionRow
+	audit       []*models.AuditEvent // в порядке записи
+
+	// Корзина: удаленные строки хранятся отдельно и не видны обычным методам
+	trashedUsers      map[int]*models.User
+	trashedArticles   map[int]*models.Article
+	deletedWithAuthor map[int]bool // article_id статей, удаленных вместе с автором
+
+	lastUserID       int
+	lastArticleID    int
+	lastTagID        int
+	lastCategoryID   int
+	lastCommentID    int
+	lastRevisionID   int
+	lastTransitionID int
+	lastSessionID    int
+	lastAuditID      int
+
+	now func() time.Time
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:       make(map[int]*models.User),
+		articles:    make(map[int]*models.Article),
+		tags:        make(map[int]*models.Tag),
+		articleTags: make(map[int]map[int]bool),
+		categories:  make(map[int]*models.Category),
+		articleCats: make(map[int]int),
+		comments:    make(map[int]*models.Comment),
+		revisions:   make(map[int][]*models.ArticleRevision),
+		transitions: make(map[int][]*models.ArticleTransition),
+		slugs:       make(map[string]int),
+		rendered:    make(map[int]*render.Document),
+		passwords:   make(map[int]string),
+		sessions:    make(map[int]*sessionRow),
+
+		trashedUsers:      make(map[int]*models.User),
+		trashedArticles:   make(map[int]*models.Article),
+		deletedWithAuthor: make(map[int]bool),
+
+		now: time.Now,
+	}
+}
+
+func (s *Store) Users() *UserRepository {
+	return &UserRepository{store: s}
+}
+
+func (s *Store) Articles() *ArticleRepository {
+	return &ArticleRepository{store: s}
+}
+
+func (s *Store) Tags() *TagRepository {
+	return &TagRepository{store: s}
+}
+
+func (s *Store) Categories() *CategoryRepository {
+	return &CategoryRepository{store: s}
+}
+
+func (s *Store) Comments() *CommentRepository {
+	return &CommentRepository{store: s}
+}
+
+func (s *Store) Sessions() *SessionRepository {
+	return &SessionRepository{store: s}
+}
+
+func (s *Store) Audit() *AuditRepository {
+	return &AuditRepository{store: s}
+}
+
+func (s *Store) Stats() *StatsRepository {
+	return &StatsRepository{store: s}
+}
+
+func (s *Store) Bulk() *BulkRepository {
+	return &BulkRepository{store: s}
+}
+
+// userByEmail вызывается под блокировкой
+func (s *Store) userByEmail(email string) *models.User {
+	for _, u := range s.users {
+		if u.Email == email {
+			return u
+		}
+	}
+	return nil
+}
+
+// emailTaken проверяет уникальность email среди пользователей вне корзины,
+// как частичный уникальный индекс в PostgreSQL. Вызывается под блокировкой.
+func (s *Store) emailTaken(email string, exceptID int) bool {
+	u := s.userByEmail(email)
+	return u != nil && u.ID != exceptID
+}
+
+// userExists повторяет внешний ключ на users: пользователь из корзины тоже
+// существует. Вызывается под блокировкой.
+func (s *Store) userExists(id int) bool {
+	_, live := s.users[id]
+	_, trashed := s.trashedUsers[id]
+	return live || trashed
+}
+
+// insertUser вызывается под блокировкой на запись
+func (s *Store) insertUser(user *models.User) error {
+	if user.Role == "" {
+		user.Role = models.DefaultRole
+	}
+	if !user.Role.Valid() {
+		return fmt.Errorf("%w: unknown role %q", repository.ErrInvalidInput, user.Role)
+	}
+	if err := checkVarchar(user.Email); err != nil {
+		return err
+	}
+	if err := checkVarchar(user.Name); err != nil {
+		return err
+	}
+	if s.emailTaken(user.Email, 0) {
+		return fmt.Errorf("user with email %s: %w", user.Email, repository.ErrAlreadyExists)
+	}
+
+	now := s.now()
+	s.lastUserID++
+	user.ID = s.lastUserID
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	stored := *user
+	s.users[user.ID] = &stored
+	return nil
+}
+
+// insertArticle добавляет статью и ее первую ревизию от editorID.
+// Вызывается под блокировкой на запись.
+func (s *Store) insertArticle(article *models.Article, editorID int) error {
+	if err := checkVarchar(article.Title); err != nil {
+		return err
+	}
+	if _, ok := s.users[article.AuthorID]; !ok {
+		return fmt.Errorf("failed to create article: author %d: %w", article.AuthorID, repository.ErrConflict)
+	}
+	if err := s.checkEditor(&editorID); err != nil {
+		return err
+	}
+
+	now := s.now()
+	s.lastArticleID++
+	article.ID = s.lastArticleID
+	article.Slug = s.pickSlug(article.ID, article.Title)
+	s.slugs[article.Slug] = article.ID
+	article.Status = models.ArticleDraft
+	article.PublishedAt = nil
+	article.PublishAt = nil
+	article.UnpublishAt = nil
+	article.Views = 0
+	article.CreatedAt = now
+	article.UpdatedAt = now
+
+	stored := *article
+	s.articles[article.ID] = &stored
+	s.addRevision(&stored, &editorID)
+	return nil
+}
+
+// addRevision записывает следующую ревизию статьи. Вызывается под блокировкой на запись.
+func (s *Store) addRevision(article *models.Article, editorID *int) {
+	s.lastRevisionID++
+	rev := &models.ArticleRevision{
+		ID:        s.lastRevisionID,
+		ArticleID: article.ID,
+		Revision:  len(s.revisions[article.ID]) + 1,
+		Title:     article.Title,
+		Content:   article.Content,
+		EditorID:  copyIntPtr(editorID),
+		CreatedAt: article.UpdatedAt,
+	}
+	s.revisions[article.ID] = append(s.revisions[article.ID], rev)
+}
+
+// checkEditor повторяет внешний ключ article_revisions.editor_id.
+// Вызывается под блокировкой.
+func (s *Store) checkEditor(editorID *int) error {
+	if editorID == nil {
+		return nil
+	}
+	if !s.userExists(*editorID) {
+		return fmt.Errorf("failed to save revision: editor %d: %w", *editorID, repository.ErrConflict)
+	}
+	return nil
+}
+
+// deleteArticle удаляет статью вместе с зависимыми строками (ON DELETE CASCADE).
+// Вызывается под блокировкой на запись.
+func (s *Store) deleteArticle(id int) {
+	delete(s.articles, id)
+	delete(s.trashedArticles, id)
+	delete(s.deletedWithAuthor, id)
+	delete(s.articleTags, id)
+	delete(s.articleCats, id)
+	delete(s.rendered, id)
+	for sl, articleID := range s.slugs {
+		if articleID == id {
+			delete(s.slugs, sl)
+		}
+	}
+	delete(s.revisions, id)
+	delete(s.transitions, id)
+	for commentID, c := range s.comments {
+		if c.ArticleID == id {
+			delete(s.comments, commentID)
+		}
+	}
+}
+
+// deleteComment удаляет комментарий и все ответы на него (ON DELETE CASCADE
+// по parent_id). Вызывается под блокировкой на запись.
+func (s *Store) deleteComment(id int) {
+	delete(s.comments, id)
+	for childID, c := range s.comments {
+		if c.ParentID != nil && *c.ParentID == id {
+			s.deleteComment(childID)
+		}
+	}
+}
+
+// checkVarchar повторяет ограничение VARCHAR(255) из миграций
+func checkVarchar(value string) error {
+	if utf8.RuneCountInString(value) > varcharLimit {
+		return fmt.Errorf("value too long for type character varying(%d)", varcharLimit)
+	}
+	return nil
+}
+
+// actorOr возвращает пользователя из контекста (repository.WithActor) или fallback
+func actorOr(ctx context.Context, fallback int) int {
+	if id, ok := repository.ActorFromContext(ctx); ok {
+		return id
+	}
+	return fallback
+}
+
+// actorID возвращает пользователя из контекста или nil
+func actorID(ctx context.Context) *int {
+	if id, ok := repository.ActorFromContext(ctx); ok {
+		return &id
+	}
+	return nil
+}
+
+func copyIntPtr(p *int) *int {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func notFound(entity string) error {
+	return fmt.Errorf("%s %w", entity, repository.ErrNotFound)
+}
+
+// cursorBefore сравнивает позиции так же, как (created_at, id) < (...) в PostgreSQL
+func cursorBefore(a, b repository.Cursor) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
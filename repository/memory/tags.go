@@ -0,0 +1,269 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"sort"
+	"strings"
+)
+
+type TagRepository struct {
+	store *Store
+}
+
+var _ repository.TagStore = (*TagRepository)(nil)
+
+// tagByName вызывается под блокировкой
+func (s *Store) tagByName(name string) *models.Tag {
+	for _, t := range s.tags {
+		if strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+	return nil
+}
+
+// insertTag вызывается под блокировкой на запись
+func (s *Store) insertTag(name string) *models.Tag {
+	s.lastTagID++
+	tag := &models.Tag{ID: s.lastTagID, Name: name, CreatedAt: s.now()}
+	s.tags[tag.ID] = tag
+	return tag
+}
+
+// articleTagList возвращает копии тегов статьи, отсортированные по имени.
+// Вызывается под блокировкой.
+func (s *Store) articleTagList(articleID int) []*models.Tag {
+	tags := []*models.Tag{}
+	for tagID := range s.articleTags[articleID] {
+		tag := *s.tags[tagID]
+		tags = append(tags, &tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return strings.ToLower(tags[i].Name) < strings.ToLower(tags[j].Name) })
+	return tags
+}
+
+func (r *TagRepository) Create(ctx context.Context, name string) (*models.Tag, error) {
+	name, err := repository.NormalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.tagByName(name) != nil {
+		return nil, fmt.Errorf("tag %q: %w", name, repository.ErrAlreadyExists)
+	}
+	tag := *r.store.insertTag(name)
+	return &tag, nil
+}
+
+func (r *TagRepository) GetByName(ctx context.Context, name string) (*models.Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	t := r.store.tagByName(strings.TrimSpace(name))
+	if t == nil {
+		return nil, notFound("tag")
+	}
+	tag := *t
+	return &tag, nil
+}
+
+func (r *TagRepository) List(ctx context.Context) ([]*repository.TagUsage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[int]int)
+	for articleID, tagIDs := range r.store.articleTags {
+		// Черновики и статьи из корзины не учитываются
+		if a, ok := r.store.articles[articleID]; !ok || a.Status != models.ArticlePublished {
+			continue
+		}
+		for tagID := range tagIDs {
+			counts[tagID]++
+		}
+	}
+
+	usages := []*repository.TagUsage{}
+	for _, t := range r.store.tags {
+		tag := *t
+		usages = append(usages, &repository.TagUsage{Tag: &tag, ArticleCount: counts[t.ID]})
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].ArticleCount != usages[j].ArticleCount {
+			return usages[i].ArticleCount > usages[j].ArticleCount
+		}
+		return strings.ToLower(usages[i].Tag.Name) < strings.ToLower(usages[j].Tag.Name)
+	})
+	return usages, nil
+}
+
+func (r *TagRepository) GetArticleTags(ctx context.Context, articleID int) ([]*models.Tag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.articleTagList(articleID), nil
+}
+
+func (r *TagRepository) SetArticleTags(ctx context.Context, articleID int, names []string) ([]*models.Tag, error) {
+	names, err := repository.NormalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.articles[articleID]; !ok {
+		return nil, notFound("article")
+	}
+
+	tagIDs := make(map[int]bool, len(names))
+	for _, name := range names {
+		tag := r.store.tagByName(name)
+		if tag == nil {
+			tag = r.store.insertTag(name)
+		}
+		tagIDs[tag.ID] = true
+	}
+
+	if len(tagIDs) == 0 {
+		delete(r.store.articleTags, articleID)
+	} else {
+		r.store.articleTags[articleID] = tagIDs
+	}
+	return r.store.articleTagList(articleID), nil
+}
+
+func (r *TagRepository) GetArticlesByTags(ctx context.Context, names []string, match repository.TagMatch) ([]*models.Article, error) {
+	names, err := repository.NormalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+	switch match {
+	case "", repository.TagMatchAny, repository.TagMatchAll:
+	default:
+		return nil, fmt.Errorf("%w: unknown tag match %q", repository.ErrInvalidQuery, match)
+	}
+	if len(names) == 0 {
+		return []*models.Article{}, nil
+	}
+
+	articles, err := r.store.Articles().list(ctx, func(a *models.Article) bool {
+		matched := 0
+		for _, name := range names {
+			// Вызывается под RLock из list
+			if tag := r.store.tagByName(name); tag != nil && r.store.articleTags[a.ID][tag.ID] {
+				matched++
+			}
+		}
+		if match == repository.TagMatchAll {
+			return matched == len(names)
+		}
+		return matched > 0
+	})
+	if err != nil {
+		return nil, err
+	}
+	if articles == nil {
+		articles = []*models.Article{}
+	}
+	return articles, nil
+}
+
+func (r *TagRepository) Rename(ctx context.Context, id int, newName string) (*models.Tag, error) {
+	newName, err := repository.NormalizeTagName(newName)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t, ok := r.store.tags[id]
+	if !ok {
+		return nil, notFound("tag")
+	}
+	if other := r.store.tagByName(newName); other != nil && other.ID != id {
+		return nil, fmt.Errorf("tag %q: %w", newName, repository.ErrAlreadyExists)
+	}
+
+	t.Name = newName
+	tag := *t
+	return &tag, nil
+}
+
+func (r *TagRepository) Merge(ctx context.Context, sourceID, targetID int) error {
+	if sourceID == targetID {
+		return fmt.Errorf("%w: cannot merge tag into itself", repository.ErrInvalidInput)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	_, okSource := r.store.tags[sourceID]
+	_, okTarget := r.store.tags[targetID]
+	if !okSource || !okTarget {
+		return notFound("tag")
+	}
+
+	for _, tagIDs := range r.store.articleTags {
+		if tagIDs[sourceID] {
+			delete(tagIDs, sourceID)
+			tagIDs[targetID] = true
+		}
+	}
+	delete(r.store.tags, sourceID)
+	return nil
+}
+
+func (r *TagRepository) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.tags[id]; !ok {
+		return notFound("tag")
+	}
+	delete(r.store.tags, id)
+
+	// ON DELETE CASCADE для article_tags
+	for articleID, tagIDs := range r.store.articleTags {
+		delete(tagIDs, id)
+		if len(tagIDs) == 0 {
+			delete(r.store.articleTags, articleID)
+		}
+	}
+	return nil
+}
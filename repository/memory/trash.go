@@ -0,0 +1,256 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"sort"
+	"time"
+)
+
+func (r *UserRepository) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	u, ok := r.store.users[id]
+	if !ok {
+		return notFound("user")
+	}
+	before := *u
+	now := r.store.now()
+	delete(r.store.users, id)
+	u.DeletedAt = &now
+	r.store.trashedUsers[id] = u
+
+	for articleID, a := range r.store.articles {
+		if a.AuthorID == id {
+			r.store.trashArticle(articleID, now)
+			r.store.deletedWithAuthor[articleID] = true
+		}
+	}
+	return r.store.record(ctx, models.AuditDelete, models.AuditUserEntity, id, &before, nil)
+}
+
+func (r *UserRepository) Restore(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	u, ok := r.store.trashedUsers[id]
+	if !ok {
+		return notFound("deleted user")
+	}
+	if r.store.emailTaken(u.Email, id) {
+		return fmt.Errorf("user with email %s: %w", u.Email, repository.ErrAlreadyExists)
+	}
+	delete(r.store.trashedUsers, id)
+	u.DeletedAt = nil
+	r.store.users[id] = u
+
+	for articleID := range r.store.deletedWithAuthor {
+		if r.store.trashedArticles[articleID].AuthorID == id {
+			r.store.restoreArticle(articleID)
+		}
+	}
+	return r.store.record(ctx, models.AuditRestore, models.AuditUserEntity, id, nil, u)
+}
+
+func (r *UserRepository) ListTrash(ctx context.Context) ([]*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := []*models.User{}
+	for _, u := range r.store.trashedUsers {
+		user := *u
+		users = append(users, &user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return trashedAfter(users[i].DeletedAt, users[j].DeletedAt, users[i].ID, users[j].ID)
+	})
+	return users, nil
+}
+
+func (r *UserRepository) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if retention < 0 {
+		return 0, fmt.Errorf("%w: negative retention", repository.ErrInvalidInput)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	cutoff := r.store.now().Add(-retention)
+	purged := 0
+	for id, u := range r.store.trashedUsers {
+		if !u.DeletedAt.After(cutoff) {
+			r.store.purgeUser(id)
+			if err := r.store.record(ctx, models.AuditPurge, models.AuditUserEntity, id, nil, nil); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *ArticleRepository) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	a, ok := r.store.articles[id]
+	if !ok {
+		return notFound("article")
+	}
+	before := *a
+	r.store.trashArticle(id, r.store.now())
+	return r.store.record(ctx, models.AuditDelete, models.AuditArticleEntity, id, &before, nil)
+}
+
+func (r *ArticleRepository) Restore(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	a, ok := r.store.trashedArticles[id]
+	if !ok {
+		return notFound("deleted article")
+	}
+	if _, trashed := r.store.trashedUsers[a.AuthorID]; trashed {
+		return fmt.Errorf("%w: author %d is in trash", repository.ErrConflict, a.AuthorID)
+	}
+	r.store.restoreArticle(id)
+	return r.store.record(ctx, models.AuditRestore, models.AuditArticleEntity, id, nil, a)
+}
+
+func (r *ArticleRepository) ListTrash(ctx context.Context) ([]*models.Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	articles := []*models.Article{}
+	for _, a := range r.store.trashedArticles {
+		article := *a
+		articles = append(articles, &article)
+	}
+	sort.Slice(articles, func(i, j int) bool {
+		return trashedAfter(articles[i].DeletedAt, articles[j].DeletedAt, articles[i].ID, articles[j].ID)
+	})
+	return articles, nil
+}
+
+func (r *ArticleRepository) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if retention < 0 {
+		return 0, fmt.Errorf("%w: negative retention", repository.ErrInvalidInput)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	cutoff := r.store.now().Add(-retention)
+	purged := 0
+	for id, a := range r.store.trashedArticles {
+		if !a.DeletedAt.After(cutoff) {
+			r.store.deleteArticle(id)
+			if err := r.store.record(ctx, models.AuditPurge, models.AuditArticleEntity, id, nil, nil); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// trashArticle перемещает статью в корзину. Вызывается под блокировкой на запись.
+func (s *Store) trashArticle(id int, now time.Time) {
+	a := s.articles[id]
+	delete(s.articles, id)
+	a.DeletedAt = &now
+	s.trashedArticles[id] = a
+}
+
+// restoreArticle возвращает статью из корзины. Вызывается под блокировкой на запись.
+func (s *Store) restoreArticle(id int) {
+	a := s.trashedArticles[id]
+	delete(s.trashedArticles, id)
+	delete(s.deletedWithAuthor, id)
+	a.DeletedAt = nil
+	s.articles[id] = a
+}
+
+// purgeUser окончательно удаляет пользователя с зависимыми строками, повторяя
+// ON DELETE CASCADE и SET NULL из схемы. Вызывается под блокировкой на запись.
+func (s *Store) purgeUser(id int) {
+	delete(s.users, id)
+	delete(s.trashedUsers, id)
+	delete(s.passwords, id)
+	for sessionID, row := range s.sessions {
+		if row.session.UserID == id {
+			delete(s.sessions, sessionID)
+		}
+	}
+
+	for articleID, a := range s.articles {
+		if a.AuthorID == id {
+			s.deleteArticle(articleID)
+		}
+	}
+	for articleID, a := range s.trashedArticles {
+		if a.AuthorID == id {
+			s.deleteArticle(articleID)
+		}
+	}
+	for commentID, c := range s.comments {
+		if c.AuthorID == id {
+			s.deleteComment(commentID)
+		}
+	}
+	for _, revisions := range s.revisions {
+		for _, rev := range revisions {
+			if rev.EditorID != nil && *rev.EditorID == id {
+				rev.EditorID = nil
+			}
+		}
+	}
+	for _, transitions := range s.transitions {
+		for _, tr := range transitions {
+			if tr.ActorID != nil && *tr.ActorID == id {
+				tr.ActorID = nil
+			}
+		}
+	}
+}
+
+// trashedAfter задает порядок корзины: недавно удаленные первыми, затем по id DESC
+func trashedAfter(a, b *time.Time, aID, bID int) bool {
+	if !a.Equal(*b) {
+		return a.After(*b)
+	}
+	return aID > bID
+}
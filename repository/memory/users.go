@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"sort"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+var _ repository.UserStore = (*UserRepository)(nil)
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.store.insertUser(user); err != nil {
+		return err
+	}
+	return r.store.record(ctx, models.AuditCreate, models.AuditUserEntity, user.ID, nil, user)
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	u, ok := r.store.users[id]
+	if !ok {
+		return nil, notFound("user")
+	}
+	user := *u
+	return &user, nil
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	u := r.store.userByEmail(email)
+	if u == nil {
+		return nil, notFound("user")
+	}
+	user := *u
+	return &user, nil
+}
+
+func (r *UserRepository) GetAll(ctx context.Context) ([]*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var users []*models.User
+	for _, u := range r.store.users {
+		user := *u
+		users = append(users, &user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.users[user.ID]
+	if !ok {
+		return notFound("user")
+	}
+	if err := checkVarchar(user.Email); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	if err := checkVarchar(user.Name); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	if r.store.emailTaken(user.Email, user.ID) {
+		return fmt.Errorf("user with email %s: %w", user.Email, repository.ErrAlreadyExists)
+	}
+
+	before := *stored
+	user.UpdatedAt = r.store.now()
+	stored.Email = user.Email
+	stored.Name = user.Name
+	stored.UpdatedAt = user.UpdatedAt
+	return r.store.record(ctx, models.AuditUpdate, models.AuditUserEntity, user.ID, &before, stored)
+}
+
+func (r *UserRepository) GetAllPage(ctx context.Context, page repository.PageRequest) (*repository.Page[*models.User], error) {
+	var cursor *repository.Cursor
+	if page.Cursor != "" {
+		c, err := repository.DecodeCursor(page.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &c
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var users []*models.User
+	for _, u := range r.store.users {
+		if cursor == nil || cursorBefore(*cursor, repository.UserCursor(u)) {
+			user := *u
+			users = append(users, &user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return cursorBefore(repository.UserCursor(users[i]), repository.UserCursor(users[j]))
+	})
+
+	size := page.PageSize()
+	if len(users) > size+1 {
+		users = users[:size+1]
+	}
+	return repository.NewPage(users, size, repository.UserCursor), nil
+}
+
+func (r *UserRepository) SetRole(ctx context.Context, id int, role models.Role) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !role.Valid() {
+		return fmt.Errorf("%w: unknown role %q", repository.ErrInvalidInput, role)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	u, ok := r.store.users[id]
+	if !ok {
+		return notFound("user")
+	}
+	before := u.Role
+	u.Role = role
+	u.UpdatedAt = r.store.now()
+	return r.store.record(ctx, models.AuditSetRole, models.AuditUserEntity, id,
+		repository.AuditRole{Role: before}, repository.AuditRole{Role: role})
+}
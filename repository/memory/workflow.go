@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"time"
+)
+
+func (r *ArticleRepository) Transition(ctx context.Context, id int, to models.ArticleStatus) (*models.Article, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	a, ok := r.store.articles[id]
+	if !ok {
+		return nil, notFound("article")
+	}
+	if err := repository.CheckArticleTransition(a.Status, to); err != nil {
+		return nil, err
+	}
+	actor := actorID(ctx)
+	if actor != nil {
+		if !r.store.userExists(*actor) {
+			return nil, fmt.Errorf("failed to save status transition: actor %d: %w", *actor, repository.ErrConflict)
+		}
+	}
+
+	now := r.store.now()
+	from := a.Status
+	if a.Status == models.ArticlePublished {
+		a.UnpublishAt = nil
+	}
+	r.store.addTransition(a, to, actor, now)
+	if to == models.ArticlePublished {
+		a.PublishedAt = &now
+		a.PublishAt = nil
+	}
+	if err := r.store.recordTransition(ctx, id, from, to); err != nil {
+		return nil, err
+	}
+
+	article := *a
+	return &article, nil
+}
+
+func (r *ArticleRepository) ListTransitions(ctx context.Context, articleID int) ([]*models.ArticleTransition, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if _, ok := r.store.articles[articleID]; !ok {
+		return nil, notFound("article")
+	}
+	transitions := make([]*models.ArticleTransition, 0, len(r.store.transitions[articleID]))
+	for _, tr := range r.store.transitions[articleID] {
+		c := *tr
+		c.ActorID = copyIntPtr(tr.ActorID)
+		transitions = append(transitions, &c)
+	}
+	return transitions, nil
+}
+
+// addTransition переводит статью в статус to и пишет переход в историю.
+// Вызывается под блокировкой на запись после проверки перехода.
+func (s *Store) addTransition(a *models.Article, to models.ArticleStatus, actor *int, now time.Time) {
+	s.lastTransitionID++
+	s.transitions[a.ID] = append(s.transitions[a.ID], &models.ArticleTransition{
+		ID:         s.lastTransitionID,
+		ArticleID:  a.ID,
+		FromStatus: a.Status,
+		ToStatus:   to,
+		ActorID:    copyIntPtr(actor),
+		CreatedAt:  now,
+	})
+	a.Status = to
+	a.UpdatedAt = now
+}
+
+// recordTransition пишет в журнал аудита смену статуса статьи.
+// Вызывается под блокировкой на запись.
+func (s *Store) recordTransition(ctx context.Context, id int, from, to models.ArticleStatus) error {
+	return s.record(ctx, repository.StatusAction(to), models.AuditArticleEntity, id,
+		repository.AuditStatus{Status: from}, repository.AuditStatus{Status: to})
+}
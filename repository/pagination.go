@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PageRequest описывает запрос страницы. Пустой Cursor означает первую страницу.
+type PageRequest struct {
+	Cursor string
+	Limit  int
+}
+
+// PageSize возвращает размер страницы с учетом значения по умолчанию и ограничения сверху
+func (p PageRequest) PageSize() int {
+	switch {
+	case p.Limit <= 0:
+		return DefaultPageSize
+	case p.Limit > MaxPageSize:
+		return MaxPageSize
+	default:
+		return p.Limit
+	}
+}
+
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// Cursor — позиция последней записи страницы в порядке (created_at, id)
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// EncodeCursor сериализует курсор в непрозрачную URL-безопасную строку
+func EncodeCursor(c Cursor) string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.Itoa(c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	n, err := strconv.Atoi(id)
+	if err != nil || n <= 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{CreatedAt: createdAt, ID: n}, nil
+}
+
+// decodePageCursor разбирает курсор запроса; для первой страницы возвращает nil
+func decodePageCursor(p PageRequest) (*Cursor, error) {
+	if p.Cursor == "" {
+		return nil, nil
+	}
+	c, err := DecodeCursor(p.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// NewPage обрезает выборку из PageSize()+1 записей до размера страницы
+// и заполняет курсор следующей страницы
+func NewPage[T any](items []T, size int, cursorOf func(T) Cursor) *Page[T] {
+	page := &Page[T]{Items: items}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if len(items) > size {
+		page.Items = items[:size]
+		page.HasMore = true
+		page.NextCursor = EncodeCursor(cursorOf(page.Items[size-1]))
+	}
+	return page
+}
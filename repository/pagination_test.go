@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		limit, want int
+	}{
+		{0, DefaultPageSize},
+		{-5, DefaultPageSize},
+		{10, 10},
+		{MaxPageSize, MaxPageSize},
+		{MaxPageSize + 1, MaxPageSize},
+	}
+	for _, tt := range tests {
+		if got := (PageRequest{Limit: tt.limit}).PageSize(); got != tt.want {
+			t.Errorf("PageSize(%d) = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := Cursor{CreatedAt: time.Date(2024, 3, 1, 9, 30, 0, 123456000, time.UTC), ID: 42}
+	got, err := DecodeCursor(EncodeCursor(want))
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+		t.Fatalf("DecodeCursor = %+v, want %+v", got, want)
+	}
+
+	for _, bad := range []string{"!!!", EncodeCursor(Cursor{ID: 0}), "MjAyNA"} {
+		if _, err := DecodeCursor(bad); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q): err = %v, want ErrInvalidCursor", bad, err)
+		}
+	}
+}
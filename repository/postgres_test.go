@@ -0,0 +1,150 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"go-articles-app/repository/storetest"
+	"os"
+	"strings"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+// TEST_DATABASE_URL должен указывать на отдельную базу с примененными
+// миграциями: перед каждым подтестом таблицы очищаются.
+const testDSNEnv = "TEST_DATABASE_URL"
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	database, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if err := database.Ping(); err != nil {
+		t.Fatalf("ping db: %v", err)
+	}
+	return database
+}
+
+func truncate(t *testing.T, database *sql.DB) {
+	t.Helper()
+	_, err := database.ExecContext(context.Background(), `TRUNCATE users, articles, tags, categories, comments, article_revisions, article_status_transitions, article_slugs, sessions, audit_events RESTART IDENTITY CASCADE`)
+	if err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+}
+
+func TestPostgresStore(t *testing.T) {
+	database := openTestDB(t)
+
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		truncate(t, database)
+		return storetest.Stores{
+			Users:      repository.NewUserRepository(database),
+			Articles:   repository.NewArticleRepository(database),
+			Tags:       repository.NewTagRepository(database),
+			Categories: repository.NewCategoryRepository(database),
+			Comments:   repository.NewCommentRepository(database),
+			Sessions:   repository.NewSessionRepository(database),
+			Audit:      repository.NewAuditRepository(database),
+			Stats:      repository.NewStatsRepository(database),
+			Bulk:       repository.NewBulkRepository(database),
+		}
+	})
+}
+
+func TestPostgresSearch(t *testing.T) {
+	database := openTestDB(t)
+	truncate(t, database)
+
+	ctx := context.Background()
+	users := repository.NewUserRepository(database)
+	articles := repository.NewArticleRepository(database)
+
+	author := &models.User{Email: "alice@example.com", Name: "Alice"}
+	if err := users.Create(ctx, author); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	create := func(title, content string, publish bool) *models.Article {
+		t.Helper()
+		a := &models.Article{Title: title, Content: content, AuthorID: author.ID}
+		if err := articles.Create(ctx, a); err != nil {
+			t.Fatalf("create article: %v", err)
+		}
+		if publish {
+			if err := articles.Publish(ctx, a.ID); err != nil {
+				t.Fatalf("publish: %v", err)
+			}
+		}
+		return a
+	}
+
+	inContent := create("Databases", "Tuning postgres indexes for faster queries", true)
+	inTitle := create("Postgres internals", "How the storage engine works", true)
+	russian := create("Микросервисы на Go", "Архитектура микросервисов и их развертывание", true)
+	draft := create("Postgres draft", "Unpublished postgres notes", false)
+
+	search := func(q repository.SearchQuery) []int {
+		t.Helper()
+		results, err := articles.Search(ctx, q)
+		if err != nil {
+			t.Fatalf("Search(%+v): %v", q, err)
+		}
+		ids := make([]int, 0, len(results))
+		for _, r := range results {
+			ids = append(ids, r.Article.ID)
+		}
+		return ids
+	}
+
+	if got := search(repository.SearchQuery{Query: "postgres"}); len(got) != 2 || got[0] != inTitle.ID || got[1] != inContent.ID {
+		t.Fatalf("title match must rank first and drafts be excluded: got %v", got)
+	}
+	if got := search(repository.SearchQuery{Query: "postgres", IncludeDrafts: true}); len(got) != 3 {
+		t.Fatalf("IncludeDrafts: got %v, want 3 results including %d", got, draft.ID)
+	}
+	if got := search(repository.SearchQuery{Query: "микросервис"}); len(got) != 1 || got[0] != russian.ID {
+		t.Fatalf("russian stemming: got %v, want [%d]", got, russian.ID)
+	}
+	if got := search(repository.SearchQuery{Query: "postg", Mode: repository.SearchModePrefix}); len(got) != 2 {
+		t.Fatalf("prefix: got %v, want 2 results", got)
+	}
+	if got := search(repository.SearchQuery{Query: "faster queries", Mode: repository.SearchModePhrase}); len(got) != 1 || got[0] != inContent.ID {
+		t.Fatalf("phrase: got %v, want [%d]", got, inContent.ID)
+	}
+	if got := search(repository.SearchQuery{Query: "queries faster", Mode: repository.SearchModePhrase}); len(got) != 0 {
+		t.Fatalf("phrase in wrong order matched: %v", got)
+	}
+
+	results, err := articles.Search(ctx, repository.SearchQuery{Query: "indexes"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 || results[0].Snippet == "" || results[0].Rank <= 0 {
+		t.Fatalf("snippet/rank not filled: %+v", results)
+	}
+
+	// Разметка из текста статьи экранируется, в сниппете остается только <mark>
+	create("XSS", `<script>alert(1)</script> exploit `+"\uE001"+` payload`, true)
+	results, err = articles.Search(ctx, repository.SearchQuery{Query: "exploit"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Search(exploit) = %d results, want 1", len(results))
+	}
+	snippet := results[0].Snippet
+	if strings.Contains(snippet, "<script>") || !strings.Contains(snippet, "&lt;script&gt;") ||
+		!strings.Contains(snippet, "<mark>exploit</mark>") || strings.Count(snippet, "</mark>") != 1 {
+		t.Fatalf("snippet = %q, want escaped content with a single <mark>", snippet)
+	}
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"time"
+)
+
+type SessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+const sessionColumns = `id, user_id, user_agent, ip, created_at, last_seen_at, expires_at, idle_expires_at, revoked_at`
+
+func scanSession(row rowScanner) (*models.Session, error) {
+	s := &models.Session{}
+	err := row.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.UserAgent,
+		&s.IP,
+		&s.CreatedAt,
+		&s.LastSeenAt,
+		&s.ExpiresAt,
+		&s.IdleExpiresAt,
+		&s.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Create сохраняет сессию; CreatedAt, LastSeenAt и сроки задает вызывающий
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session, tokenHash []byte) error {
+	query := `INSERT INTO sessions (user_id, token_hash, user_agent, ip, created_at, last_seen_at, expires_at, idle_expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING id
+	`
+	err := r.db.QueryRowContext(ctx, query,
+		session.UserID,
+		tokenHash,
+		session.UserAgent,
+		session.IP,
+		session.CreatedAt,
+		session.LastSeenAt,
+		session.ExpiresAt,
+		session.IdleExpiresAt,
+	).Scan(&session.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", pgError(err))
+	}
+	return nil
+}
+
+// GetByToken находит сессию по хэшу текущего токена, в том числе отозванную
+// или истекшую: проверять срок должен вызывающий
+func (r *SessionRepository) GetByToken(ctx context.Context, tokenHash []byte) (*models.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE token_hash = $1`
+	session, err := scanSession(r.db.QueryRowContext(ctx, query, tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("session")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	return session, nil
+}
+
+// Touch отмечает активность в сессии и продлевает ее простой
+func (r *SessionRepository) Touch(ctx context.Context, id int, seenAt, idleExpiresAt time.Time) error {
+	query := `UPDATE sessions SET last_seen_at = $1, idle_expires_at = $2 WHERE id = $3 AND revoked_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, seenAt, idleExpiresAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to touch session: %w", err)
+	}
+	return sessionAffected(result)
+}
+
+// Rotate заменяет токен сессии, запоминая прежний. Если текущий токен уже
+// не oldHash (его успел сменить параллельный запрос) или сессия отозвана,
+// возвращается ErrConflict.
+func (r *SessionRepository) Rotate(ctx context.Context, id int, oldHash, newHash []byte, seenAt, idleExpiresAt time.Time) error {
+	query := `
+		UPDATE sessions
+		SET token_hash = $1, previous_token_hash = $2, last_seen_at = $3, idle_expires_at = $4
+		WHERE id = $5 AND token_hash = $2 AND revoked_at IS NULL
+	`
+	result, err := r.db.ExecContext(ctx, query, newHash, oldHash, seenAt, idleExpiresAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to rotate session: %w", pgError(err))
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("%w: session %d token was already rotated or revoked", ErrConflict, id)
+	}
+	return nil
+}
+
+// RevokeByPreviousToken отзывает сессию, чей прежний токен предъявлен
+// повторно, и возвращает ее ID
+func (r *SessionRepository) RevokeByPreviousToken(ctx context.Context, tokenHash []byte) (int, error) {
+	query := `
+		UPDATE sessions SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE previous_token_hash = $1
+		RETURNING id
+	`
+	var id int
+	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, notFound("session")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return id, nil
+}
+
+// ListActive возвращает действующие на момент now сессии пользователя,
+// последние активные первыми
+func (r *SessionRepository) ListActive(ctx context.Context, userID int, now time.Time) ([]*models.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2 AND idle_expires_at > $2
+		ORDER BY last_seen_at DESC, id DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*models.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return sessions, nil
+}
+
+// Revoke отзывает сессию пользователя. Чужая и уже отозванная сессия дают
+// ErrNotFound.
+func (r *SessionRepository) Revoke(ctx context.Context, userID, id int) error {
+	query := `UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return sessionAffected(result)
+}
+
+// RevokeAll отзывает все сессии пользователя и возвращает их число
+func (r *SessionRepository) RevokeAll(ctx context.Context, userID int) (int, error) {
+	query := `UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(n), nil
+}
+
+// Purge удаляет сессии, которые истекли или отозваны больше retention назад
+func (r *SessionRepository) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	seconds, err := retentionSeconds(retention)
+	if err != nil {
+		return 0, err
+	}
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM sessions
+		WHERE LEAST(expires_at, idle_expires_at, COALESCE(revoked_at, 'infinity')) <= NOW() - make_interval(secs => $1)
+	`, seconds)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge sessions: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(n), nil
+}
+
+func sessionAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return notFound("session")
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-articles-app/models"
+)
+
+// DefaultTopAuthors — сколько авторов попадает в Stats.TopAuthors по умолчанию
+const DefaultTopAuthors = 5
+
+// Stats — сводка по содержимому хранилища. Корзина считается отдельно и в
+// остальные поля не входит; комментарии считаются только у живых статей.
+type Stats struct {
+	Users            int                          `json:"users"`
+	UsersByRole      map[models.Role]int          `json:"users_by_role"`
+	TrashedUsers     int                          `json:"trashed_users"`
+	Articles         int                          `json:"articles"`
+	ArticlesByStatus map[models.ArticleStatus]int `json:"articles_by_status"`
+	TrashedArticles  int                          `json:"trashed_articles"`
+	Views            int64                        `json:"views"`
+	CommentsByStatus map[models.CommentStatus]int `json:"comments_by_status"`
+	Tags             int                          `json:"tags"`
+	TopAuthors       []AuthorStats                `json:"top_authors"`
+}
+
+// AuthorStats — автор и суммы по его живым статьям
+type AuthorStats struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Articles int    `json:"articles"`
+	Views    int64  `json:"views"`
+}
+
+// NewStats возвращает Stats с пустыми, а не nil картами
+func NewStats() *Stats {
+	return &Stats{
+		UsersByRole:      make(map[models.Role]int),
+		ArticlesByStatus: make(map[models.ArticleStatus]int),
+		CommentsByStatus: make(map[models.CommentStatus]int),
+		TopAuthors:       []AuthorStats{},
+	}
+}
+
+type StatsRepository struct {
+	db *sql.DB
+}
+
+func NewStatsRepository(db *sql.DB) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// Get собирает сводку. TopAuthors — до top авторов с наибольшим числом
+// статей (при равенстве — по просмотрам, затем по id); top <= 0 —
+// DefaultTopAuthors. Запросы выполняются в одной транзакции REPEATABLE READ,
+// чтобы числа были согласованы между собой.
+func (r *StatsRepository) Get(ctx context.Context, top int) (*Stats, error) {
+	if top <= 0 {
+		top = DefaultTopAuthors
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stats := NewStats()
+
+	counts := []struct {
+		query string
+		add   func(key string, n int)
+	}{
+		{
+			`SELECT role, COUNT(*) FROM users WHERE deleted_at IS NULL GROUP BY role`,
+			func(key string, n int) { stats.UsersByRole[models.Role(key)] = n; stats.Users += n },
+		},
+		{
+			`SELECT status, COUNT(*) FROM articles WHERE deleted_at IS NULL GROUP BY status`,
+			func(key string, n int) { stats.ArticlesByStatus[models.ArticleStatus(key)] = n; stats.Articles += n },
+		},
+		{
+			`SELECT c.status, COUNT(*) FROM comments c
+			JOIN articles a ON a.id = c.article_id AND a.deleted_at IS NULL
+			GROUP BY c.status`,
+			func(key string, n int) { stats.CommentsByStatus[models.CommentStatus(key)] = n },
+		},
+	}
+	for _, c := range counts {
+		if err := groupCounts(ctx, tx, c.query, c.add); err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM users WHERE deleted_at IS NOT NULL),
+			(SELECT COUNT(*) FROM articles WHERE deleted_at IS NOT NULL),
+			(SELECT COALESCE(SUM(views), 0) FROM articles WHERE deleted_at IS NULL),
+			(SELECT COUNT(*) FROM tags)`,
+	).Scan(&stats.TrashedUsers, &stats.TrashedArticles, &stats.Views, &stats.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stats: %w", err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT u.id, u.name, COUNT(*) AS articles, COALESCE(SUM(a.views), 0) AS views
+		FROM articles a
+		JOIN users u ON u.id = a.author_id AND u.deleted_at IS NULL
+		WHERE a.deleted_at IS NULL
+		GROUP BY u.id, u.name
+		ORDER BY articles DESC, views DESC, u.id
+		LIMIT $1`, top)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top authors: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var a AuthorStats
+		if err := rows.Scan(&a.ID, &a.Name, &a.Articles, &a.Views); err != nil {
+			return nil, fmt.Errorf("failed to query top authors: %w", err)
+		}
+		stats.TopAuthors = append(stats.TopAuthors, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query top authors: %w", err)
+	}
+	return stats, nil
+}
+
+// groupCounts читает пары (ключ, количество) запроса с GROUP BY
+func groupCounts(ctx context.Context, tx *sql.Tx, query string, add func(key string, n int)) error {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to query stats: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			key string
+			n   int
+		)
+		if err := rows.Scan(&key, &n); err != nil {
+			return fmt.Errorf("failed to query stats: %w", err)
+		}
+		add(key, n)
+	}
+	return rows.Err()
+}
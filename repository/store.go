@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"go-articles-app/models"
+	"go-articles-app/render"
+	"time"
+)
+
+// UserStore описывает хранилище пользователей. Реализуется UserRepository
+// (PostgreSQL) и memory.UserRepository (in-memory, для тестов).
+type UserStore interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id int) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetAll(ctx context.Context) ([]*models.User, error)
+	GetAllPage(ctx context.Context, page PageRequest) (*Page[*models.User], error)
+	Update(ctx context.Context, user *models.User) error
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	ListTrash(ctx context.Context) ([]*models.User, error)
+	Purge(ctx context.Context, retention time.Duration) (int, error)
+
+	// Пароли: хэши хранятся отдельно от models.User и не попадают в ответы API
+	CreateWithPassword(ctx context.Context, user *models.User, passwordHash string) error
+	GetCredentials(ctx context.Context, email string) (*models.User, string, error)
+	SetPasswordHash(ctx context.Context, id int, passwordHash string) error
+
+	SetRole(ctx context.Context, id int, role models.Role) error
+}
+
+// ArticleStore описывает хранилище статей. Реализуется ArticleRepository
+// (PostgreSQL) и memory.ArticleRepository (in-memory, для тестов).
+type ArticleStore interface {
+	Create(ctx context.Context, article *models.Article) error
+	GetByID(ctx context.Context, id int) (*models.Article, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Article, error)
+	GetByAuthorID(ctx context.Context, authorID int) ([]*models.Article, error)
+	GetPublished(ctx context.Context) ([]*models.Article, error)
+	// GetByAuthorIDPage — статьи автора; без includeDrafts только
+	// опубликованные, и limit с курсором считаются по ним
+	GetByAuthorIDPage(ctx context.Context, authorID int, includeDrafts bool, page PageRequest) (*Page[*models.Article], error)
+	GetPublishedPage(ctx context.Context, page PageRequest) (*Page[*models.Article], error)
+	Find(ctx context.Context, q ArticleQuery) (*ArticleList, error)
+	Update(ctx context.Context, article *models.Article) error
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	ListTrash(ctx context.Context) ([]*models.Article, error)
+	Purge(ctx context.Context, retention time.Duration) (int, error)
+	Publish(ctx context.Context, id int) error
+	Transition(ctx context.Context, id int, to models.ArticleStatus) (*models.Article, error)
+	ListTransitions(ctx context.Context, articleID int) ([]*models.ArticleTransition, error)
+	Schedule(ctx context.Context, id int, action ScheduleAction, at time.Time) (*models.Article, error)
+	CancelSchedule(ctx context.Context, id int, action ScheduleAction) (*models.Article, error)
+	ProcessDueSchedules(ctx context.Context, limit int) (ScheduleRun, error)
+	IncrementViews(ctx context.Context, id int) error
+	CreateArticleWithAuthor(ctx context.Context, userName, userEmail, articleTitle, articleContent string) (*models.User, *models.Article, error)
+	GetArticleWithAuthor(ctx context.Context, id int) (*ArticleWithAuthor, error)
+	ListFeed(ctx context.Context, q FeedQuery) ([]*ArticleWithAuthor, error)
+	ListRevisions(ctx context.Context, articleID int) ([]*models.ArticleRevision, error)
+	GetRevision(ctx context.Context, articleID, revision int) (*models.ArticleRevision, error)
+	DiffRevisions(ctx context.Context, articleID, from, to int) (*RevisionDiff, error)
+	RestoreRevision(ctx context.Context, articleID, revision int) (*models.Article, error)
+	GetRendered(ctx context.Context, id int) (*render.Document, error)
+}
+
+// TagStore описывает хранилище тегов и их связей со статьями
+type TagStore interface {
+	Create(ctx context.Context, name string) (*models.Tag, error)
+	GetByName(ctx context.Context, name string) (*models.Tag, error)
+	List(ctx context.Context) ([]*TagUsage, error)
+	GetArticleTags(ctx context.Context, articleID int) ([]*models.Tag, error)
+	SetArticleTags(ctx context.Context, articleID int, names []string) ([]*models.Tag, error)
+	GetArticlesByTags(ctx context.Context, names []string, match TagMatch) ([]*models.Article, error)
+	Rename(ctx context.Context, id int, newName string) (*models.Tag, error)
+	Merge(ctx context.Context, sourceID, targetID int) error
+	Delete(ctx context.Context, id int) error
+}
+
+// CategoryStore описывает хранилище рубрик и принадлежности статей к ним
+type CategoryStore interface {
+	Create(ctx context.Context, category *models.Category) error
+	GetBySlug(ctx context.Context, slug string) (*models.Category, error)
+	List(ctx context.Context) ([]*CategoryUsage, error)
+	Update(ctx context.Context, category *models.Category) error
+	Delete(ctx context.Context, id int) error
+	GetArticleCategory(ctx context.Context, articleID int) (*models.Category, error)
+	SetArticleCategory(ctx context.Context, articleID int, categoryID *int) (*models.Category, error)
+	GetArticlesByCategory(ctx context.Context, categoryID int) ([]*models.Article, error)
+}
+
+// CommentStore описывает хранилище комментариев с ветками ответов и модерацией
+type CommentStore interface {
+	Create(ctx context.Context, comment *models.Comment) error
+	GetByID(ctx context.Context, id int) (*models.Comment, error)
+	GetThread(ctx context.Context, articleID int, includeHidden bool) ([]*models.Comment, error)
+	ListByStatus(ctx context.Context, status models.CommentStatus, limit int) ([]*models.Comment, error)
+	Moderate(ctx context.Context, id int, status models.CommentStatus) (*models.Comment, error)
+	Delete(ctx context.Context, id int) error
+	CountByArticles(ctx context.Context, articleIDs []int) (map[int]int, error)
+}
+
+// SessionStore описывает хранилище сессий входа. Токены передаются только
+// в виде хэшей; время задает вызывающий, кроме Purge.
+type SessionStore interface {
+	Create(ctx context.Context, session *models.Session, tokenHash []byte) error
+	GetByToken(ctx context.Context, tokenHash []byte) (*models.Session, error)
+	Touch(ctx context.Context, id int, seenAt, idleExpiresAt time.Time) error
+	Rotate(ctx context.Context, id int, oldHash, newHash []byte, seenAt, idleExpiresAt time.Time) error
+	RevokeByPreviousToken(ctx context.Context, tokenHash []byte) (int, error)
+	ListActive(ctx context.Context, userID int, now time.Time) ([]*models.Session, error)
+	Revoke(ctx context.Context, userID, id int) error
+	RevokeAll(ctx context.Context, userID int) (int, error)
+	Purge(ctx context.Context, retention time.Duration) (int, error)
+}
+
+// AuditStore описывает чтение журнала аудита. Записи в журнал делают сами
+// UserStore и ArticleStore в транзакциях изменений.
+type AuditStore interface {
+	List(ctx context.Context, q AuditQuery) (*Page[*models.AuditEvent], error)
+}
+
+// StatsStore описывает сводку по содержимому хранилища для команды stats
+type StatsStore interface {
+	Get(ctx context.Context, top int) (*Stats, error)
+}
+
+// BulkStore описывает массовую загрузку для генератора тестовых данных
+// (команда seed). Каждый вызов выполняется целиком или не выполняется.
+type BulkStore interface {
+	InsertUsers(ctx context.Context, users []*models.User) error
+	InsertArticles(ctx context.Context, articles []*models.Article) error
+}
+
+var (
+	_ UserStore     = (*UserRepository)(nil)
+	_ ArticleStore  = (*ArticleRepository)(nil)
+	_ TagStore      = (*TagRepository)(nil)
+	_ CategoryStore = (*CategoryRepository)(nil)
+	_ CommentStore  = (*CommentRepository)(nil)
+	_ SessionStore  = (*SessionRepository)(nil)
+	_ AuditStore    = (*AuditRepository)(nil)
+	_ StatsStore    = (*StatsRepository)(nil)
+	_ BulkStore     = (*BulkRepository)(nil)
+)
@@ -0,0 +1,316 @@
+package storetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"slices"
+	"testing"
+	"time"
+)
+
+func runAuditTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"UserLifecycle", testAuditUserLifecycle},
+		{"ArticleLifecycle", testAuditArticleLifecycle},
+		{"Snapshots", testAuditSnapshots},
+		{"FailedChangeNotRecorded", testAuditFailedChange},
+		{"FilterByActorAndTime", testAuditFilter},
+		{"InvalidQuery", testAuditInvalidQuery},
+		{"Pagination", testAuditPagination},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+func mustListAudit(t *testing.T, s Stores, q repository.AuditQuery) []*models.AuditEvent {
+	t.Helper()
+	page, err := s.Audit.List(context.Background(), q)
+	if err != nil {
+		t.Fatalf("List(%+v): %v", q, err)
+	}
+	return page.Items
+}
+
+func auditActions(events []*models.AuditEvent) []models.AuditAction {
+	actions := make([]models.AuditAction, 0, len(events))
+	for _, e := range events {
+		actions = append(actions, e.Action)
+	}
+	return actions
+}
+
+// decodeSnapshot разбирает снимок в map: PostgreSQL хранит JSONB в
+// нормализованном виде, поэтому байты сравнивать нельзя
+func decodeSnapshot(t *testing.T, raw json.RawMessage) map[string]any {
+	t.Helper()
+	if raw == nil {
+		return nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		t.Fatalf("decode snapshot %s: %v", raw, err)
+	}
+	return m
+}
+
+func testAuditUserLifecycle(t *testing.T, s Stores) {
+	admin := mustCreateUser(t, s, "admin@example.com")
+	ctx := repository.WithActor(context.Background(), admin.ID)
+	ctx = repository.WithRequestInfo(ctx, repository.RequestInfo{ID: "req-1", IP: "192.0.2.1", UserAgent: "curl/8"})
+
+	user := &models.User{Email: "alice@example.com", Name: "Alice"}
+	if err := s.Users.Create(ctx, user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	user.Name = "Alice Smith"
+	if err := s.Users.Update(ctx, user); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := s.Users.SetRole(ctx, user.ID, models.RoleEditor); err != nil {
+		t.Fatalf("SetRole: %v", err)
+	}
+	if err := s.Users.SetPasswordHash(ctx, user.ID, "hash"); err != nil {
+		t.Fatalf("SetPasswordHash: %v", err)
+	}
+	if err := s.Users.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Users.Restore(ctx, user.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	events := mustListAudit(t, s, repository.AuditQuery{EntityType: models.AuditUserEntity, EntityID: user.ID})
+	want := []models.AuditAction{
+		models.AuditRestore, models.AuditDelete, models.AuditSetPassword,
+		models.AuditSetRole, models.AuditUpdate, models.AuditCreate,
+	}
+	if got := auditActions(events); !slices.Equal(got, want) {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+	for _, e := range events {
+		if e.ActorID == nil || *e.ActorID != admin.ID {
+			t.Fatalf("%s: actor = %v, want %d", e.Action, e.ActorID, admin.ID)
+		}
+		if e.RequestID != "req-1" || e.IP != "192.0.2.1" || e.UserAgent != "curl/8" {
+			t.Fatalf("%s: request metadata = %q %q %q", e.Action, e.RequestID, e.IP, e.UserAgent)
+		}
+		if e.CreatedAt.IsZero() {
+			t.Fatalf("%s: created_at is zero", e.Action)
+		}
+	}
+	if events[2].Before != nil || events[2].After != nil {
+		t.Fatalf("set_password recorded snapshots: %s / %s", events[2].Before, events[2].After)
+	}
+}
+
+func testAuditArticleLifecycle(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	ctx := repository.WithActor(context.Background(), author.ID)
+
+	article := &models.Article{Title: "Draft", Content: "Text", AuthorID: author.ID}
+	if err := s.Articles.Create(ctx, article); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	article.Title = "Final"
+	if err := s.Articles.Update(ctx, article); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := s.Articles.Transition(ctx, article.ID, models.ArticleInReview); err != nil {
+		t.Fatalf("Transition: %v", err)
+	}
+	if err := s.Articles.Publish(ctx, article.ID); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if _, err := s.Articles.Schedule(ctx, article.ID, repository.ScheduleUnpublish, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Schedule: %v", err)
+	}
+	if err := s.Articles.Delete(ctx, article.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Articles.Restore(ctx, article.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	events := mustListAudit(t, s, repository.AuditQuery{EntityType: models.AuditArticleEntity, EntityID: article.ID})
+	want := []models.AuditAction{
+		models.AuditRestore, models.AuditDelete, models.AuditSchedule, models.AuditPublish,
+		models.AuditTransition, models.AuditUpdate, models.AuditCreate,
+	}
+	if got := auditActions(events); !slices.Equal(got, want) {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+
+	publish := events[3]
+	if got := decodeSnapshot(t, publish.Before)["status"]; got != string(models.ArticleInReview) {
+		t.Fatalf("publish before status = %v", got)
+	}
+	if got := decodeSnapshot(t, publish.After)["status"]; got != string(models.ArticlePublished) {
+		t.Fatalf("publish after status = %v", got)
+	}
+	if schedule := decodeSnapshot(t, events[2].After); schedule["unpublish_at"] == nil {
+		t.Fatalf("schedule after = %s, want unpublish_at", events[2].After)
+	}
+}
+
+func testAuditSnapshots(t *testing.T, s Stores) {
+	ctx := context.Background()
+	user := mustCreateUser(t, s, "alice@example.com")
+	user.Name = "Alice Smith"
+	if err := s.Users.Update(ctx, user); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := s.Users.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	events := mustListAudit(t, s, repository.AuditQuery{EntityType: models.AuditUserEntity, EntityID: user.ID})
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	deleted, updated, created := events[0], events[1], events[2]
+
+	if created.Before != nil {
+		t.Fatalf("create has before snapshot %s", created.Before)
+	}
+	if got := decodeSnapshot(t, created.After)["email"]; got != "alice@example.com" {
+		t.Fatalf("create after email = %v", got)
+	}
+	if got := decodeSnapshot(t, updated.Before)["name"]; got != "alice" {
+		t.Fatalf("update before name = %v, want alice", got)
+	}
+	if got := decodeSnapshot(t, updated.After)["name"]; got != "Alice Smith" {
+		t.Fatalf("update after name = %v, want Alice Smith", got)
+	}
+	if deleted.After != nil {
+		t.Fatalf("delete has after snapshot %s", deleted.After)
+	}
+	if got := decodeSnapshot(t, deleted.Before)["name"]; got != "Alice Smith" {
+		t.Fatalf("delete before name = %v", got)
+	}
+	if created.ActorID != nil {
+		t.Fatalf("actor without WithActor = %d, want nil", *created.ActorID)
+	}
+}
+
+func testAuditFailedChange(t *testing.T, s Stores) {
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice@example.com")
+	mustCreateUser(t, s, "bob@example.com")
+
+	// Нарушение уникальности откатывает и событие
+	alice.Email = "bob@example.com"
+	if err := s.Users.Update(ctx, alice); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("Update with taken email: got %v, want ErrAlreadyExists", err)
+	}
+	if err := s.Articles.Create(ctx, &models.Article{Title: "Orphan", Content: "Text", AuthorID: 999}); err == nil {
+		t.Fatal("Create with unknown author succeeded")
+	}
+	if err := s.Articles.Delete(ctx, 999); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Delete missing: got %v, want ErrNotFound", err)
+	}
+
+	events := mustListAudit(t, s, repository.AuditQuery{})
+	want := []models.AuditAction{models.AuditCreate, models.AuditCreate}
+	if got := auditActions(events); !slices.Equal(got, want) {
+		t.Fatalf("actions = %v, want %v", got, want)
+	}
+}
+
+func testAuditFilter(t *testing.T, s Stores) {
+	alice := mustCreateUser(t, s, "alice@example.com")
+	bob := mustCreateUser(t, s, "bob@example.com")
+	mustCreateArticle(t, s, alice.ID, "Unattributed")
+
+	start := time.Now().Add(-time.Hour)
+	asAlice := repository.WithActor(context.Background(), alice.ID)
+	asBob := repository.WithActor(context.Background(), bob.ID)
+	first := &models.Article{Title: "First", Content: "Text", AuthorID: alice.ID}
+	if err := s.Articles.Create(asAlice, first); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := s.Articles.Publish(asBob, first.ID); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if err := s.Users.SetRole(asBob, alice.ID, models.RoleEditor); err != nil {
+		t.Fatalf("SetRole: %v", err)
+	}
+
+	byBob := mustListAudit(t, s, repository.AuditQuery{ActorID: bob.ID})
+	if got, want := auditActions(byBob), []models.AuditAction{models.AuditSetRole, models.AuditPublish}; !slices.Equal(got, want) {
+		t.Fatalf("bob's actions = %v, want %v", got, want)
+	}
+	byAlice := mustListAudit(t, s, repository.AuditQuery{ActorID: alice.ID})
+	if len(byAlice) != 1 || byAlice[0].EntityID != first.ID {
+		t.Fatalf("alice's events = %+v, want create of article %d", byAlice, first.ID)
+	}
+
+	articles := mustListAudit(t, s, repository.AuditQuery{EntityType: models.AuditArticleEntity})
+	if len(articles) != 3 {
+		t.Fatalf("got %d article events, want 3", len(articles))
+	}
+	publishes := mustListAudit(t, s, repository.AuditQuery{Action: models.AuditPublish})
+	if len(publishes) != 1 || publishes[0].EntityID != first.ID {
+		t.Fatalf("publish events = %+v", publishes)
+	}
+
+	// Часы базы и теста могут расходиться, поэтому границы берутся с запасом
+	if got := mustListAudit(t, s, repository.AuditQuery{Since: start}); len(got) != 6 {
+		t.Fatalf("since an hour ago: got %d events, want 6", len(got))
+	}
+	if got := mustListAudit(t, s, repository.AuditQuery{Until: start}); len(got) != 0 {
+		t.Fatalf("until an hour ago: got %d events, want 0", len(got))
+	}
+	if got := mustListAudit(t, s, repository.AuditQuery{Since: time.Now().Add(time.Hour)}); len(got) != 0 {
+		t.Fatalf("since an hour later: got %d events, want 0", len(got))
+	}
+}
+
+func testAuditInvalidQuery(t *testing.T, s Stores) {
+	now := time.Now()
+	queries := []repository.AuditQuery{
+		{EntityID: 1},
+		{EntityType: "comment"},
+		{ActorID: -1},
+		{Since: now, Until: now.Add(-time.Minute)},
+	}
+	for _, q := range queries {
+		if _, err := s.Audit.List(context.Background(), q); !errors.Is(err, repository.ErrInvalidQuery) {
+			t.Fatalf("List(%+v): got %v, want ErrInvalidQuery", q, err)
+		}
+	}
+	if _, err := s.Audit.List(context.Background(), repository.AuditQuery{Page: repository.PageRequest{Cursor: "!"}}); !errors.Is(err, repository.ErrInvalidCursor) {
+		t.Fatalf("bad cursor: got %v, want ErrInvalidCursor", err)
+	}
+}
+
+func testAuditPagination(t *testing.T, s Stores) {
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com", "e@example.com"} {
+		mustCreateUser(t, s, email)
+	}
+
+	var ids []int
+	page := repository.PageRequest{Limit: 2}
+	for {
+		res, err := s.Audit.List(context.Background(), repository.AuditQuery{Page: page})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		for _, e := range res.Items {
+			ids = append(ids, e.EntityID)
+		}
+		if !res.HasMore {
+			break
+		}
+		page.Cursor = res.NextCursor
+	}
+	if want := []int{5, 4, 3, 2, 1}; !slices.Equal(ids, want) {
+		t.Fatalf("entity ids across pages = %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,212 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"go-articles-app/slug"
+	"testing"
+	"time"
+)
+
+func runBulkTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"InsertUsers", testBulkInsertUsers},
+		{"InsertUsersDuplicateEmail", testBulkInsertUsersDuplicateEmail},
+		{"InsertArticles", testBulkInsertArticles},
+		{"InsertArticlesInvalid", testBulkInsertArticlesInvalid},
+		{"InsertArticlesTakenSlug", testBulkInsertArticlesTakenSlug},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+// bulkTime — момент в прошлом с точностью до секунды, одинаково
+// сохраняемый обеими реализациями
+func bulkTime(daysAgo int) time.Time {
+	return time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC).AddDate(0, 0, -daysAgo)
+}
+
+func testBulkInsertUsers(t *testing.T, s Stores) {
+	ctx := context.Background()
+	users := []*models.User{
+		{Email: "anna@example.com", Name: "Анна", Role: models.RoleEditor, CreatedAt: bulkTime(30), UpdatedAt: bulkTime(10)},
+		{Email: "bob@example.com", Name: "Bob", CreatedAt: bulkTime(20), UpdatedAt: bulkTime(20)},
+	}
+	if err := s.Bulk.InsertUsers(ctx, users); err != nil {
+		t.Fatalf("InsertUsers: %v", err)
+	}
+	if users[0].ID == 0 || users[1].ID == 0 || users[0].ID == users[1].ID {
+		t.Fatalf("IDs = %d, %d, want distinct non-zero", users[0].ID, users[1].ID)
+	}
+
+	got, err := s.Users.GetByID(ctx, users[0].ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Email != "anna@example.com" || got.Role != models.RoleEditor ||
+		!got.CreatedAt.Equal(bulkTime(30)) || !got.UpdatedAt.Equal(bulkTime(10)) {
+		t.Fatalf("GetByID = %+v", got)
+	}
+	if got, err := s.Users.GetByID(ctx, users[1].ID); err != nil || got.Role != models.DefaultRole {
+		t.Fatalf("GetByID = %+v, %v, want default role", got, err)
+	}
+
+	// После загрузки обычное создание продолжает нумерацию
+	carol := mustCreateUser(t, s, "carol@example.com")
+	if carol.ID <= users[1].ID {
+		t.Fatalf("Create after InsertUsers: ID = %d, want > %d", carol.ID, users[1].ID)
+	}
+}
+
+func testBulkInsertUsersDuplicateEmail(t *testing.T, s Stores) {
+	ctx := context.Background()
+	mustCreateUser(t, s, "taken@example.com")
+
+	users := []*models.User{
+		{Email: "fresh@example.com", Name: "Fresh", CreatedAt: bulkTime(1), UpdatedAt: bulkTime(1)},
+		{Email: "taken@example.com", Name: "Taken", CreatedAt: bulkTime(1), UpdatedAt: bulkTime(1)},
+	}
+	if err := s.Bulk.InsertUsers(ctx, users); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("InsertUsers with a taken email: err = %v, want ErrAlreadyExists", err)
+	}
+	if _, err := s.Users.GetByEmail(ctx, "fresh@example.com"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByEmail after a failed batch: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testBulkInsertArticles(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "author@example.com")
+	published := bulkTime(5)
+	articles := []*models.Article{
+		{
+			Title: "Привет, мир", Content: "Первая статья", AuthorID: author.ID,
+			Status: models.ArticlePublished, Views: 42, PublishedAt: &published,
+			CreatedAt: bulkTime(6), UpdatedAt: bulkTime(4),
+		},
+		{
+			Title: "Привет, мир", Content: "Черновик с тем же заголовком", AuthorID: author.ID,
+			Status: models.ArticleDraft, CreatedAt: bulkTime(3), UpdatedAt: bulkTime(3),
+		},
+	}
+	if err := s.Bulk.InsertArticles(ctx, articles); err != nil {
+		t.Fatalf("InsertArticles: %v", err)
+	}
+
+	first := articles[0]
+	if want := slug.WithID(first.Title, first.ID); first.Slug != want {
+		t.Fatalf("Slug = %q, want %q", first.Slug, want)
+	}
+	if articles[1].Slug == first.Slug {
+		t.Fatalf("articles with the same title share slug %q", first.Slug)
+	}
+
+	got, err := s.Articles.GetBySlug(ctx, first.Slug)
+	if err != nil {
+		t.Fatalf("GetBySlug: %v", err)
+	}
+	if got.ID != first.ID || got.Status != models.ArticlePublished || got.Views != 42 ||
+		got.PublishedAt == nil || !got.PublishedAt.Equal(published) ||
+		!got.CreatedAt.Equal(bulkTime(6)) || !got.UpdatedAt.Equal(bulkTime(4)) {
+		t.Fatalf("GetBySlug = %+v", got)
+	}
+
+	revisions, err := s.Articles.ListRevisions(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions: %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Revision != 1 || revisions[0].Content != first.Content ||
+		revisions[0].EditorID == nil || *revisions[0].EditorID != author.ID || !revisions[0].CreatedAt.Equal(bulkTime(6)) {
+		t.Fatalf("ListRevisions = %+v, want the first revision by the author", revisions)
+	}
+
+	// Загруженные статьи участвуют в обычных запросах и правках
+	list, err := s.Articles.Find(ctx, repository.ArticleQuery{Filter: repository.ArticleFilter{Statuses: []models.ArticleStatus{models.ArticleDraft}}})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if list.Total != 1 || list.Articles[0].ID != articles[1].ID {
+		t.Fatalf("Find drafts = %+v", list)
+	}
+	if err := s.Articles.Publish(ctx, articles[1].ID); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+}
+
+func testBulkInsertArticlesInvalid(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "author@example.com")
+	valid := func() *models.Article {
+		return &models.Article{
+			Title: "Valid", Content: "Valid", AuthorID: author.ID,
+			Status: models.ArticleDraft, CreatedAt: bulkTime(2), UpdatedAt: bulkTime(1),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(a *models.Article)
+		want   error
+	}{
+		{"UnknownStatus", func(a *models.Article) { a.Status = "deleted" }, repository.ErrInvalidInput},
+		{"PublishedWithoutDate", func(a *models.Article) { a.Status = models.ArticlePublished }, repository.ErrInvalidInput},
+		{"UpdatedBeforeCreated", func(a *models.Article) { a.UpdatedAt = bulkTime(3) }, repository.ErrInvalidInput},
+		{"UnknownAuthor", func(a *models.Article) { a.AuthorID = author.ID + 1000 }, repository.ErrConflict},
+	}
+	for _, tt := range tests {
+		bad := valid()
+		tt.modify(bad)
+		if err := s.Bulk.InsertArticles(ctx, []*models.Article{valid(), bad}); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	list, err := s.Articles.Find(ctx, repository.ArticleQuery{})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if list.Total != 0 {
+		t.Fatalf("failed batches left %d articles", list.Total)
+	}
+}
+
+// Слаг с номером статьи может быть уже занят: статья "Go <n>" получила
+// его от Create
+func testBulkInsertArticlesTakenSlug(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "author@example.com")
+	first := mustCreateArticle(t, s, author.ID, "Go")
+	// Следующие id — first.ID+1 у статьи ниже и first.ID+2 у загружаемой
+	next := first.ID + 2
+	taken := mustCreateArticle(t, s, author.ID, fmt.Sprintf("Go %d", next))
+	if want := slug.WithID("Go", next); taken.Slug != want {
+		t.Fatalf("Slug = %q, want %q", taken.Slug, want)
+	}
+
+	articles := []*models.Article{
+		{Title: "Go", Content: "text", AuthorID: author.ID, Status: models.ArticleDraft, CreatedAt: bulkTime(1), UpdatedAt: bulkTime(1)},
+		{Title: fmt.Sprintf("Go %d", next), Content: "text", AuthorID: author.ID, Status: models.ArticleDraft, CreatedAt: bulkTime(1), UpdatedAt: bulkTime(1)},
+	}
+	if err := s.Bulk.InsertArticles(ctx, articles); err != nil {
+		t.Fatalf("InsertArticles: %v", err)
+	}
+	if articles[0].ID != next {
+		t.Fatalf("ID = %d, want %d", articles[0].ID, next)
+	}
+	if articles[0].Slug == taken.Slug || articles[1].Slug == articles[0].Slug {
+		t.Fatalf("slugs = %q, %q, taken %q", articles[0].Slug, articles[1].Slug, taken.Slug)
+	}
+	for _, a := range append(articles, taken) {
+		got, err := s.Articles.GetBySlug(ctx, a.Slug)
+		if err != nil || got.ID != a.ID {
+			t.Fatalf("GetBySlug(%q) = %+v, %v, want article %d", a.Slug, got, err, a.ID)
+		}
+	}
+}
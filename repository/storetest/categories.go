@@ -0,0 +1,230 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"slices"
+	"testing"
+)
+
+func runCategoryTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"CreateAndGetBySlug", testCategoryCreateAndGetBySlug},
+		{"Update", testCategoryUpdate},
+		{"SetArticleCategory", testSetArticleCategory},
+		{"ListWithCounts", testCategoryListWithCounts},
+		{"Delete", testCategoryDelete},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+func mustCreateCategory(t *testing.T, s Stores, name string) *models.Category {
+	t.Helper()
+	c := &models.Category{Name: name}
+	if err := s.Categories.Create(context.Background(), c); err != nil {
+		t.Fatalf("Create category %q: %v", name, err)
+	}
+	return c
+}
+
+func testCategoryCreateAndGetBySlug(t *testing.T, s Stores) {
+	ctx := context.Background()
+	backend := mustCreateCategory(t, s, "  Бэкенд   и базы ")
+	if backend.ID == 0 || backend.Name != "Бэкенд и базы" || backend.Slug != "bekend-i-bazy" || backend.CreatedAt.IsZero() {
+		t.Fatalf("Create = %+v", backend)
+	}
+
+	got, err := s.Categories.GetBySlug(ctx, backend.Slug)
+	if err != nil {
+		t.Fatalf("GetBySlug: %v", err)
+	}
+	if got.ID != backend.ID || got.Name != backend.Name {
+		t.Fatalf("GetBySlug = %+v, want %+v", got, backend)
+	}
+	if _, err := s.Categories.GetBySlug(ctx, "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetBySlug(missing): err = %v, want ErrNotFound", err)
+	}
+
+	// Имя уникально без учета регистра, слаг — точно; "C++" дает слаг "c"
+	mustCreateCategory(t, s, "C")
+	for _, c := range []*models.Category{{Name: "бэкенд И базы"}, {Name: "C++"}} {
+		if err := s.Categories.Create(ctx, c); !errors.Is(err, repository.ErrAlreadyExists) {
+			t.Errorf("Create(%q): err = %v, want ErrAlreadyExists", c.Name, err)
+		}
+	}
+	cpp := &models.Category{Name: "C++", Slug: "cpp"}
+	if err := s.Categories.Create(ctx, cpp); err != nil {
+		t.Fatalf("Create with explicit slug: %v", err)
+	}
+
+	for _, c := range []*models.Category{{Name: "   "}, {Name: "Go", Slug: "Not A Slug"}} {
+		if err := s.Categories.Create(ctx, c); !errors.Is(err, repository.ErrInvalidInput) {
+			t.Errorf("Create(%q, %q): err = %v, want ErrInvalidInput", c.Name, c.Slug, err)
+		}
+	}
+}
+
+func testCategoryUpdate(t *testing.T, s Stores) {
+	ctx := context.Background()
+	backend := mustCreateCategory(t, s, "Backend")
+	mustCreateCategory(t, s, "Frontend")
+
+	update := &models.Category{ID: backend.ID, Name: "Server side"}
+	if err := s.Categories.Update(ctx, update); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if update.Slug != "server-side" || !update.CreatedAt.Equal(backend.CreatedAt) {
+		t.Fatalf("Update = %+v", update)
+	}
+	if _, err := s.Categories.GetBySlug(ctx, "backend"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetBySlug(old slug): err = %v, want ErrNotFound", err)
+	}
+	if got, err := s.Categories.GetBySlug(ctx, "server-side"); err != nil || got.Name != "Server side" {
+		t.Fatalf("GetBySlug(new slug) = %+v, %v", got, err)
+	}
+
+	if err := s.Categories.Update(ctx, &models.Category{ID: backend.ID, Name: "frontend"}); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("Update to a taken name: err = %v, want ErrAlreadyExists", err)
+	}
+	if err := s.Categories.Update(ctx, &models.Category{ID: backend.ID + 1000, Name: "Other"}); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Update missing: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testSetArticleCategory(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+	backend := mustCreateCategory(t, s, "Backend")
+	frontend := mustCreateCategory(t, s, "Frontend")
+
+	if _, err := s.Categories.GetArticleCategory(ctx, article.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetArticleCategory without category: err = %v, want ErrNotFound", err)
+	}
+
+	for _, c := range []*models.Category{backend, frontend} {
+		got, err := s.Categories.SetArticleCategory(ctx, article.ID, &c.ID)
+		if err != nil {
+			t.Fatalf("SetArticleCategory(%d): %v", c.ID, err)
+		}
+		if got == nil || got.ID != c.ID || got.Slug != c.Slug {
+			t.Fatalf("SetArticleCategory = %+v, want %+v", got, c)
+		}
+	}
+	if got, err := s.Categories.GetArticleCategory(ctx, article.ID); err != nil || got.ID != frontend.ID {
+		t.Fatalf("GetArticleCategory = %+v, %v, want %d", got, err, frontend.ID)
+	}
+
+	missing := frontend.ID + 1000
+	if _, err := s.Categories.SetArticleCategory(ctx, article.ID, &missing); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("SetArticleCategory(missing category): err = %v, want ErrConflict", err)
+	}
+	if _, err := s.Categories.SetArticleCategory(ctx, article.ID+1000, &backend.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("SetArticleCategory(missing article): err = %v, want ErrNotFound", err)
+	}
+
+	if got, err := s.Categories.SetArticleCategory(ctx, article.ID, nil); err != nil || got != nil {
+		t.Fatalf("SetArticleCategory(nil) = %+v, %v", got, err)
+	}
+	if _, err := s.Categories.GetArticleCategory(ctx, article.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetArticleCategory after clearing: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testCategoryListWithCounts(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	frontend := mustCreateCategory(t, s, "frontend")
+	backend := mustCreateCategory(t, s, "Backend")
+	mustCreateCategory(t, s, "Empty")
+
+	var want []int
+	for _, title := range []string{"A", "B", "C"} {
+		a := mustCreateArticle(t, s, author.ID, title)
+		if _, err := s.Categories.SetArticleCategory(ctx, a.ID, &backend.ID); err != nil {
+			t.Fatalf("SetArticleCategory: %v", err)
+		}
+		if err := s.Articles.Publish(ctx, a.ID); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+		want = append([]int{a.ID}, want...)
+	}
+	// Черновик остается в рубрике, но не входит в счетчик
+	draft := mustCreateArticle(t, s, author.ID, "Draft")
+	if _, err := s.Categories.SetArticleCategory(ctx, draft.ID, &backend.ID); err != nil {
+		t.Fatalf("SetArticleCategory: %v", err)
+	}
+	want = append([]int{draft.ID}, want...)
+	trashed := mustCreateArticle(t, s, author.ID, "Trashed")
+	if _, err := s.Categories.SetArticleCategory(ctx, trashed.ID, &frontend.ID); err != nil {
+		t.Fatalf("SetArticleCategory: %v", err)
+	}
+	if err := s.Articles.Delete(ctx, trashed.ID); err != nil {
+		t.Fatalf("Delete article: %v", err)
+	}
+
+	usages, err := s.Categories.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var got []string
+	for _, u := range usages {
+		got = append(got, u.Category.Name)
+		if want := map[string]int{"Backend": 3}[u.Category.Name]; u.ArticleCount != want {
+			t.Errorf("%s: ArticleCount = %d, want %d", u.Category.Name, u.ArticleCount, want)
+		}
+	}
+	if !slices.Equal(got, []string{"Backend", "Empty", "frontend"}) {
+		t.Fatalf("List order = %v", got)
+	}
+
+	articles, err := s.Categories.GetArticlesByCategory(ctx, backend.ID)
+	if err != nil {
+		t.Fatalf("GetArticlesByCategory: %v", err)
+	}
+	if ids := articleIDs(articles); !slices.Equal(ids, want) {
+		t.Fatalf("GetArticlesByCategory = %v, want %v", ids, want)
+	}
+	if articles, err := s.Categories.GetArticlesByCategory(ctx, frontend.ID); err != nil || articles == nil || len(articles) != 0 {
+		t.Fatalf("GetArticlesByCategory(trashed only) = %v, %v, want empty", articles, err)
+	}
+
+	// Восстановленная статья возвращается в рубрику
+	if err := s.Articles.Restore(ctx, trashed.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got, err := s.Categories.GetArticleCategory(ctx, trashed.ID); err != nil || got.ID != frontend.ID {
+		t.Fatalf("GetArticleCategory after Restore = %+v, %v", got, err)
+	}
+}
+
+func testCategoryDelete(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+	backend := mustCreateCategory(t, s, "Backend")
+	if _, err := s.Categories.SetArticleCategory(ctx, article.ID, &backend.ID); err != nil {
+		t.Fatalf("SetArticleCategory: %v", err)
+	}
+
+	if err := s.Categories.Delete(ctx, backend.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Categories.Delete(ctx, backend.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("second Delete: err = %v, want ErrNotFound", err)
+	}
+	// Статья остается, но без рубрики
+	if _, err := s.Articles.GetByID(ctx, article.ID); err != nil {
+		t.Fatalf("GetByID after category Delete: %v", err)
+	}
+	if _, err := s.Categories.GetArticleCategory(ctx, article.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetArticleCategory after Delete: err = %v, want ErrNotFound", err)
+	}
+}
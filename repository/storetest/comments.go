@@ -0,0 +1,343 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func runCommentTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"CreateDefaults", testCommentCreateDefaults},
+		{"CreateInvalid", testCommentCreateInvalid},
+		{"ReplyRules", testCommentReplyRules},
+		{"ThreadOrderAndDepth", testCommentThreadOrder},
+		{"ThreadVisibility", testCommentThreadVisibility},
+		{"ModerationTransitions", testCommentModeration},
+		{"ModerationQueue", testCommentModerationQueue},
+		{"CountByArticles", testCommentCountByArticles},
+		{"Cascades", testCommentCascades},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+func testCommentCreateDefaults(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	comment := &models.Comment{ArticleID: article.ID, AuthorID: author.ID, Body: "  Nice post  "}
+	if err := s.Comments.Create(ctx, comment); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if comment.ID == 0 || comment.CreatedAt.IsZero() {
+		t.Fatalf("Create did not fill ID/timestamps: %+v", comment)
+	}
+	if comment.Status != models.CommentPending || comment.Body != "Nice post" {
+		t.Fatalf("Create = %+v, want pending with trimmed body", comment)
+	}
+
+	got, err := s.Comments.GetByID(ctx, comment.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Body != "Nice post" || got.ParentID != nil || got.ArticleID != article.ID {
+		t.Fatalf("GetByID = %+v", got)
+	}
+	if _, err := s.Comments.GetByID(ctx, 999999); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByID missing: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testCommentCreateInvalid(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	tests := []struct {
+		name    string
+		comment *models.Comment
+		want    error
+	}{
+		{"EmptyBody", &models.Comment{ArticleID: article.ID, AuthorID: author.ID, Body: "   "}, repository.ErrInvalidInput},
+		{"TooLong", &models.Comment{ArticleID: article.ID, AuthorID: author.ID, Body: strings.Repeat("x", repository.MaxCommentLength+1)}, repository.ErrInvalidInput},
+		{"RejectedStatus", &models.Comment{ArticleID: article.ID, AuthorID: author.ID, Body: "x", Status: models.CommentRejected}, repository.ErrInvalidInput},
+		{"MissingArticle", &models.Comment{ArticleID: 999999, AuthorID: author.ID, Body: "x"}, repository.ErrNotFound},
+		{"UnknownAuthor", &models.Comment{ArticleID: article.ID, AuthorID: 999999, Body: "x"}, repository.ErrConflict},
+	}
+	for _, tt := range tests {
+		if err := s.Comments.Create(ctx, tt.comment); !errors.Is(err, tt.want) {
+			t.Fatalf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func testCommentReplyRules(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "A")
+	other := mustCreateArticle(t, s, author.ID, "B")
+	root := mustCreateComment(t, s, article.ID, author.ID, nil, models.CommentApproved)
+
+	reply := mustCreateComment(t, s, article.ID, author.ID, &root.ID, models.CommentPending)
+	if reply.ParentID == nil || *reply.ParentID != root.ID {
+		t.Fatalf("reply.ParentID = %v, want %d", reply.ParentID, root.ID)
+	}
+
+	err := s.Comments.Create(ctx, &models.Comment{ArticleID: other.ID, AuthorID: author.ID, ParentID: &root.ID, Body: "x"})
+	if !errors.Is(err, repository.ErrInvalidInput) {
+		t.Fatalf("reply from another article: err = %v, want ErrInvalidInput", err)
+	}
+	missing := 999999
+	err = s.Comments.Create(ctx, &models.Comment{ArticleID: article.ID, AuthorID: author.ID, ParentID: &missing, Body: "x"})
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("reply to missing parent: err = %v, want ErrNotFound", err)
+	}
+
+	if err := s.Comments.Delete(ctx, root.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	err = s.Comments.Create(ctx, &models.Comment{ArticleID: article.ID, AuthorID: author.ID, ParentID: &root.ID, Body: "x"})
+	if !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("reply to deleted comment: err = %v, want ErrConflict", err)
+	}
+}
+
+func testCommentThreadOrder(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	//  first
+	//    reply1
+	//      nested
+	//    reply2
+	//  second
+	first := mustCreateComment(t, s, article.ID, author.ID, nil, models.CommentApproved)
+	second := mustCreateComment(t, s, article.ID, author.ID, nil, models.CommentApproved)
+	reply1 := mustCreateComment(t, s, article.ID, author.ID, &first.ID, models.CommentApproved)
+	reply2 := mustCreateComment(t, s, article.ID, author.ID, &first.ID, models.CommentApproved)
+	nested := mustCreateComment(t, s, article.ID, author.ID, &reply1.ID, models.CommentApproved)
+
+	thread, err := s.Comments.GetThread(ctx, article.ID, false)
+	if err != nil {
+		t.Fatalf("GetThread: %v", err)
+	}
+	if got, want := commentIDs(thread), []int{first.ID, reply1.ID, nested.ID, reply2.ID, second.ID}; !slices.Equal(got, want) {
+		t.Fatalf("thread order = %v, want %v", got, want)
+	}
+	var depths []int
+	for _, c := range thread {
+		depths = append(depths, c.Depth)
+	}
+	if want := []int{0, 1, 2, 1, 0}; !slices.Equal(depths, want) {
+		t.Fatalf("depths = %v, want %v", depths, want)
+	}
+
+	empty, err := s.Comments.GetThread(ctx, 999999, false)
+	if err != nil {
+		t.Fatalf("GetThread unknown article: %v", err)
+	}
+	if len(empty) != 0 {
+		t.Fatalf("GetThread unknown article returned %d comments", len(empty))
+	}
+}
+
+func testCommentThreadVisibility(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	approved := mustCreateComment(t, s, article.ID, author.ID, nil, models.CommentApproved)
+	pending := mustCreateComment(t, s, article.ID, author.ID, nil, models.CommentPending)
+	// Ответ на комментарий, который затем скрывается модератором
+	hiddenParent := mustCreateComment(t, s, article.ID, author.ID, nil, models.CommentApproved)
+	orphan := mustCreateComment(t, s, article.ID, author.ID, &hiddenParent.ID, models.CommentApproved)
+	if _, err := s.Comments.Moderate(ctx, hiddenParent.ID, models.CommentRejected); err != nil {
+		t.Fatalf("Moderate: %v", err)
+	}
+	// Удаленный комментарий остается в ветке заглушкой, ответы на него видны
+	deleted := mustCreateComment(t, s, article.ID, author.ID, nil, models.CommentApproved)
+	survivor := mustCreateComment(t, s, article.ID, author.ID, &deleted.ID, models.CommentApproved)
+	if err := s.Comments.Delete(ctx, deleted.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	public, err := s.Comments.GetThread(ctx, article.ID, false)
+	if err != nil {
+		t.Fatalf("GetThread: %v", err)
+	}
+	if got, want := commentIDs(public), []int{approved.ID, deleted.ID, survivor.ID}; !slices.Equal(got, want) {
+		t.Fatalf("public thread = %v, want %v (pending %d, orphan %d hidden)", got, want, pending.ID, orphan.ID)
+	}
+	if public[1].Body != repository.DeletedCommentBody {
+		t.Fatalf("deleted comment body = %q, want placeholder", public[1].Body)
+	}
+
+	all, err := s.Comments.GetThread(ctx, article.ID, true)
+	if err != nil {
+		t.Fatalf("GetThread includeHidden: %v", err)
+	}
+	if len(all) != 6 {
+		t.Fatalf("moderator thread has %d comments, want 6", len(all))
+	}
+	for _, c := range all {
+		if c.ID == deleted.ID && c.Body == repository.DeletedCommentBody {
+			t.Fatal("moderator view must keep the original body of deleted comments")
+		}
+	}
+}
+
+func testCommentModeration(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+	comment := mustCreateComment(t, s, article.ID, author.ID, nil, models.CommentPending)
+
+	steps := []struct {
+		to   models.CommentStatus
+		want error
+	}{
+		{models.CommentApproved, nil},
+		{models.CommentApproved, repository.ErrConflict},
+		{models.CommentPending, repository.ErrConflict},
+		{models.CommentRejected, nil},
+		{models.CommentApproved, nil},
+		{"spam", repository.ErrInvalidInput},
+		{models.CommentDeleted, nil},
+		{models.CommentApproved, repository.ErrConflict},
+	}
+	for i, step := range steps {
+		got, err := s.Comments.Moderate(ctx, comment.ID, step.to)
+		if !errors.Is(err, step.want) {
+			t.Fatalf("step %d: Moderate(%s): err = %v, want %v", i, step.to, err, step.want)
+		}
+		if err == nil && got.Status != step.to {
+			t.Fatalf("step %d: status = %s, want %s", i, got.Status, step.to)
+		}
+	}
+
+	if err := s.Comments.Delete(ctx, comment.ID); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("second Delete: err = %v, want ErrConflict", err)
+	}
+	if _, err := s.Comments.Moderate(ctx, 999999, models.CommentApproved); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Moderate missing: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testCommentModerationQueue(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	first := mustCreateComment(t, s, article.ID, author.ID, nil, models.CommentPending)
+	mustCreateComment(t, s, article.ID, author.ID, nil, models.CommentApproved)
+	second := mustCreateComment(t, s, article.ID, author.ID, nil, models.CommentPending)
+
+	queue, err := s.Comments.ListByStatus(ctx, models.CommentPending, 0)
+	if err != nil {
+		t.Fatalf("ListByStatus: %v", err)
+	}
+	if got, want := commentIDs(queue), []int{first.ID, second.ID}; !slices.Equal(got, want) {
+		t.Fatalf("queue = %v, want oldest first %v", got, want)
+	}
+
+	queue, err = s.Comments.ListByStatus(ctx, models.CommentPending, 1)
+	if err != nil {
+		t.Fatalf("ListByStatus limit: %v", err)
+	}
+	if got := commentIDs(queue); !slices.Equal(got, []int{first.ID}) {
+		t.Fatalf("queue with limit 1 = %v", got)
+	}
+}
+
+func testCommentCountByArticles(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	a := mustCreateArticle(t, s, author.ID, "A")
+	b := mustCreateArticle(t, s, author.ID, "B")
+	c := mustCreateArticle(t, s, author.ID, "C")
+
+	mustCreateComment(t, s, a.ID, author.ID, nil, models.CommentApproved)
+	mustCreateComment(t, s, a.ID, author.ID, nil, models.CommentApproved)
+	mustCreateComment(t, s, a.ID, author.ID, nil, models.CommentPending)
+	mustCreateComment(t, s, b.ID, author.ID, nil, models.CommentApproved)
+	mustCreateComment(t, s, c.ID, author.ID, nil, models.CommentApproved)
+
+	counts, err := s.Comments.CountByArticles(ctx, []int{a.ID, b.ID, 999999})
+	if err != nil {
+		t.Fatalf("CountByArticles: %v", err)
+	}
+	if counts[a.ID] != 2 || counts[b.ID] != 1 || counts[999999] != 0 {
+		t.Fatalf("counts = %v", counts)
+	}
+	if _, ok := counts[c.ID]; ok {
+		t.Fatalf("counts include article %d that was not requested", c.ID)
+	}
+
+	counts, err = s.Comments.CountByArticles(ctx, nil)
+	if err != nil {
+		t.Fatalf("CountByArticles(nil): %v", err)
+	}
+	if len(counts) != 0 {
+		t.Fatalf("CountByArticles(nil) = %v", counts)
+	}
+}
+
+func testCommentCascades(t *testing.T, s Stores) {
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice@example.com")
+	bob := mustCreateUser(t, s, "bob@example.com")
+	article := mustCreateArticle(t, s, alice.ID, "A")
+	other := mustCreateArticle(t, s, alice.ID, "B")
+
+	byBob := mustCreateComment(t, s, other.ID, bob.ID, nil, models.CommentApproved)
+	replyByAlice := mustCreateComment(t, s, other.ID, alice.ID, &byBob.ID, models.CommentApproved)
+	onArticle := mustCreateComment(t, s, article.ID, bob.ID, nil, models.CommentApproved)
+
+	// Окончательное удаление статьи удаляет ее комментарии
+	if err := s.Articles.Delete(ctx, article.ID); err != nil {
+		t.Fatalf("Delete article: %v", err)
+	}
+	mustPurge(t, s)
+	if _, err := s.Comments.GetByID(ctx, onArticle.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("comment survived article deletion: err = %v", err)
+	}
+
+	// Окончательное удаление автора удаляет его комментарии вместе с ответами других пользователей
+	if err := s.Users.Delete(ctx, bob.ID); err != nil {
+		t.Fatalf("Delete user: %v", err)
+	}
+	mustPurge(t, s)
+	for _, id := range []int{byBob.ID, replyByAlice.ID} {
+		if _, err := s.Comments.GetByID(ctx, id); !errors.Is(err, repository.ErrNotFound) {
+			t.Fatalf("comment %d survived author deletion: err = %v", id, err)
+		}
+	}
+}
+
+func mustCreateComment(t *testing.T, s Stores, articleID, authorID int, parentID *int, status models.CommentStatus) *models.Comment {
+	t.Helper()
+	comment := &models.Comment{ArticleID: articleID, AuthorID: authorID, ParentID: parentID, Body: "comment", Status: status}
+	if err := s.Comments.Create(context.Background(), comment); err != nil {
+		t.Fatalf("create comment: %v", err)
+	}
+	return comment
+}
+
+func commentIDs(comments []*models.Comment) []int {
+	ids := make([]int, 0, len(comments))
+	for _, c := range comments {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
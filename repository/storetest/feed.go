@@ -0,0 +1,104 @@
+package storetest
+
+import (
+	"context"
+	"go-articles-app/repository"
+	"slices"
+	"testing"
+)
+
+func runFeedTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"Filters", testFeedFilters},
+		{"Limit", testFeedLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+func feedIDs(items []*repository.ArticleWithAuthor) []int {
+	ids := make([]int, 0, len(items))
+	for _, it := range items {
+		ids = append(ids, it.Article.ID)
+	}
+	return ids
+}
+
+func testFeedFilters(t *testing.T, s Stores) {
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice@example.com")
+	bob := mustCreateUser(t, s, "bob@example.com")
+
+	// Порядок публикации отличается от порядка создания
+	older := mustCreateArticle(t, s, alice.ID, "Older")
+	newer := mustCreateArticle(t, s, bob.ID, "Newer")
+	draft := mustCreateArticle(t, s, alice.ID, "Draft")
+	trashed := mustCreateArticle(t, s, alice.ID, "Trashed")
+	for _, id := range []int{newer.ID, older.ID, trashed.ID} {
+		if err := s.Articles.Publish(ctx, id); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	if err := s.Articles.Delete(ctx, trashed.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	mustSetTags(t, s, newer.ID, "go")
+	mustSetTags(t, s, draft.ID, "go")
+	tag, err := s.Tags.GetByName(ctx, "go")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query repository.FeedQuery
+		want  []int
+	}{
+		{"Site", repository.FeedQuery{}, []int{older.ID, newer.ID}},
+		{"Author", repository.FeedQuery{AuthorID: alice.ID}, []int{older.ID}},
+		{"Tag", repository.FeedQuery{TagID: tag.ID}, []int{newer.ID}},
+		{"AuthorAndTag", repository.FeedQuery{AuthorID: alice.ID, TagID: tag.ID}, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := s.Articles.ListFeed(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("ListFeed: %v", err)
+			}
+			if ids := feedIDs(items); !slices.Equal(ids, tt.want) {
+				t.Fatalf("ListFeed IDs = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	items, err := s.Articles.ListFeed(ctx, repository.FeedQuery{AuthorID: bob.ID})
+	if err != nil {
+		t.Fatalf("ListFeed: %v", err)
+	}
+	if len(items) != 1 || items[0].AuthorName != bob.Name || items[0].AuthorEmail != bob.Email || items[0].Article.PublishedAt == nil {
+		t.Fatalf("ListFeed item = %+v", items[0])
+	}
+}
+
+func testFeedLimit(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	for _, title := range []string{"One", "Two", "Three"} {
+		a := mustCreateArticle(t, s, author.ID, title)
+		if err := s.Articles.Publish(ctx, a.ID); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	items, err := s.Articles.ListFeed(ctx, repository.FeedQuery{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListFeed: %v", err)
+	}
+	if len(items) != 2 || items[0].Article.Title != "Three" {
+		t.Fatalf("ListFeed(limit 2) = %d items, first %q", len(items), items[0].Article.Title)
+	}
+}
@@ -0,0 +1,180 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"slices"
+	"testing"
+)
+
+func runFindTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"Filters", testFindFilters},
+		{"CreatedRange", testFindCreatedRange},
+		{"SortAndWindow", testFindSortAndWindow},
+		{"InvalidQuery", testFindInvalidQuery},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+type findFixture struct {
+	alice, bob, carol   *models.User
+	goIntro, goAdvanced *models.Article
+	percent, draft      *models.Article
+	carolPost           *models.Article
+}
+
+// newFindFixture создает статьи трех авторов с разными просмотрами и статусами
+func newFindFixture(t *testing.T, s Stores) *findFixture {
+	t.Helper()
+	ctx := context.Background()
+	f := &findFixture{
+		alice: mustCreateUser(t, s, "alice@example.com"),
+		bob:   mustCreateUser(t, s, "bob@example.com"),
+		carol: mustCreateUser(t, s, "carol@example.com"),
+	}
+	f.goIntro = mustCreateArticle(t, s, f.alice.ID, "Introduction to Go")
+	f.goAdvanced = mustCreateArticle(t, s, f.bob.ID, "Advanced GO patterns")
+	f.percent = mustCreateArticle(t, s, f.bob.ID, "100% coverage")
+	f.draft = mustCreateArticle(t, s, f.alice.ID, "Draft_notes")
+	f.carolPost = mustCreateArticle(t, s, f.carol.ID, "Carol writes")
+
+	views := map[int]int{f.goIntro.ID: 5, f.goAdvanced.ID: 10, f.percent.ID: 1, f.carolPost.ID: 10}
+	for id, n := range views {
+		for i := 0; i < n; i++ {
+			if err := s.Articles.IncrementViews(ctx, id); err != nil {
+				t.Fatalf("IncrementViews: %v", err)
+			}
+		}
+	}
+	for _, a := range []*models.Article{f.goIntro, f.goAdvanced, f.percent, f.carolPost} {
+		if err := s.Articles.Publish(ctx, a.ID); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+	return f
+}
+
+func testFindFilters(t *testing.T, s Stores) {
+	f := newFindFixture(t, s)
+	published, draft := true, false
+
+	tests := []struct {
+		name   string
+		filter repository.ArticleFilter
+		want   []int
+	}{
+		{"NoFilter", repository.ArticleFilter{}, []int{f.carolPost.ID, f.draft.ID, f.percent.ID, f.goAdvanced.ID, f.goIntro.ID}},
+		{"AuthorSet", repository.ArticleFilter{AuthorIDs: []int{f.alice.ID, f.carol.ID}}, []int{f.carolPost.ID, f.draft.ID, f.goIntro.ID}},
+		{"Published", repository.ArticleFilter{Published: &published}, []int{f.carolPost.ID, f.percent.ID, f.goAdvanced.ID, f.goIntro.ID}},
+		{"Drafts", repository.ArticleFilter{Published: &draft}, []int{f.draft.ID}},
+		{"StatusSet", repository.ArticleFilter{Statuses: []models.ArticleStatus{models.ArticleDraft, models.ArticleArchived}}, []int{f.draft.ID}},
+		{"MinViews", repository.ArticleFilter{MinViews: 5}, []int{f.carolPost.ID, f.goAdvanced.ID, f.goIntro.ID}},
+		{"TitleCaseInsensitive", repository.ArticleFilter{TitleContains: "go"}, []int{f.goAdvanced.ID, f.goIntro.ID}},
+		{"TitleLiteralPercent", repository.ArticleFilter{TitleContains: "0%"}, []int{f.percent.ID}},
+		{"TitleLiteralUnderscore", repository.ArticleFilter{TitleContains: "t_n"}, []int{f.draft.ID}},
+		{"Combined", repository.ArticleFilter{AuthorIDs: []int{f.bob.ID}, MinViews: 2, TitleContains: "go"}, []int{f.goAdvanced.ID}},
+		{"NoMatch", repository.ArticleFilter{AuthorIDs: []int{999999}}, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Articles.Find(context.Background(), repository.ArticleQuery{Filter: tt.filter})
+			if err != nil {
+				t.Fatalf("Find: %v", err)
+			}
+			if ids := articleIDs(got.Articles); !slices.Equal(ids, tt.want) {
+				t.Fatalf("Find IDs = %v, want %v", ids, tt.want)
+			}
+			if got.Total != len(tt.want) {
+				t.Fatalf("Total = %d, want %d", got.Total, len(tt.want))
+			}
+		})
+	}
+}
+
+func testFindCreatedRange(t *testing.T, s Stores) {
+	f := newFindFixture(t, s)
+
+	// [goAdvanced.CreatedAt, draft.CreatedAt) включает goAdvanced и percent
+	got, err := s.Articles.Find(context.Background(), repository.ArticleQuery{
+		Filter: repository.ArticleFilter{CreatedFrom: f.goAdvanced.CreatedAt, CreatedTo: f.draft.CreatedAt},
+	})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if ids, want := articleIDs(got.Articles), []int{f.percent.ID, f.goAdvanced.ID}; !slices.Equal(ids, want) {
+		t.Fatalf("Find IDs = %v, want %v", ids, want)
+	}
+}
+
+func testFindSortAndWindow(t *testing.T, s Stores) {
+	f := newFindFixture(t, s)
+	ctx := context.Background()
+
+	// views: goAdvanced=10, carolPost=10, goIntro=5, percent=1, draft=0; равные — по id
+	desc := []int{f.carolPost.ID, f.goAdvanced.ID, f.goIntro.ID, f.percent.ID, f.draft.ID}
+	got, err := s.Articles.Find(ctx, repository.ArticleQuery{SortBy: repository.SortByViews})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if ids := articleIDs(got.Articles); !slices.Equal(ids, desc) {
+		t.Fatalf("views DESC IDs = %v, want %v", ids, desc)
+	}
+
+	asc := slices.Clone(desc)
+	slices.Reverse(asc)
+	got, err = s.Articles.Find(ctx, repository.ArticleQuery{SortBy: repository.SortByViews, Ascending: true})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if ids := articleIDs(got.Articles); !slices.Equal(ids, asc) {
+		t.Fatalf("views ASC IDs = %v, want %v", ids, asc)
+	}
+
+	got, err = s.Articles.Find(ctx, repository.ArticleQuery{SortBy: repository.SortByViews, Limit: 2, Offset: 1})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if ids := articleIDs(got.Articles); !slices.Equal(ids, desc[1:3]) || got.Total != len(desc) {
+		t.Fatalf("window IDs = %v total = %d, want %v total = %d", ids, got.Total, desc[1:3], len(desc))
+	}
+
+	got, err = s.Articles.Find(ctx, repository.ArticleQuery{Offset: 100})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if len(got.Articles) != 0 || got.Total != len(desc) {
+		t.Fatalf("past-the-end window = %d articles, total %d", len(got.Articles), got.Total)
+	}
+
+	// updated_at: последней опубликована carolPost, черновик не обновлялся после создания
+	got, err = s.Articles.Find(ctx, repository.ArticleQuery{SortBy: repository.SortByUpdatedAt, Limit: 1})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if ids := articleIDs(got.Articles); !slices.Equal(ids, []int{f.carolPost.ID}) {
+		t.Fatalf("updated_at DESC first = %v, want %d", ids, f.carolPost.ID)
+	}
+}
+
+func testFindInvalidQuery(t *testing.T, s Stores) {
+	ctx := context.Background()
+	queries := []repository.ArticleQuery{
+		{SortBy: "title; DROP TABLE articles"},
+		{Offset: -1},
+		{Filter: repository.ArticleFilter{MinViews: -1}},
+		{Filter: repository.ArticleFilter{Statuses: []models.ArticleStatus{"deleted"}}},
+	}
+	for _, q := range queries {
+		if _, err := s.Articles.Find(ctx, q); !errors.Is(err, repository.ErrInvalidQuery) {
+			t.Fatalf("Find(%+v): err = %v, want ErrInvalidQuery", q, err)
+		}
+	}
+}
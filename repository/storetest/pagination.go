@@ -0,0 +1,157 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"slices"
+	"testing"
+)
+
+func runPaginationTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"PublishedPages", testPublishedPages},
+		{"AuthorPages", testAuthorPages},
+		{"UserPages", testUserPages},
+		{"EmptyPage", testEmptyPage},
+		{"InvalidCursor", testInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+func testPublishedPages(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+
+	var want []int
+	for i := 0; i < 5; i++ {
+		a := mustCreateArticle(t, s, author.ID, fmt.Sprintf("A%d", i))
+		if err := s.Articles.Publish(ctx, a.ID); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+		want = append([]int{a.ID}, want...)
+	}
+	mustCreateArticle(t, s, author.ID, "Draft")
+
+	got := collectArticlePages(t, []int{2, 2, 1}, func(p repository.PageRequest) (*repository.Page[*models.Article], error) {
+		return s.Articles.GetPublishedPage(ctx, p)
+	})
+	if !slices.Equal(got, want) {
+		t.Fatalf("pages IDs = %v, want %v", got, want)
+	}
+}
+
+func testAuthorPages(t *testing.T, s Stores) {
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice@example.com")
+	bob := mustCreateUser(t, s, "bob@example.com")
+
+	var all, published []int
+	for i := 0; i < 5; i++ {
+		a := mustCreateArticle(t, s, alice.ID, fmt.Sprintf("A%d", i))
+		all = append([]int{a.ID}, all...)
+		if i%2 == 0 {
+			if err := s.Articles.Publish(ctx, a.ID); err != nil {
+				t.Fatalf("Publish: %v", err)
+			}
+			published = append([]int{a.ID}, published...)
+		}
+		mustCreateArticle(t, s, bob.ID, fmt.Sprintf("B%d", i))
+	}
+
+	got := collectArticlePages(t, []int{2, 2, 1}, func(p repository.PageRequest) (*repository.Page[*models.Article], error) {
+		return s.Articles.GetByAuthorIDPage(ctx, alice.ID, true, p)
+	})
+	if !slices.Equal(got, all) {
+		t.Fatalf("pages IDs = %v, want %v", got, all)
+	}
+
+	// Черновики не занимают места на странице: страницы полные, has_more
+	// выставлен только у первой
+	got = collectArticlePages(t, []int{2, 1}, func(p repository.PageRequest) (*repository.Page[*models.Article], error) {
+		return s.Articles.GetByAuthorIDPage(ctx, alice.ID, false, p)
+	})
+	if !slices.Equal(got, published) {
+		t.Fatalf("published pages IDs = %v, want %v", got, published)
+	}
+}
+
+func testUserPages(t *testing.T, s Stores) {
+	ctx := context.Background()
+	var want []int
+	for i := 0; i < 5; i++ {
+		want = append(want, mustCreateUser(t, s, fmt.Sprintf("user%d@example.com", i)).ID)
+	}
+
+	var got []int
+	page := repository.PageRequest{Limit: 2}
+	for _, size := range []int{2, 2, 1} {
+		p, err := s.Users.GetAllPage(ctx, page)
+		if err != nil {
+			t.Fatalf("GetAllPage: %v", err)
+		}
+		if len(p.Items) != size {
+			t.Fatalf("page size = %d, want %d", len(p.Items), size)
+		}
+		got = append(got, userIDs(p.Items)...)
+		page.Cursor = p.NextCursor
+	}
+	if page.Cursor != "" {
+		t.Fatalf("last page has next cursor %q", page.Cursor)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("pages IDs = %v, want %v", got, want)
+	}
+}
+
+func testEmptyPage(t *testing.T, s Stores) {
+	p, err := s.Articles.GetPublishedPage(context.Background(), repository.PageRequest{})
+	if err != nil {
+		t.Fatalf("GetPublishedPage: %v", err)
+	}
+	if p.Items == nil || len(p.Items) != 0 || p.HasMore || p.NextCursor != "" {
+		t.Fatalf("empty page = %+v", p)
+	}
+}
+
+func testInvalidCursor(t *testing.T, s Stores) {
+	ctx := context.Background()
+	page := repository.PageRequest{Cursor: "not a cursor"}
+	if _, err := s.Articles.GetPublishedPage(ctx, page); !errors.Is(err, repository.ErrInvalidCursor) {
+		t.Fatalf("GetPublishedPage: err = %v, want ErrInvalidCursor", err)
+	}
+	if _, err := s.Users.GetAllPage(ctx, page); !errors.Is(err, repository.ErrInvalidCursor) {
+		t.Fatalf("GetAllPage: err = %v, want ErrInvalidCursor", err)
+	}
+}
+
+// collectArticlePages проходит по страницам размера sizes[0] и проверяет
+// размер каждой страницы и флаг has_more
+func collectArticlePages(t *testing.T, sizes []int, fetch func(repository.PageRequest) (*repository.Page[*models.Article], error)) []int {
+	t.Helper()
+	var ids []int
+	page := repository.PageRequest{Limit: sizes[0]}
+	for i, size := range sizes {
+		p, err := fetch(page)
+		if err != nil {
+			t.Fatalf("page %d: %v", i, err)
+		}
+		if len(p.Items) != size {
+			t.Fatalf("page %d size = %d, want %d", i, len(p.Items), size)
+		}
+		last := i == len(sizes)-1
+		if p.HasMore == last || (p.NextCursor == "") != last {
+			t.Fatalf("page %d: has_more=%v next_cursor=%q", i, p.HasMore, p.NextCursor)
+		}
+		ids = append(ids, articleIDs(p.Items)...)
+		page.Cursor = p.NextCursor
+	}
+	return ids
+}
@@ -0,0 +1,120 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"testing"
+)
+
+func runPasswordTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"CreateWithPassword", testPasswordCreate},
+		{"CreateDuplicateEmail", testPasswordCreateDuplicate},
+		{"UserWithoutPassword", testPasswordNotSet},
+		{"GetCredentialsMissing", testPasswordMissingUser},
+		{"SetPasswordHash", testPasswordSet},
+		{"TrashedUser", testPasswordTrashedUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+func mustGetCredentials(t *testing.T, s Stores, email string) (*models.User, string) {
+	t.Helper()
+	user, hash, err := s.Users.GetCredentials(context.Background(), email)
+	if err != nil {
+		t.Fatalf("GetCredentials(%q): %v", email, err)
+	}
+	return user, hash
+}
+
+func testPasswordCreate(t *testing.T, s Stores) {
+	user := &models.User{Email: "alice@example.com", Name: "Alice"}
+	if err := s.Users.CreateWithPassword(context.Background(), user, "$argon2id$hash"); err != nil {
+		t.Fatalf("CreateWithPassword: %v", err)
+	}
+	if user.ID == 0 || user.CreatedAt.IsZero() {
+		t.Fatalf("CreateWithPassword did not fill ID and timestamps: %+v", user)
+	}
+
+	got, hash := mustGetCredentials(t, s, "alice@example.com")
+	if got.ID != user.ID || got.Name != "Alice" {
+		t.Fatalf("GetCredentials user = %+v, want %+v", got, user)
+	}
+	if hash != "$argon2id$hash" {
+		t.Fatalf("hash = %q, want $argon2id$hash", hash)
+	}
+}
+
+func testPasswordCreateDuplicate(t *testing.T, s Stores) {
+	mustCreateUser(t, s, "alice@example.com")
+	err := s.Users.CreateWithPassword(context.Background(), &models.User{Email: "alice@example.com", Name: "Alice"}, "hash")
+	if !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("CreateWithPassword duplicate: err = %v, want ErrAlreadyExists", err)
+	}
+}
+
+func testPasswordNotSet(t *testing.T, s Stores) {
+	user := mustCreateUser(t, s, "alice@example.com")
+	got, hash := mustGetCredentials(t, s, "alice@example.com")
+	if got.ID != user.ID || hash != "" {
+		t.Fatalf("GetCredentials = user %d, hash %q, want user %d without hash", got.ID, hash, user.ID)
+	}
+}
+
+func testPasswordMissingUser(t *testing.T, s Stores) {
+	_, _, err := s.Users.GetCredentials(context.Background(), "nobody@example.com")
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetCredentials: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Users.SetPasswordHash(context.Background(), 999, "hash"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("SetPasswordHash: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testPasswordSet(t *testing.T, s Stores) {
+	ctx := context.Background()
+	user := mustCreateUser(t, s, "alice@example.com")
+
+	if err := s.Users.SetPasswordHash(ctx, user.ID, "first"); err != nil {
+		t.Fatalf("SetPasswordHash: %v", err)
+	}
+	if err := s.Users.SetPasswordHash(ctx, user.ID, "second"); err != nil {
+		t.Fatalf("SetPasswordHash: %v", err)
+	}
+	if _, hash := mustGetCredentials(t, s, "alice@example.com"); hash != "second" {
+		t.Fatalf("hash = %q, want second", hash)
+	}
+}
+
+func testPasswordTrashedUser(t *testing.T, s Stores) {
+	ctx := context.Background()
+	user := &models.User{Email: "alice@example.com", Name: "Alice"}
+	if err := s.Users.CreateWithPassword(ctx, user, "hash"); err != nil {
+		t.Fatalf("CreateWithPassword: %v", err)
+	}
+	if err := s.Users.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, _, err := s.Users.GetCredentials(ctx, "alice@example.com"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetCredentials of trashed user: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Users.SetPasswordHash(ctx, user.ID, "other"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("SetPasswordHash of trashed user: err = %v, want ErrNotFound", err)
+	}
+
+	// Восстановленный пользователь входит со старым паролем
+	if err := s.Users.Restore(ctx, user.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if _, hash := mustGetCredentials(t, s, "alice@example.com"); hash != "hash" {
+		t.Fatalf("hash after restore = %q, want hash", hash)
+	}
+}
@@ -0,0 +1,106 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"strings"
+	"testing"
+)
+
+func runRenderTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"Rendered", testRendered},
+		{"UpdateInvalidatesCache", testRenderedUpdateInvalidates},
+		{"RestoreRevisionInvalidatesCache", testRenderedRestoreInvalidates},
+		{"TrashedArticle", testRenderedTrashed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+func mustCreateMarkdownArticle(t *testing.T, s Stores, content string) *models.Article {
+	t.Helper()
+	author := mustCreateUser(t, s, "alice@example.com")
+	a := &models.Article{Title: "Markdown", Content: content, AuthorID: author.ID}
+	if err := s.Articles.Create(context.Background(), a); err != nil {
+		t.Fatalf("Create article: %v", err)
+	}
+	return a
+}
+
+// mustRenderContaining проверяет, что HTML статьи содержит want
+func mustRenderContaining(t *testing.T, s Stores, id int, want string) {
+	t.Helper()
+	doc, err := s.Articles.GetRendered(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetRendered: %v", err)
+	}
+	if !strings.Contains(doc.HTML, want) {
+		t.Fatalf("GetRendered HTML = %q, want to contain %q", doc.HTML, want)
+	}
+}
+
+func testRendered(t *testing.T, s Stores) {
+	a := mustCreateMarkdownArticle(t, s, "## Введение\n\nТекст <script>x</script>\n")
+
+	for i := 0; i < 2; i++ { // второй вызов читает кэш
+		doc, err := s.Articles.GetRendered(context.Background(), a.ID)
+		if err != nil {
+			t.Fatalf("GetRendered: %v", err)
+		}
+		if !strings.Contains(doc.HTML, `<h2 id="vvedenie">Введение</h2>`) || strings.Contains(doc.HTML, "<script") {
+			t.Fatalf("GetRendered HTML = %q", doc.HTML)
+		}
+		if len(doc.TOC) != 1 || doc.TOC[0].ID != "vvedenie" || doc.TOC[0].Level != 2 {
+			t.Fatalf("GetRendered TOC = %+v", doc.TOC)
+		}
+	}
+}
+
+func testRenderedUpdateInvalidates(t *testing.T, s Stores) {
+	a := mustCreateMarkdownArticle(t, s, "**old**")
+	mustRenderContaining(t, s, a.ID, "<strong>old</strong>")
+
+	a.Title = "Renamed"
+	if err := s.Articles.Update(context.Background(), a); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	mustRenderContaining(t, s, a.ID, "<strong>old</strong>")
+
+	a.Content = "*new*"
+	if err := s.Articles.Update(context.Background(), a); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	mustRenderContaining(t, s, a.ID, "<em>new</em>")
+}
+
+func testRenderedRestoreInvalidates(t *testing.T, s Stores) {
+	a := mustCreateMarkdownArticle(t, s, "**first**")
+	a.Content = "*second*"
+	if err := s.Articles.Update(context.Background(), a); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	mustRenderContaining(t, s, a.ID, "<em>second</em>")
+
+	if _, err := s.Articles.RestoreRevision(context.Background(), a.ID, 1); err != nil {
+		t.Fatalf("RestoreRevision: %v", err)
+	}
+	mustRenderContaining(t, s, a.ID, "<strong>first</strong>")
+}
+
+func testRenderedTrashed(t *testing.T, s Stores) {
+	ctx := context.Background()
+	a := mustCreateMarkdownArticle(t, s, "text")
+	if err := s.Articles.Delete(ctx, a.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Articles.GetRendered(ctx, a.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetRendered of a trashed article: err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,265 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/diff"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"slices"
+	"testing"
+)
+
+func runRevisionTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"CreateWritesFirstRevision", testRevisionOnCreate},
+		{"UpdateWritesRevision", testRevisionOnUpdate},
+		{"UnknownEditorRollsBack", testRevisionUnknownEditor},
+		{"GetMissing", testRevisionGetMissing},
+		{"Diff", testRevisionDiff},
+		{"Restore", testRevisionRestore},
+		{"Cascades", testRevisionCascades},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+func testRevisionOnCreate(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "First")
+
+	revisions, err := s.Articles.ListRevisions(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions: %v", err)
+	}
+	if len(revisions) != 1 {
+		t.Fatalf("got %d revisions after Create, want 1", len(revisions))
+	}
+	rev := revisions[0]
+	if rev.Revision != 1 || rev.Title != "First" || rev.Content != article.Content {
+		t.Fatalf("first revision = %+v", rev)
+	}
+	if rev.EditorID == nil || *rev.EditorID != author.ID {
+		t.Fatalf("first revision editor = %v, want author %d", rev.EditorID, author.ID)
+	}
+
+	_, withAuthor, err := s.Articles.CreateArticleWithAuthor(ctx, "Bob", "bob@example.com", "Second", "Body")
+	if err != nil {
+		t.Fatalf("CreateArticleWithAuthor: %v", err)
+	}
+	revisions, err = s.Articles.ListRevisions(ctx, withAuthor.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions: %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Title != "Second" {
+		t.Fatalf("CreateArticleWithAuthor revisions = %+v", revisions)
+	}
+}
+
+func testRevisionOnUpdate(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	editor := mustCreateUser(t, s, "bob@example.com")
+	article := mustCreateArticle(t, s, author.ID, "v1")
+
+	ctx := repository.WithActor(context.Background(), editor.ID)
+	article.Title = "v2"
+	article.Content = "second"
+	if err := s.Articles.Update(ctx, article); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	// Правка без пользователя в контексте сохраняется с неизвестным автором
+	article.Title = "v3"
+	if err := s.Articles.Update(context.Background(), article); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	revisions, err := s.Articles.ListRevisions(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions: %v", err)
+	}
+	if got, want := revisionNumbers(revisions), []int{3, 2, 1}; !slices.Equal(got, want) {
+		t.Fatalf("revisions = %v, want newest first %v", got, want)
+	}
+	if revisions[1].Title != "v2" || revisions[1].Content != "second" {
+		t.Fatalf("revision 2 = %+v", revisions[1])
+	}
+	if revisions[1].EditorID == nil || *revisions[1].EditorID != editor.ID {
+		t.Fatalf("revision 2 editor = %v, want %d", revisions[1].EditorID, editor.ID)
+	}
+	if revisions[0].EditorID != nil {
+		t.Fatalf("revision 3 editor = %d, want nil", *revisions[0].EditorID)
+	}
+
+	rev, err := s.Articles.GetRevision(ctx, article.ID, 2)
+	if err != nil {
+		t.Fatalf("GetRevision: %v", err)
+	}
+	if rev.Title != "v2" {
+		t.Fatalf("GetRevision(2) = %+v", rev)
+	}
+}
+
+func testRevisionUnknownEditor(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "v1")
+
+	ctx := repository.WithActor(context.Background(), 999999)
+	changed := *article
+	changed.Title = "v2"
+	if err := s.Articles.Update(ctx, &changed); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("Update with unknown editor: err = %v, want ErrConflict", err)
+	}
+
+	// Правка статьи откатывается вместе с ревизией
+	got, err := s.Articles.GetByID(context.Background(), article.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Title != "v1" {
+		t.Fatalf("title = %q after failed update, want v1", got.Title)
+	}
+	revisions, err := s.Articles.ListRevisions(context.Background(), article.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions: %v", err)
+	}
+	if len(revisions) != 1 {
+		t.Fatalf("got %d revisions after failed update, want 1", len(revisions))
+	}
+}
+
+func testRevisionGetMissing(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	if _, err := s.Articles.GetRevision(ctx, article.ID, 2); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetRevision missing: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Articles.ListRevisions(ctx, 999999); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("ListRevisions missing article: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Articles.DiffRevisions(ctx, article.ID, 1, 5); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("DiffRevisions missing: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Articles.RestoreRevision(ctx, article.ID, 5); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("RestoreRevision missing: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testRevisionDiff(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := &models.Article{Title: "Title", Content: "one\ntwo\nthree", AuthorID: author.ID}
+	if err := s.Articles.Create(ctx, article); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	article.Content = "one\n2\nthree\nfour"
+	if err := s.Articles.Update(ctx, article); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	d, err := s.Articles.DiffRevisions(ctx, article.ID, 1, 2)
+	if err != nil {
+		t.Fatalf("DiffRevisions: %v", err)
+	}
+	if d.FromRevision != 1 || d.ToRevision != 2 || d.ArticleID != article.ID {
+		t.Fatalf("diff header = %+v", d)
+	}
+	if diff.Changed(d.Title) {
+		t.Fatalf("title diff reports changes:\n%s", diff.Format(d.Title))
+	}
+	if got, want := diff.Format(d.Content), " one\n-two\n+2\n three\n+four\n"; got != want {
+		t.Fatalf("content diff:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func testRevisionRestore(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "original")
+	original := article.Content
+	if err := s.Articles.Publish(ctx, article.ID); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	article.Title = "broken"
+	article.Content = "oops"
+	if err := s.Articles.Update(ctx, article); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	restored, err := s.Articles.RestoreRevision(repository.WithActor(ctx, author.ID), article.ID, 1)
+	if err != nil {
+		t.Fatalf("RestoreRevision: %v", err)
+	}
+	if restored.Title != "original" || restored.Content != original || !restored.IsPublished() || restored.AuthorID != author.ID {
+		t.Fatalf("restored article = %+v", restored)
+	}
+
+	got, err := s.Articles.GetByID(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Title != "original" {
+		t.Fatalf("stored title = %q after restore", got.Title)
+	}
+
+	// Восстановление — новая ревизия, история не переписывается
+	revisions, err := s.Articles.ListRevisions(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions: %v", err)
+	}
+	if got, want := revisionNumbers(revisions), []int{3, 2, 1}; !slices.Equal(got, want) {
+		t.Fatalf("revisions = %v, want %v", got, want)
+	}
+	if revisions[0].Title != "original" || revisions[1].Title != "broken" {
+		t.Fatalf("history after restore = %q, %q", revisions[0].Title, revisions[1].Title)
+	}
+	if revisions[0].EditorID == nil || *revisions[0].EditorID != author.ID {
+		t.Fatalf("restore editor = %v, want %d", revisions[0].EditorID, author.ID)
+	}
+}
+
+func testRevisionCascades(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	editor := mustCreateUser(t, s, "bob@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	article.Title = "edited"
+	if err := s.Articles.Update(repository.WithActor(ctx, editor.ID), article); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	// Окончательное удаление редактора сохраняет ревизию, но обнуляет ссылку на него
+	if err := s.Users.Delete(ctx, editor.ID); err != nil {
+		t.Fatalf("Delete user: %v", err)
+	}
+	mustPurge(t, s)
+	rev, err := s.Articles.GetRevision(ctx, article.ID, 2)
+	if err != nil {
+		t.Fatalf("GetRevision: %v", err)
+	}
+	if rev.EditorID != nil {
+		t.Fatalf("editor_id = %d after editor deletion, want nil", *rev.EditorID)
+	}
+
+	if err := s.Articles.Delete(ctx, article.ID); err != nil {
+		t.Fatalf("Delete article: %v", err)
+	}
+	if _, err := s.Articles.GetRevision(ctx, article.ID, 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("revision survived article deletion: err = %v", err)
+	}
+}
+
+func revisionNumbers(revisions []*models.ArticleRevision) []int {
+	numbers := make([]int, 0, len(revisions))
+	for _, rev := range revisions {
+		numbers = append(numbers, rev.Revision)
+	}
+	return numbers
+}
@@ -0,0 +1,114 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"testing"
+)
+
+func runRoleTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"DefaultRole", testRoleDefault},
+		{"CreateWithRole", testRoleCreate},
+		{"UnknownRole", testRoleUnknown},
+		{"SetRole", testRoleSet},
+		{"UpdateKeepsRole", testRoleUpdateKeeps},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+func mustRole(t *testing.T, s Stores, id int, want models.Role) {
+	t.Helper()
+	got, err := s.Users.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Role != want {
+		t.Fatalf("role of user %d = %q, want %q", id, got.Role, want)
+	}
+}
+
+func testRoleDefault(t *testing.T, s Stores) {
+	user := mustCreateUser(t, s, "alice@example.com")
+	if user.Role != models.DefaultRole {
+		t.Fatalf("Create set role %q, want %q", user.Role, models.DefaultRole)
+	}
+	mustRole(t, s, user.ID, models.DefaultRole)
+
+	author, _, err := s.Articles.CreateArticleWithAuthor(context.Background(), "Bob", "bob@example.com", "Title", "Content")
+	if err != nil {
+		t.Fatalf("CreateArticleWithAuthor: %v", err)
+	}
+	if author.Role != models.DefaultRole {
+		t.Fatalf("CreateArticleWithAuthor returned role %q, want %q", author.Role, models.DefaultRole)
+	}
+}
+
+func testRoleCreate(t *testing.T, s Stores) {
+	ctx := context.Background()
+	editor := &models.User{Email: "editor@example.com", Name: "Editor", Role: models.RoleEditor}
+	if err := s.Users.Create(ctx, editor); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	mustRole(t, s, editor.ID, models.RoleEditor)
+
+	reader := &models.User{Email: "reader@example.com", Name: "Reader", Role: models.RoleReader}
+	if err := s.Users.CreateWithPassword(ctx, reader, "hash"); err != nil {
+		t.Fatalf("CreateWithPassword: %v", err)
+	}
+	got, _, err := s.Users.GetCredentials(ctx, "reader@example.com")
+	if err != nil {
+		t.Fatalf("GetCredentials: %v", err)
+	}
+	if got.Role != models.RoleReader {
+		t.Fatalf("GetCredentials role = %q, want reader", got.Role)
+	}
+}
+
+func testRoleUnknown(t *testing.T, s Stores) {
+	ctx := context.Background()
+	err := s.Users.Create(ctx, &models.User{Email: "alice@example.com", Name: "Alice", Role: "owner"})
+	if !errors.Is(err, repository.ErrInvalidInput) {
+		t.Fatalf("Create with unknown role: err = %v, want ErrInvalidInput", err)
+	}
+	user := mustCreateUser(t, s, "bob@example.com")
+	if err := s.Users.SetRole(ctx, user.ID, "owner"); !errors.Is(err, repository.ErrInvalidInput) {
+		t.Fatalf("SetRole unknown: err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func testRoleSet(t *testing.T, s Stores) {
+	ctx := context.Background()
+	user := mustCreateUser(t, s, "alice@example.com")
+	if err := s.Users.SetRole(ctx, user.ID, models.RoleAdmin); err != nil {
+		t.Fatalf("SetRole: %v", err)
+	}
+	mustRole(t, s, user.ID, models.RoleAdmin)
+
+	if err := s.Users.SetRole(ctx, 999, models.RoleAdmin); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("SetRole missing: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Users.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Users.SetRole(ctx, user.ID, models.RoleReader); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("SetRole trashed: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testRoleUpdateKeeps(t *testing.T, s Stores) {
+	user := mustCreateUser(t, s, "alice@example.com")
+	user.Name = "Alice Cooper"
+	user.Role = models.RoleAdmin
+	if err := s.Users.Update(context.Background(), user); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	mustRole(t, s, user.ID, models.DefaultRole)
+}
@@ -0,0 +1,266 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"testing"
+	"time"
+)
+
+func runScheduleTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"PublishWhenDue", testSchedulePublishWhenDue},
+		{"UnpublishWhenDue", testScheduleUnpublishWhenDue},
+		{"PublishAndUnpublishInOneRun", testScheduleBothDue},
+		{"Batches", testScheduleBatches},
+		{"Validation", testScheduleValidation},
+		{"Cancel", testScheduleCancel},
+		{"ManualTransitionClearsSchedule", testScheduleManualTransition},
+		{"UpdateKeepsSchedule", testScheduleUpdateKeeps},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+// scheduleAt возвращает время относительно текущего с точностью PostgreSQL
+func scheduleAt(d time.Duration) time.Time {
+	return time.Now().Add(d).Truncate(time.Microsecond)
+}
+
+func mustSchedule(t *testing.T, s Stores, id int, action repository.ScheduleAction, at time.Time) *models.Article {
+	t.Helper()
+	article, err := s.Articles.Schedule(context.Background(), id, action, at)
+	if err != nil {
+		t.Fatalf("Schedule(%d, %s): %v", id, action, err)
+	}
+	return article
+}
+
+func mustProcessSchedules(t *testing.T, s Stores, limit int) repository.ScheduleRun {
+	t.Helper()
+	run, err := s.Articles.ProcessDueSchedules(context.Background(), limit)
+	if err != nil {
+		t.Fatalf("ProcessDueSchedules: %v", err)
+	}
+	return run
+}
+
+func testSchedulePublishWhenDue(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	due := mustCreateArticle(t, s, author.ID, "due")
+	later := mustCreateArticle(t, s, author.ID, "later")
+
+	at := scheduleAt(-time.Minute)
+	scheduled := mustSchedule(t, s, due.ID, repository.SchedulePublish, at)
+	if scheduled.PublishAt == nil || !scheduled.PublishAt.Equal(at) || scheduled.Status != models.ArticleDraft {
+		t.Fatalf("scheduled article = %+v", scheduled)
+	}
+	mustSchedule(t, s, later.ID, repository.SchedulePublish, scheduleAt(time.Hour))
+
+	run := mustProcessSchedules(t, s, 10)
+	if run != (repository.ScheduleRun{Published: 1}) {
+		t.Fatalf("run = %+v, want one publication", run)
+	}
+
+	got, err := s.Articles.GetByID(ctx, due.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !got.IsPublished() || got.PublishedAt == nil || got.PublishAt != nil {
+		t.Fatalf("due article after run = %+v", got)
+	}
+	transitions, err := s.Articles.ListTransitions(ctx, due.ID)
+	if err != nil {
+		t.Fatalf("ListTransitions: %v", err)
+	}
+	if len(transitions) != 1 || transitions[0].ToStatus != models.ArticlePublished || transitions[0].ActorID != nil {
+		t.Fatalf("transitions = %+v, want one publication without actor", transitions)
+	}
+
+	pending, err := s.Articles.GetByID(ctx, later.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if pending.Status != models.ArticleDraft || pending.PublishAt == nil {
+		t.Fatalf("article scheduled for later = %+v", pending)
+	}
+
+	if run := mustProcessSchedules(t, s, 10); run.Total() != 0 {
+		t.Fatalf("second run = %+v, want nothing to do", run)
+	}
+}
+
+func testScheduleUnpublishWhenDue(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "announcement")
+	mustTransition(t, s, ctx, article.ID, models.ArticlePublished)
+
+	mustSchedule(t, s, article.ID, repository.ScheduleUnpublish, scheduleAt(-time.Second))
+	run := mustProcessSchedules(t, s, 10)
+	if run != (repository.ScheduleRun{Unpublished: 1}) {
+		t.Fatalf("run = %+v, want one unpublication", run)
+	}
+
+	got, err := s.Articles.GetByID(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Status != models.ArticleArchived || got.UnpublishAt != nil {
+		t.Fatalf("article after run = %+v", got)
+	}
+	if published := mustGetPublished(t, s); len(published) != 0 {
+		t.Fatalf("GetPublished = %d articles after expiry, want 0", len(published))
+	}
+}
+
+func testScheduleBothDue(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "flash")
+
+	mustSchedule(t, s, article.ID, repository.SchedulePublish, scheduleAt(-2*time.Minute))
+	mustSchedule(t, s, article.ID, repository.ScheduleUnpublish, scheduleAt(-time.Minute))
+
+	run := mustProcessSchedules(t, s, 10)
+	if run != (repository.ScheduleRun{Published: 1, Unpublished: 1}) {
+		t.Fatalf("run = %+v", run)
+	}
+	transitions, err := s.Articles.ListTransitions(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("ListTransitions: %v", err)
+	}
+	if len(transitions) != 2 || transitions[0].ToStatus != models.ArticlePublished || transitions[1].ToStatus != models.ArticleArchived {
+		t.Fatalf("transitions = %+v, want published then archived", transitions)
+	}
+}
+
+func testScheduleBatches(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	for i := 0; i < 3; i++ {
+		article := mustCreateArticle(t, s, author.ID, "T")
+		mustSchedule(t, s, article.ID, repository.SchedulePublish, scheduleAt(-time.Duration(3-i)*time.Minute))
+	}
+
+	if run := mustProcessSchedules(t, s, 2); run.Published != 2 {
+		t.Fatalf("first batch = %+v, want 2 published", run)
+	}
+	if run := mustProcessSchedules(t, s, 2); run.Published != 1 {
+		t.Fatalf("second batch = %+v, want 1 published", run)
+	}
+	if published := mustGetPublished(t, s); len(published) != 3 {
+		t.Fatalf("GetPublished = %d articles, want 3", len(published))
+	}
+}
+
+func testScheduleValidation(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	draft := mustCreateArticle(t, s, author.ID, "draft")
+	published := mustCreateArticle(t, s, author.ID, "published")
+	mustTransition(t, s, ctx, published.ID, models.ArticlePublished)
+
+	tests := []struct {
+		name   string
+		id     int
+		action repository.ScheduleAction
+		at     time.Time
+		want   error
+	}{
+		{"UnknownAction", draft.ID, "delete", scheduleAt(time.Hour), repository.ErrInvalidInput},
+		{"ZeroTime", draft.ID, repository.SchedulePublish, time.Time{}, repository.ErrInvalidInput},
+		{"MissingArticle", 999999, repository.SchedulePublish, scheduleAt(time.Hour), repository.ErrNotFound},
+		{"PublishPublished", published.ID, repository.SchedulePublish, scheduleAt(time.Hour), repository.ErrAlreadyPublished},
+		{"UnpublishUnscheduledDraft", draft.ID, repository.ScheduleUnpublish, scheduleAt(time.Hour), repository.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Articles.Schedule(ctx, tt.id, tt.action, tt.at); !errors.Is(err, tt.want) {
+				t.Fatalf("Schedule: err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// Снятие с публикации должно идти после публикации, и наоборот
+	mustSchedule(t, s, draft.ID, repository.SchedulePublish, scheduleAt(2*time.Hour))
+	if _, err := s.Articles.Schedule(ctx, draft.ID, repository.ScheduleUnpublish, scheduleAt(time.Hour)); !errors.Is(err, repository.ErrInvalidInput) {
+		t.Fatalf("unpublish before publish: err = %v, want ErrInvalidInput", err)
+	}
+	mustSchedule(t, s, draft.ID, repository.ScheduleUnpublish, scheduleAt(3*time.Hour))
+	if _, err := s.Articles.Schedule(ctx, draft.ID, repository.SchedulePublish, scheduleAt(4*time.Hour)); !errors.Is(err, repository.ErrInvalidInput) {
+		t.Fatalf("publish after unpublish: err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func testScheduleCancel(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+	mustSchedule(t, s, article.ID, repository.SchedulePublish, scheduleAt(-time.Minute))
+
+	canceled, err := s.Articles.CancelSchedule(ctx, article.ID, repository.SchedulePublish)
+	if err != nil {
+		t.Fatalf("CancelSchedule: %v", err)
+	}
+	if canceled.PublishAt != nil {
+		t.Fatalf("publish_at = %v after cancel", canceled.PublishAt)
+	}
+	// Повторная отмена ничего не меняет
+	if _, err := s.Articles.CancelSchedule(ctx, article.ID, repository.SchedulePublish); err != nil {
+		t.Fatalf("second CancelSchedule: %v", err)
+	}
+	if run := mustProcessSchedules(t, s, 10); run.Total() != 0 {
+		t.Fatalf("run after cancel = %+v", run)
+	}
+
+	if _, err := s.Articles.CancelSchedule(ctx, 999999, repository.SchedulePublish); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("CancelSchedule missing: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Articles.CancelSchedule(ctx, article.ID, "delete"); !errors.Is(err, repository.ErrInvalidInput) {
+		t.Fatalf("CancelSchedule unknown action: err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func testScheduleManualTransition(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+	mustSchedule(t, s, article.ID, repository.SchedulePublish, scheduleAt(time.Hour))
+	mustSchedule(t, s, article.ID, repository.ScheduleUnpublish, scheduleAt(2*time.Hour))
+
+	// Ручная публикация снимает запланированную, но сохраняет срок снятия
+	published := mustTransition(t, s, ctx, article.ID, models.ArticlePublished)
+	if published.PublishAt != nil || published.UnpublishAt == nil {
+		t.Fatalf("after manual publish: publish_at=%v unpublish_at=%v", published.PublishAt, published.UnpublishAt)
+	}
+
+	// Ручное снятие с публикации отменяет запланированное
+	archived := mustTransition(t, s, ctx, article.ID, models.ArticleArchived)
+	if archived.UnpublishAt != nil {
+		t.Fatalf("after manual archive: unpublish_at=%v", archived.UnpublishAt)
+	}
+}
+
+func testScheduleUpdateKeeps(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+	at := scheduleAt(time.Hour)
+	mustSchedule(t, s, article.ID, repository.SchedulePublish, at)
+
+	article.Title = "edited"
+	article.PublishAt = nil
+	if err := s.Articles.Update(ctx, article); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if article.PublishAt == nil || !article.PublishAt.Equal(at) {
+		t.Fatalf("Update changed publish_at to %v", article.PublishAt)
+	}
+}
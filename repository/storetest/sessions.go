@@ -0,0 +1,241 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"testing"
+	"time"
+)
+
+func runSessionTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"CreateAndGetByToken", testSessionCreateAndGet},
+		{"Touch", testSessionTouch},
+		{"Rotate", testSessionRotate},
+		{"ReuseRevokes", testSessionReuseRevokes},
+		{"ListActive", testSessionListActive},
+		{"Revoke", testSessionRevoke},
+		{"RevokeAll", testSessionRevokeAll},
+		{"Purge", testSessionPurge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+// sessionNow — время с точностью PostgreSQL, чтобы сравнивать через Equal
+func sessionNow() time.Time {
+	return time.Now().Truncate(time.Millisecond)
+}
+
+// mustCreateSession создает сессию, начатую в start и действующую сутки
+func mustCreateSession(t *testing.T, s Stores, userID int, token string, start time.Time) *models.Session {
+	t.Helper()
+	session := &models.Session{
+		UserID:        userID,
+		UserAgent:     "test",
+		IP:            "127.0.0.1",
+		CreatedAt:     start,
+		LastSeenAt:    start,
+		ExpiresAt:     start.Add(24 * time.Hour),
+		IdleExpiresAt: start.Add(time.Hour),
+	}
+	if err := s.Sessions.Create(context.Background(), session, []byte(token)); err != nil {
+		t.Fatalf("Create session: %v", err)
+	}
+	return session
+}
+
+func testSessionCreateAndGet(t *testing.T, s Stores) {
+	user := mustCreateUser(t, s, "alice@example.com")
+	now := sessionNow()
+	created := mustCreateSession(t, s, user.ID, "token-1", now)
+	if created.ID == 0 {
+		t.Fatal("Create did not set ID")
+	}
+
+	got, err := s.Sessions.GetByToken(context.Background(), []byte("token-1"))
+	if err != nil {
+		t.Fatalf("GetByToken: %v", err)
+	}
+	if got.ID != created.ID || got.UserID != user.ID || got.UserAgent != "test" || got.IP != "127.0.0.1" {
+		t.Fatalf("GetByToken = %+v, want %+v", got, created)
+	}
+	if !got.ExpiresAt.Equal(created.ExpiresAt) || !got.IdleExpiresAt.Equal(created.IdleExpiresAt) || got.RevokedAt != nil {
+		t.Fatalf("GetByToken times = %+v, want %+v", got, created)
+	}
+
+	if _, err := s.Sessions.GetByToken(context.Background(), []byte("unknown")); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByToken unknown: err = %v, want ErrNotFound", err)
+	}
+	err = s.Sessions.Create(context.Background(), &models.Session{UserID: 999, ExpiresAt: now, IdleExpiresAt: now}, []byte("token-2"))
+	if !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("Create for missing user: err = %v, want ErrConflict", err)
+	}
+}
+
+func testSessionTouch(t *testing.T, s Stores) {
+	ctx := context.Background()
+	user := mustCreateUser(t, s, "alice@example.com")
+	now := sessionNow()
+	session := mustCreateSession(t, s, user.ID, "token", now)
+
+	seen := now.Add(30 * time.Minute)
+	if err := s.Sessions.Touch(ctx, session.ID, seen, seen.Add(time.Hour)); err != nil {
+		t.Fatalf("Touch: %v", err)
+	}
+	got, err := s.Sessions.GetByToken(ctx, []byte("token"))
+	if err != nil {
+		t.Fatalf("GetByToken: %v", err)
+	}
+	if !got.LastSeenAt.Equal(seen) || !got.IdleExpiresAt.Equal(seen.Add(time.Hour)) {
+		t.Fatalf("after Touch last_seen=%v idle=%v, want %v and %v", got.LastSeenAt, got.IdleExpiresAt, seen, seen.Add(time.Hour))
+	}
+	if err := s.Sessions.Touch(ctx, 999, seen, seen); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Touch missing: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testSessionRotate(t *testing.T, s Stores) {
+	ctx := context.Background()
+	user := mustCreateUser(t, s, "alice@example.com")
+	now := sessionNow()
+	session := mustCreateSession(t, s, user.ID, "old", now)
+
+	if err := s.Sessions.Rotate(ctx, session.ID, []byte("old"), []byte("new"), now, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if _, err := s.Sessions.GetByToken(ctx, []byte("old")); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByToken(old) after rotation: err = %v, want ErrNotFound", err)
+	}
+	if got, err := s.Sessions.GetByToken(ctx, []byte("new")); err != nil || got.ID != session.ID {
+		t.Fatalf("GetByToken(new) = %v, %v, want session %d", got, err, session.ID)
+	}
+
+	// Параллельная ротация тем же токеном проигрывает
+	err := s.Sessions.Rotate(ctx, session.ID, []byte("old"), []byte("other"), now, now.Add(time.Hour))
+	if !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("Rotate with stale token: err = %v, want ErrConflict", err)
+	}
+}
+
+func testSessionReuseRevokes(t *testing.T, s Stores) {
+	ctx := context.Background()
+	user := mustCreateUser(t, s, "alice@example.com")
+	now := sessionNow()
+	session := mustCreateSession(t, s, user.ID, "old", now)
+	if err := s.Sessions.Rotate(ctx, session.ID, []byte("old"), []byte("new"), now, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	id, err := s.Sessions.RevokeByPreviousToken(ctx, []byte("old"))
+	if err != nil || id != session.ID {
+		t.Fatalf("RevokeByPreviousToken = %d, %v, want %d", id, err, session.ID)
+	}
+	got, err := s.Sessions.GetByToken(ctx, []byte("new"))
+	if err != nil {
+		t.Fatalf("GetByToken: %v", err)
+	}
+	if got.RevokedAt == nil {
+		t.Fatal("session is not revoked after token reuse")
+	}
+	if _, err := s.Sessions.RevokeByPreviousToken(ctx, []byte("new")); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("RevokeByPreviousToken(current token): err = %v, want ErrNotFound", err)
+	}
+}
+
+func testSessionListActive(t *testing.T, s Stores) {
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice@example.com")
+	bob := mustCreateUser(t, s, "bob@example.com")
+	now := sessionNow()
+
+	older := mustCreateSession(t, s, alice.ID, "older", now.Add(-10*time.Minute))
+	newer := mustCreateSession(t, s, alice.ID, "newer", now.Add(-time.Minute))
+	revoked := mustCreateSession(t, s, alice.ID, "revoked", now)
+	idle := mustCreateSession(t, s, alice.ID, "idle", now.Add(-2*time.Hour))
+	mustCreateSession(t, s, bob.ID, "bob", now)
+	if err := s.Sessions.Revoke(ctx, alice.ID, revoked.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+
+	sessions, err := s.Sessions.ListActive(ctx, alice.ID, now)
+	if err != nil {
+		t.Fatalf("ListActive: %v", err)
+	}
+	var ids []int
+	for _, session := range sessions {
+		ids = append(ids, session.ID)
+	}
+	if len(ids) != 2 || ids[0] != newer.ID || ids[1] != older.ID {
+		t.Fatalf("ListActive = %v, want [%d %d] (idle session %d excluded)", ids, newer.ID, older.ID, idle.ID)
+	}
+}
+
+func testSessionRevoke(t *testing.T, s Stores) {
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice@example.com")
+	bob := mustCreateUser(t, s, "bob@example.com")
+	session := mustCreateSession(t, s, alice.ID, "token", sessionNow())
+
+	if err := s.Sessions.Revoke(ctx, bob.ID, session.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Revoke foreign session: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Sessions.Revoke(ctx, alice.ID, session.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if err := s.Sessions.Revoke(ctx, alice.ID, session.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Revoke twice: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Sessions.Touch(ctx, session.ID, sessionNow(), sessionNow()); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Touch revoked: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testSessionRevokeAll(t *testing.T, s Stores) {
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice@example.com")
+	bob := mustCreateUser(t, s, "bob@example.com")
+	now := sessionNow()
+	mustCreateSession(t, s, alice.ID, "a1", now)
+	mustCreateSession(t, s, alice.ID, "a2", now)
+	mustCreateSession(t, s, bob.ID, "b1", now)
+
+	n, err := s.Sessions.RevokeAll(ctx, alice.ID)
+	if err != nil || n != 2 {
+		t.Fatalf("RevokeAll = %d, %v, want 2", n, err)
+	}
+	if sessions, _ := s.Sessions.ListActive(ctx, alice.ID, now); len(sessions) != 0 {
+		t.Fatalf("alice has %d active sessions after RevokeAll", len(sessions))
+	}
+	if sessions, _ := s.Sessions.ListActive(ctx, bob.ID, now); len(sessions) != 1 {
+		t.Fatalf("bob has %d active sessions, want 1", len(sessions))
+	}
+}
+
+func testSessionPurge(t *testing.T, s Stores) {
+	ctx := context.Background()
+	user := mustCreateUser(t, s, "alice@example.com")
+	now := sessionNow()
+	mustCreateSession(t, s, user.ID, "expired", now.Add(-72*time.Hour))
+	active := mustCreateSession(t, s, user.ID, "active", now)
+
+	n, err := s.Sessions.Purge(ctx, 24*time.Hour)
+	if err != nil || n != 1 {
+		t.Fatalf("Purge = %d, %v, want 1", n, err)
+	}
+	if _, err := s.Sessions.GetByToken(ctx, []byte("expired")); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("expired session survived Purge: err = %v", err)
+	}
+	if got, err := s.Sessions.GetByToken(ctx, []byte("active")); err != nil || got.ID != active.ID {
+		t.Fatalf("active session after Purge = %v, %v", got, err)
+	}
+	if _, err := s.Sessions.Purge(ctx, -time.Hour); !errors.Is(err, repository.ErrInvalidInput) {
+		t.Fatalf("Purge with negative retention: err = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,169 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"testing"
+)
+
+func runSlugTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"CreateAssignsUniqueSlugs", testSlugCreateUnique},
+		{"GetBySlug", testSlugGetBySlug},
+		{"TitleChangeKeepsOldSlug", testSlugTitleChange},
+		{"PunctuationEditKeepsSlug", testSlugPunctuationEdit},
+		{"RevertReclaimsSlug", testSlugRevertReclaims},
+		{"RestoreRevision", testSlugRestoreRevision},
+		{"TrashedArticle", testSlugTrashedArticle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+// mustGetBySlug проверяет, что slug разрешается в статью с каноническим слагом canonical
+func mustGetBySlug(t *testing.T, s Stores, slug string, id int, canonical string) {
+	t.Helper()
+	got, err := s.Articles.GetBySlug(context.Background(), slug)
+	if err != nil {
+		t.Fatalf("GetBySlug(%q): %v", slug, err)
+	}
+	if got.ID != id || got.Slug != canonical {
+		t.Fatalf("GetBySlug(%q) = article %d with slug %q, want %d with %q", slug, got.ID, got.Slug, id, canonical)
+	}
+}
+
+func mustRetitle(t *testing.T, s Stores, a *models.Article, title string) {
+	t.Helper()
+	a.Title = title
+	if err := s.Articles.Update(context.Background(), a); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+}
+
+func testSlugCreateUnique(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	first := mustCreateArticle(t, s, author.ID, "Привет, мир!")
+	second := mustCreateArticle(t, s, author.ID, "привет мир")
+	third := mustCreateArticle(t, s, author.ID, "?!")
+
+	if first.Slug != "privet-mir" || second.Slug != "privet-mir-2" {
+		t.Fatalf("slugs = %q, %q, want privet-mir, privet-mir-2", first.Slug, second.Slug)
+	}
+	if third.Slug != "article" {
+		t.Fatalf("slug of a title without letters = %q, want article", third.Slug)
+	}
+
+	got, err := s.Articles.GetByID(context.Background(), second.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Slug != second.Slug {
+		t.Fatalf("GetByID slug = %q, want %q", got.Slug, second.Slug)
+	}
+}
+
+func testSlugGetBySlug(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	a := mustCreateArticle(t, s, author.ID, "Go Concurrency")
+
+	mustGetBySlug(t, s, "go-concurrency", a.ID, "go-concurrency")
+	if _, err := s.Articles.GetBySlug(context.Background(), "missing"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetBySlug(missing): err = %v, want ErrNotFound", err)
+	}
+}
+
+func testSlugTitleChange(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	a := mustCreateArticle(t, s, author.ID, "Черновик")
+
+	mustRetitle(t, s, a, "Итоговое название")
+	if a.Slug != "itogovoe-nazvanie" {
+		t.Fatalf("Update slug = %q, want itogovoe-nazvanie", a.Slug)
+	}
+	mustGetBySlug(t, s, "itogovoe-nazvanie", a.ID, "itogovoe-nazvanie")
+	mustGetBySlug(t, s, "chernovik", a.ID, "itogovoe-nazvanie")
+
+	// Старый слаг не достается новой статье с тем же заголовком
+	other := mustCreateArticle(t, s, author.ID, "Черновик")
+	if other.Slug != "chernovik-2" {
+		t.Fatalf("slug of a new article = %q, want chernovik-2", other.Slug)
+	}
+	mustGetBySlug(t, s, "chernovik", a.ID, "itogovoe-nazvanie")
+}
+
+func testSlugPunctuationEdit(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	a := mustCreateArticle(t, s, author.ID, "Hello world")
+
+	mustRetitle(t, s, a, "Hello, World!")
+	if a.Slug != "hello-world" {
+		t.Fatalf("Update slug = %q, want hello-world", a.Slug)
+	}
+}
+
+func testSlugRevertReclaims(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	a := mustCreateArticle(t, s, author.ID, "First")
+	mustCreateArticle(t, s, author.ID, "Second")
+
+	mustRetitle(t, s, a, "Second")
+	if a.Slug != "second-2" {
+		t.Fatalf("Update slug = %q, want second-2", a.Slug)
+	}
+	mustRetitle(t, s, a, "First")
+	if a.Slug != "first" {
+		t.Fatalf("slug after reverting the title = %q, want first", a.Slug)
+	}
+	mustGetBySlug(t, s, "second-2", a.ID, "first")
+}
+
+func testSlugRestoreRevision(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	a := mustCreateArticle(t, s, author.ID, "Original")
+	mustRetitle(t, s, a, "Renamed")
+
+	restored, err := s.Articles.RestoreRevision(context.Background(), a.ID, 1)
+	if err != nil {
+		t.Fatalf("RestoreRevision: %v", err)
+	}
+	if restored.Slug != "original" {
+		t.Fatalf("RestoreRevision slug = %q, want original", restored.Slug)
+	}
+	mustGetBySlug(t, s, "renamed", a.ID, "original")
+}
+
+func testSlugTrashedArticle(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	a := mustCreateArticle(t, s, author.ID, "Gone")
+
+	if err := s.Articles.Delete(ctx, a.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Articles.GetBySlug(ctx, "gone"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetBySlug of a trashed article: err = %v, want ErrNotFound", err)
+	}
+
+	// Слаг статьи в корзине занят до окончательного удаления
+	if b := mustCreateArticle(t, s, author.ID, "Gone"); b.Slug != "gone-2" {
+		t.Fatalf("slug next to a trashed article = %q, want gone-2", b.Slug)
+	}
+	if err := s.Articles.Restore(ctx, a.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	mustGetBySlug(t, s, "gone", a.ID, "gone")
+
+	if err := s.Articles.Delete(ctx, a.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	mustPurge(t, s)
+	if c := mustCreateArticle(t, s, author.ID, "Gone"); c.Slug != "gone" {
+		t.Fatalf("slug after purge = %q, want gone", c.Slug)
+	}
+}
@@ -0,0 +1,120 @@
+package storetest
+
+import (
+	"context"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"reflect"
+	"testing"
+)
+
+func runStatsTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"Empty", testStatsEmpty},
+		{"Counts", testStatsCounts},
+		{"TopAuthors", testStatsTopAuthors},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+func mustStats(t *testing.T, s Stores, top int) *repository.Stats {
+	t.Helper()
+	stats, err := s.Stats.Get(context.Background(), top)
+	if err != nil {
+		t.Fatalf("Stats.Get: %v", err)
+	}
+	return stats
+}
+
+func testStatsEmpty(t *testing.T, s Stores) {
+	got := mustStats(t, s, 0)
+	if want := repository.NewStats(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Stats of empty store = %+v, want %+v", got, want)
+	}
+}
+
+func testStatsCounts(t *testing.T, s Stores) {
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice@example.com")
+	bob := mustCreateUser(t, s, "bob@example.com")
+	carol := mustCreateUser(t, s, "carol@example.com")
+	if err := s.Users.SetRole(ctx, alice.ID, models.RoleEditor); err != nil {
+		t.Fatalf("SetRole: %v", err)
+	}
+
+	first := mustCreateArticle(t, s, alice.ID, "First")
+	second := mustCreateArticle(t, s, alice.ID, "Second")
+	trashed := mustCreateArticle(t, s, alice.ID, "Trashed")
+	mustCreateArticle(t, s, carol.ID, "By Carol")
+	if err := s.Articles.Publish(ctx, first.ID); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	for range 3 {
+		if err := s.Articles.IncrementViews(ctx, first.ID); err != nil {
+			t.Fatalf("IncrementViews: %v", err)
+		}
+	}
+	mustCreateComment(t, s, first.ID, bob.ID, nil, models.CommentApproved)
+	mustCreateComment(t, s, second.ID, bob.ID, nil, models.CommentPending)
+	mustCreateComment(t, s, trashed.ID, bob.ID, nil, models.CommentApproved)
+	mustSetTags(t, s, first.ID, "go", "sql")
+	if err := s.Articles.Delete(ctx, trashed.ID); err != nil {
+		t.Fatalf("Delete article: %v", err)
+	}
+	// Вместе с Carol в корзину уходит и ее статья
+	if err := s.Users.Delete(ctx, carol.ID); err != nil {
+		t.Fatalf("Delete user: %v", err)
+	}
+
+	got := mustStats(t, s, 0)
+	want := &repository.Stats{
+		Users:            2,
+		UsersByRole:      map[models.Role]int{models.RoleEditor: 1, models.DefaultRole: 1},
+		TrashedUsers:     1,
+		Articles:         2,
+		ArticlesByStatus: map[models.ArticleStatus]int{models.ArticlePublished: 1, models.ArticleDraft: 1},
+		TrashedArticles:  2,
+		Views:            3,
+		CommentsByStatus: map[models.CommentStatus]int{models.CommentApproved: 1, models.CommentPending: 1},
+		Tags:             2,
+		TopAuthors:       []repository.AuthorStats{{ID: alice.ID, Name: "alice", Articles: 2, Views: 3}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Stats =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func testStatsTopAuthors(t *testing.T, s Stores) {
+	ctx := context.Background()
+	var ids []int
+	for i, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		u := mustCreateUser(t, s, email)
+		ids = append(ids, u.ID)
+		for j := 0; j < 3-i; j++ {
+			mustCreateArticle(t, s, u.ID, email+" article")
+		}
+	}
+	// У c одна статья, но больше просмотров, чем у b: порядок по числу статей
+	lone, err := s.Articles.GetByAuthorID(ctx, ids[2])
+	if err != nil {
+		t.Fatalf("GetByAuthorID: %v", err)
+	}
+	for range 5 {
+		if err := s.Articles.IncrementViews(ctx, lone[0].ID); err != nil {
+			t.Fatalf("IncrementViews: %v", err)
+		}
+	}
+
+	got := mustStats(t, s, 2).TopAuthors
+	if len(got) != 2 || got[0].ID != ids[0] || got[1].ID != ids[1] {
+		t.Fatalf("TopAuthors(2) = %+v, want authors %v", got, ids[:2])
+	}
+	if got[0].Articles != 3 || got[1].Articles != 2 {
+		t.Fatalf("TopAuthors article counts = %d, %d, want 3, 2", got[0].Articles, got[1].Articles)
+	}
+}
@@ -0,0 +1,669 @@
+// Package storetest содержит общий набор тестов, которому должна
+// соответствовать любая реализация repository.UserStore и
+// repository.ArticleStore. Каждая новая реализация подключает его из
+// своего _test.go файла через Run.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Stores — набор хранилищ, разделяющих одно состояние
+type Stores struct {
+	Users      repository.UserStore
+	Articles   repository.ArticleStore
+	Tags       repository.TagStore
+	Categories repository.CategoryStore
+	Comments   repository.CommentStore
+	Sessions   repository.SessionStore
+	Audit      repository.AuditStore
+	Stats      repository.StatsStore
+	Bulk       repository.BulkStore
+}
+
+// Factory возвращает пустые хранилища для одного подтеста
+type Factory func(t *testing.T) Stores
+
+// Run запускает весь набор тестов против реализации, созданной factory
+func Run(t *testing.T, factory Factory) {
+	t.Run("Users", func(t *testing.T) { runUserTests(t, factory) })
+	t.Run("Articles", func(t *testing.T) { runArticleTests(t, factory) })
+	t.Run("Pagination", func(t *testing.T) { runPaginationTests(t, factory) })
+	t.Run("Find", func(t *testing.T) { runFindTests(t, factory) })
+	t.Run("Tags", func(t *testing.T) { runTagTests(t, factory) })
+	t.Run("Categories", func(t *testing.T) { runCategoryTests(t, factory) })
+	t.Run("Comments", func(t *testing.T) { runCommentTests(t, factory) })
+	t.Run("Revisions", func(t *testing.T) { runRevisionTests(t, factory) })
+	t.Run("Workflow", func(t *testing.T) { runWorkflowTests(t, factory) })
+	t.Run("Schedule", func(t *testing.T) { runScheduleTests(t, factory) })
+	t.Run("Trash", func(t *testing.T) { runTrashTests(t, factory) })
+	t.Run("Slugs", func(t *testing.T) { runSlugTests(t, factory) })
+	t.Run("Render", func(t *testing.T) { runRenderTests(t, factory) })
+	t.Run("Feed", func(t *testing.T) { runFeedTests(t, factory) })
+	t.Run("Passwords", func(t *testing.T) { runPasswordTests(t, factory) })
+	t.Run("Sessions", func(t *testing.T) { runSessionTests(t, factory) })
+	t.Run("Roles", func(t *testing.T) { runRoleTests(t, factory) })
+	t.Run("Audit", func(t *testing.T) { runAuditTests(t, factory) })
+	t.Run("Stats", func(t *testing.T) { runStatsTests(t, factory) })
+	t.Run("Bulk", func(t *testing.T) { runBulkTests(t, factory) })
+}
+
+func runUserTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"CreateAndGet", testUserCreateAndGet},
+		{"CreateDuplicateEmail", testUserCreateDuplicateEmail},
+		{"GetMissing", testUserGetMissing},
+		{"GetAllOrderedByID", testUserGetAll},
+		{"Update", testUserUpdate},
+		{"UpdateDuplicateEmail", testUserUpdateDuplicateEmail},
+		{"UpdateMissing", testUserUpdateMissing},
+		{"Delete", testUserDelete},
+		{"DeleteCascadesArticles", testUserDeleteCascades},
+		{"ReturnsCopies", testUserReturnsCopies},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+func runArticleTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"CreateDefaults", testArticleCreateDefaults},
+		{"CreateUnknownAuthor", testArticleCreateUnknownAuthor},
+		{"GetMissing", testArticleGetMissing},
+		{"GetByAuthorID", testArticleGetByAuthorID},
+		{"GetPublished", testArticleGetPublished},
+		{"Update", testArticleUpdate},
+		{"UpdateMissing", testArticleUpdateMissing},
+		{"UpdateUnknownAuthor", testArticleUpdateUnknownAuthor},
+		{"Delete", testArticleDelete},
+		{"PublishIdempotency", testArticlePublishIdempotency},
+		{"PublishMissing", testArticlePublishMissing},
+		{"IncrementViews", testArticleIncrementViews},
+		{"IncrementViewsConcurrent", testArticleIncrementViewsConcurrent},
+		{"CreateWithNewAuthor", testCreateArticleWithNewAuthor},
+		{"CreateWithExistingAuthor", testCreateArticleWithExistingAuthor},
+		{"CreateWithAuthorRollback", testCreateArticleWithAuthorRollback},
+		{"GetArticleWithAuthor", testGetArticleWithAuthor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+func testUserCreateAndGet(t *testing.T, s Stores) {
+	ctx := context.Background()
+	user := &models.User{Email: "alice@example.com", Name: "Alice"}
+	if err := s.Users.Create(ctx, user); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if user.ID == 0 {
+		t.Fatal("Create did not assign ID")
+	}
+	if user.CreatedAt.IsZero() || user.UpdatedAt.IsZero() {
+		t.Fatal("Create did not set timestamps")
+	}
+
+	got, err := s.Users.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Email != user.Email || got.Name != user.Name {
+		t.Fatalf("GetByID = %+v, want %+v", got, user)
+	}
+
+	got, err = s.Users.GetByEmail(ctx, user.Email)
+	if err != nil {
+		t.Fatalf("GetByEmail: %v", err)
+	}
+	if got.ID != user.ID {
+		t.Fatalf("GetByEmail ID = %d, want %d", got.ID, user.ID)
+	}
+}
+
+func testUserCreateDuplicateEmail(t *testing.T, s Stores) {
+	ctx := context.Background()
+	mustCreateUser(t, s, "alice@example.com")
+
+	err := s.Users.Create(ctx, &models.User{Email: "alice@example.com", Name: "Other"})
+	if !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("Create duplicate: err = %v, want ErrAlreadyExists", err)
+	}
+}
+
+func testUserGetMissing(t *testing.T, s Stores) {
+	ctx := context.Background()
+	if _, err := s.Users.GetByID(ctx, 999999); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByID: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Users.GetByEmail(ctx, "nobody@example.com"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByEmail: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testUserGetAll(t *testing.T, s Stores) {
+	ctx := context.Background()
+	users, err := s.Users.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll on empty store: %v", err)
+	}
+	if len(users) != 0 {
+		t.Fatalf("GetAll on empty store returned %d users", len(users))
+	}
+
+	var ids []int
+	for i := 0; i < 3; i++ {
+		ids = append(ids, mustCreateUser(t, s, fmt.Sprintf("user%d@example.com", i)).ID)
+	}
+
+	users, err = s.Users.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	if got := userIDs(users); !slices.Equal(got, ids) {
+		t.Fatalf("GetAll IDs = %v, want %v", got, ids)
+	}
+}
+
+func testUserUpdate(t *testing.T, s Stores) {
+	ctx := context.Background()
+	user := mustCreateUser(t, s, "alice@example.com")
+	before := user.UpdatedAt
+
+	user.Email = "alice@example.org"
+	user.Name = "Alice Liddell"
+	if err := s.Users.Update(ctx, user); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if user.UpdatedAt.Before(before) {
+		t.Fatalf("Update moved UpdatedAt backwards: %v -> %v", before, user.UpdatedAt)
+	}
+
+	got, err := s.Users.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Email != "alice@example.org" || got.Name != "Alice Liddell" {
+		t.Fatalf("GetByID after Update = %+v", got)
+	}
+	if _, err := s.Users.GetByEmail(ctx, "alice@example.com"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("old email still resolves: err = %v", err)
+	}
+}
+
+func testUserUpdateDuplicateEmail(t *testing.T, s Stores) {
+	ctx := context.Background()
+	mustCreateUser(t, s, "alice@example.com")
+	bob := mustCreateUser(t, s, "bob@example.com")
+
+	bob.Email = "alice@example.com"
+	if err := s.Users.Update(ctx, bob); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("Update duplicate: err = %v, want ErrAlreadyExists", err)
+	}
+
+	got, err := s.Users.GetByID(ctx, bob.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Email != "bob@example.com" {
+		t.Fatalf("failed Update changed email to %q", got.Email)
+	}
+}
+
+func testUserUpdateMissing(t *testing.T, s Stores) {
+	err := s.Users.Update(context.Background(), &models.User{ID: 999999, Email: "x@example.com", Name: "X"})
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Update: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testUserDelete(t *testing.T, s Stores) {
+	ctx := context.Background()
+	user := mustCreateUser(t, s, "alice@example.com")
+
+	if err := s.Users.Delete(ctx, user.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Users.GetByID(ctx, user.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByID after Delete: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Users.Delete(ctx, user.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("second Delete: err = %v, want ErrNotFound", err)
+	}
+
+	// Пользователь в корзине не занимает email
+	mustCreateUser(t, s, "alice@example.com")
+}
+
+func testUserDeleteCascades(t *testing.T, s Stores) {
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice@example.com")
+	bob := mustCreateUser(t, s, "bob@example.com")
+	a1 := mustCreateArticle(t, s, alice.ID, "First")
+	a2 := mustCreateArticle(t, s, alice.ID, "Second")
+	b1 := mustCreateArticle(t, s, bob.ID, "Bob's")
+
+	if err := s.Users.Delete(ctx, alice.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	for _, id := range []int{a1.ID, a2.ID} {
+		if _, err := s.Articles.GetByID(ctx, id); !errors.Is(err, repository.ErrNotFound) {
+			t.Fatalf("article %d survived author deletion: err = %v", id, err)
+		}
+	}
+	if _, err := s.Articles.GetByID(ctx, b1.ID); err != nil {
+		t.Fatalf("unrelated article was deleted: %v", err)
+	}
+	articles, err := s.Articles.GetByAuthorID(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("GetByAuthorID: %v", err)
+	}
+	if len(articles) != 0 {
+		t.Fatalf("GetByAuthorID after cascade returned %d articles", len(articles))
+	}
+}
+
+func testUserReturnsCopies(t *testing.T, s Stores) {
+	ctx := context.Background()
+	user := mustCreateUser(t, s, "alice@example.com")
+
+	got, err := s.Users.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	got.Name = "Mallory"
+
+	again, err := s.Users.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if again.Name != user.Name {
+		t.Fatalf("mutating a returned user changed stored state: %q", again.Name)
+	}
+}
+
+func testArticleCreateDefaults(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+
+	article := &models.Article{Title: "Intro", Content: "Body", AuthorID: author.ID, Status: models.ArticlePublished, Views: 42}
+	if err := s.Articles.Create(ctx, article); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if article.ID == 0 {
+		t.Fatal("Create did not assign ID")
+	}
+	if article.Status != models.ArticleDraft || article.PublishedAt != nil || article.Views != 0 {
+		t.Fatalf("Create ignored defaults: status=%s views=%d", article.Status, article.Views)
+	}
+	if article.CreatedAt.IsZero() || article.UpdatedAt.IsZero() {
+		t.Fatal("Create did not set timestamps")
+	}
+
+	got, err := s.Articles.GetByID(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Title != "Intro" || got.Content != "Body" || got.AuthorID != author.ID || got.Status != models.ArticleDraft || got.Views != 0 {
+		t.Fatalf("GetByID = %+v", got)
+	}
+}
+
+func testArticleCreateUnknownAuthor(t *testing.T, s Stores) {
+	err := s.Articles.Create(context.Background(), &models.Article{Title: "T", Content: "C", AuthorID: 999999})
+	if !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("Create: err = %v, want ErrConflict", err)
+	}
+}
+
+func testArticleGetMissing(t *testing.T, s Stores) {
+	ctx := context.Background()
+	if _, err := s.Articles.GetByID(ctx, 999999); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByID: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Articles.GetArticleWithAuthor(ctx, 999999); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetArticleWithAuthor: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testArticleGetByAuthorID(t *testing.T, s Stores) {
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice@example.com")
+	bob := mustCreateUser(t, s, "bob@example.com")
+
+	var want []int
+	for i := 0; i < 3; i++ {
+		want = append([]int{mustCreateArticle(t, s, alice.ID, fmt.Sprintf("A%d", i)).ID}, want...)
+	}
+	mustCreateArticle(t, s, bob.ID, "B")
+
+	articles, err := s.Articles.GetByAuthorID(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("GetByAuthorID: %v", err)
+	}
+	if got := articleIDs(articles); !slices.Equal(got, want) {
+		t.Fatalf("GetByAuthorID IDs = %v, want newest first %v", got, want)
+	}
+
+	articles, err = s.Articles.GetByAuthorID(ctx, 999999)
+	if err != nil {
+		t.Fatalf("GetByAuthorID unknown author: %v", err)
+	}
+	if len(articles) != 0 {
+		t.Fatalf("GetByAuthorID unknown author returned %d articles", len(articles))
+	}
+}
+
+func testArticleGetPublished(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	draft := mustCreateArticle(t, s, author.ID, "Draft")
+	first := mustCreateArticle(t, s, author.ID, "First")
+	second := mustCreateArticle(t, s, author.ID, "Second")
+
+	for _, id := range []int{first.ID, second.ID} {
+		if err := s.Articles.Publish(ctx, id); err != nil {
+			t.Fatalf("Publish(%d): %v", id, err)
+		}
+	}
+
+	articles, err := s.Articles.GetPublished(ctx)
+	if err != nil {
+		t.Fatalf("GetPublished: %v", err)
+	}
+	if got, want := articleIDs(articles), []int{second.ID, first.ID}; !slices.Equal(got, want) {
+		t.Fatalf("GetPublished IDs = %v, want %v (draft %d excluded)", got, want, draft.ID)
+	}
+}
+
+func testArticleUpdate(t *testing.T, s Stores) {
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice@example.com")
+	bob := mustCreateUser(t, s, "bob@example.com")
+	article := mustCreateArticle(t, s, alice.ID, "Old")
+	if err := s.Articles.IncrementViews(ctx, article.ID); err != nil {
+		t.Fatalf("IncrementViews: %v", err)
+	}
+
+	article.Title = "New"
+	article.Content = "New content"
+	article.AuthorID = bob.ID
+	// Статус меняется только через Transition, Update его не трогает
+	article.Status = models.ArticlePublished
+	if err := s.Articles.Update(ctx, article); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	got, err := s.Articles.GetByID(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Title != "New" || got.Content != "New content" || got.AuthorID != bob.ID || got.Status != models.ArticleDraft {
+		t.Fatalf("GetByID after Update = %+v", got)
+	}
+	if got.Views != 1 {
+		t.Fatalf("Update reset views to %d", got.Views)
+	}
+}
+
+func testArticleUpdateMissing(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	err := s.Articles.Update(context.Background(), &models.Article{ID: 999999, Title: "T", Content: "C", AuthorID: author.ID})
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Update: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testArticleUpdateUnknownAuthor(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	article.AuthorID = 999999
+	if err := s.Articles.Update(context.Background(), article); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("Update: err = %v, want ErrConflict", err)
+	}
+}
+
+func testArticleDelete(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	if err := s.Articles.Delete(ctx, article.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Articles.GetByID(ctx, article.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByID after Delete: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Articles.Delete(ctx, article.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("second Delete: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Users.GetByID(ctx, author.ID); err != nil {
+		t.Fatalf("deleting an article removed its author: %v", err)
+	}
+}
+
+func testArticlePublishIdempotency(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	if err := s.Articles.Publish(ctx, article.ID); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	published, err := s.Articles.GetByID(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !published.IsPublished() || published.PublishedAt == nil {
+		t.Fatalf("Publish did not publish: %+v", published)
+	}
+
+	if err := s.Articles.Publish(ctx, article.ID); !errors.Is(err, repository.ErrAlreadyPublished) {
+		t.Fatalf("second Publish: err = %v, want ErrAlreadyPublished", err)
+	}
+	again, err := s.Articles.GetByID(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if !again.IsPublished() || !again.UpdatedAt.Equal(published.UpdatedAt) {
+		t.Fatalf("second Publish changed state: %+v -> %+v", published, again)
+	}
+}
+
+func testArticlePublishMissing(t *testing.T, s Stores) {
+	if err := s.Articles.Publish(context.Background(), 999999); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Publish: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testArticleIncrementViews(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	for i := 0; i < 3; i++ {
+		if err := s.Articles.IncrementViews(ctx, article.ID); err != nil {
+			t.Fatalf("IncrementViews: %v", err)
+		}
+	}
+	got, err := s.Articles.GetByID(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Views != 3 {
+		t.Fatalf("Views = %d, want 3", got.Views)
+	}
+	if err := s.Articles.IncrementViews(ctx, 999999); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("IncrementViews missing: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testArticleIncrementViewsConcurrent(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	const workers, perWorker = 8, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				if err := s.Articles.IncrementViews(ctx, article.ID); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("IncrementViews: %v", err)
+	}
+
+	got, err := s.Articles.GetByID(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Views != workers*perWorker {
+		t.Fatalf("Views = %d, want %d", got.Views, workers*perWorker)
+	}
+}
+
+func testCreateArticleWithNewAuthor(t *testing.T, s Stores) {
+	ctx := context.Background()
+	user, article, err := s.Articles.CreateArticleWithAuthor(ctx, "Diana", "diana@example.com", "Title", "Content")
+	if err != nil {
+		t.Fatalf("CreateArticleWithAuthor: %v", err)
+	}
+	if user.ID == 0 || user.Name != "Diana" || user.Email != "diana@example.com" || user.CreatedAt.IsZero() {
+		t.Fatalf("user = %+v", user)
+	}
+	if article.ID == 0 || article.AuthorID != user.ID || article.Title != "Title" || article.Status != models.ArticleDraft || article.Views != 0 {
+		t.Fatalf("article = %+v", article)
+	}
+
+	stored, err := s.Users.GetByEmail(ctx, "diana@example.com")
+	if err != nil {
+		t.Fatalf("GetByEmail: %v", err)
+	}
+	if stored.ID != user.ID {
+		t.Fatalf("stored user ID = %d, want %d", stored.ID, user.ID)
+	}
+}
+
+func testCreateArticleWithExistingAuthor(t *testing.T, s Stores) {
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice@example.com")
+
+	user, article, err := s.Articles.CreateArticleWithAuthor(ctx, "Ignored", "alice@example.com", "Title", "Content")
+	if err != nil {
+		t.Fatalf("CreateArticleWithAuthor: %v", err)
+	}
+	if user.ID != alice.ID || user.Name != alice.Name {
+		t.Fatalf("user = %+v, want existing %+v", user, alice)
+	}
+	if article.AuthorID != alice.ID {
+		t.Fatalf("article.AuthorID = %d, want %d", article.AuthorID, alice.ID)
+	}
+
+	users, err := s.Users.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	if len(users) != 1 {
+		t.Fatalf("GetAll returned %d users, want 1", len(users))
+	}
+}
+
+func testCreateArticleWithAuthorRollback(t *testing.T, s Stores) {
+	ctx := context.Background()
+
+	// Заголовок длиннее VARCHAR(255): вставка статьи падает после вставки автора
+	longTitle := strings.Repeat("x", 256)
+	if _, _, err := s.Articles.CreateArticleWithAuthor(ctx, "Eve", "eve@example.com", longTitle, "Content"); err == nil {
+		t.Fatal("CreateArticleWithAuthor with oversized title succeeded")
+	}
+
+	if _, err := s.Users.GetByEmail(ctx, "eve@example.com"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("author was not rolled back: err = %v", err)
+	}
+	users, err := s.Users.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	if len(users) != 0 {
+		t.Fatalf("GetAll returned %d users after rollback", len(users))
+	}
+}
+
+func testGetArticleWithAuthor(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	got, err := s.Articles.GetArticleWithAuthor(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("GetArticleWithAuthor: %v", err)
+	}
+	if got.Article.ID != article.ID || got.AuthorName != author.Name || got.AuthorEmail != author.Email {
+		t.Fatalf("GetArticleWithAuthor = %+v / %+v", got, got.Article)
+	}
+}
+
+func mustCreateUser(t *testing.T, s Stores, email string) *models.User {
+	t.Helper()
+	name, _, _ := strings.Cut(email, "@")
+	user := &models.User{Email: email, Name: name}
+	if err := s.Users.Create(context.Background(), user); err != nil {
+		t.Fatalf("create user %s: %v", email, err)
+	}
+	return user
+}
+
+func mustCreateArticle(t *testing.T, s Stores, authorID int, title string) *models.Article {
+	t.Helper()
+	article := &models.Article{Title: title, Content: "Content of " + title, AuthorID: authorID}
+	if err := s.Articles.Create(context.Background(), article); err != nil {
+		t.Fatalf("create article %q: %v", title, err)
+	}
+	// created_at различается между статьями, поэтому порядок сортировки детерминирован
+	time.Sleep(2 * time.Millisecond)
+	return article
+}
+
+func userIDs(users []*models.User) []int {
+	ids := make([]int, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func articleIDs(articles []*models.Article) []int {
+	ids := make([]int, 0, len(articles))
+	for _, a := range articles {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
@@ -0,0 +1,363 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func runTagTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"SetArticleTags", testSetArticleTags},
+		{"SetArticleTagsMissingArticle", testSetArticleTagsMissingArticle},
+		{"InvalidNames", testTagInvalidNames},
+		{"CreateAndGetByName", testTagCreateAndGetByName},
+		{"ArticlesByTags", testArticlesByTags},
+		{"ListWithCounts", testTagListWithCounts},
+		{"Rename", testTagRename},
+		{"Merge", testTagMerge},
+		{"Delete", testTagDelete},
+		{"ArticleDeleteCascades", testTagArticleDeleteCascades},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+func testSetArticleTags(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	tags, err := s.Tags.SetArticleTags(ctx, article.ID, []string{"Go", "databases", "  go  "})
+	if err != nil {
+		t.Fatalf("SetArticleTags: %v", err)
+	}
+	if got, want := tagNames(tags), []string{"databases", "Go"}; !slices.Equal(got, want) {
+		t.Fatalf("tags = %v, want %v", got, want)
+	}
+
+	// Замена набора: "go" совпадает с существующим "Go", "databases" снимается
+	tags, err = s.Tags.SetArticleTags(ctx, article.ID, []string{"go", "Web   dev"})
+	if err != nil {
+		t.Fatalf("SetArticleTags: %v", err)
+	}
+	if got, want := tagNames(tags), []string{"Go", "Web dev"}; !slices.Equal(got, want) {
+		t.Fatalf("tags after replace = %v, want %v", got, want)
+	}
+
+	stored, err := s.Tags.GetArticleTags(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("GetArticleTags: %v", err)
+	}
+	if got := tagNames(stored); !slices.Equal(got, []string{"Go", "Web dev"}) {
+		t.Fatalf("GetArticleTags = %v", got)
+	}
+
+	tags, err = s.Tags.SetArticleTags(ctx, article.ID, nil)
+	if err != nil {
+		t.Fatalf("SetArticleTags(nil): %v", err)
+	}
+	if len(tags) != 0 {
+		t.Fatalf("tags after clear = %v", tagNames(tags))
+	}
+
+	// Сами теги остаются, снимаются только связи
+	if _, err := s.Tags.GetByName(ctx, "databases"); err != nil {
+		t.Fatalf("GetByName after clear: %v", err)
+	}
+}
+
+func testSetArticleTagsMissingArticle(t *testing.T, s Stores) {
+	if _, err := s.Tags.SetArticleTags(context.Background(), 999999, []string{"go"}); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("SetArticleTags: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testTagInvalidNames(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	for _, names := range [][]string{{"go", "   "}, {strings.Repeat("x", repository.MaxTagNameLength+1)}} {
+		if _, err := s.Tags.SetArticleTags(ctx, article.ID, names); !errors.Is(err, repository.ErrInvalidInput) {
+			t.Fatalf("SetArticleTags(%q): err = %v, want ErrInvalidInput", names, err)
+		}
+	}
+	tags, err := s.Tags.GetArticleTags(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("GetArticleTags: %v", err)
+	}
+	if len(tags) != 0 {
+		t.Fatalf("invalid SetArticleTags partially applied: %v", tagNames(tags))
+	}
+}
+
+func testTagCreateAndGetByName(t *testing.T, s Stores) {
+	ctx := context.Background()
+	tag, err := s.Tags.Create(ctx, "Golang")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if tag.ID == 0 || tag.CreatedAt.IsZero() {
+		t.Fatalf("Create = %+v", tag)
+	}
+	if _, err := s.Tags.Create(ctx, "GOLANG"); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("Create duplicate: err = %v, want ErrAlreadyExists", err)
+	}
+
+	got, err := s.Tags.GetByName(ctx, "golang")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	if got.ID != tag.ID || got.Name != "Golang" {
+		t.Fatalf("GetByName = %+v", got)
+	}
+	if _, err := s.Tags.GetByName(ctx, "rust"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByName missing: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testArticlesByTags(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	goOnly := mustCreateArticle(t, s, author.ID, "Go")
+	goDB := mustCreateArticle(t, s, author.ID, "Go and DB")
+	dbOnly := mustCreateArticle(t, s, author.ID, "DB")
+	mustCreateArticle(t, s, author.ID, "Untagged")
+
+	mustSetTags(t, s, goOnly.ID, "go")
+	mustSetTags(t, s, goDB.ID, "go", "db")
+	mustSetTags(t, s, dbOnly.ID, "db")
+
+	tests := []struct {
+		name  string
+		tags  []string
+		match repository.TagMatch
+		want  []int
+	}{
+		{"Any", []string{"GO", "db"}, repository.TagMatchAny, []int{dbOnly.ID, goDB.ID, goOnly.ID}},
+		{"All", []string{"go", "DB"}, repository.TagMatchAll, []int{goDB.ID}},
+		{"AllWithUnknownTag", []string{"go", "rust"}, repository.TagMatchAll, []int{}},
+		{"AnyWithUnknownTag", []string{"go", "rust"}, repository.TagMatchAny, []int{goDB.ID, goOnly.ID}},
+		{"AllWithDuplicateNames", []string{"go", "Go"}, repository.TagMatchAll, []int{goDB.ID, goOnly.ID}},
+		{"Empty", nil, repository.TagMatchAny, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles, err := s.Tags.GetArticlesByTags(ctx, tt.tags, tt.match)
+			if err != nil {
+				t.Fatalf("GetArticlesByTags: %v", err)
+			}
+			if got := articleIDs(articles); !slices.Equal(got, tt.want) {
+				t.Fatalf("GetArticlesByTags(%v, %s) = %v, want %v", tt.tags, tt.match, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := s.Tags.GetArticlesByTags(ctx, []string{"go"}, "most"); !errors.Is(err, repository.ErrInvalidQuery) {
+		t.Fatalf("unknown match: err = %v, want ErrInvalidQuery", err)
+	}
+}
+
+func testTagListWithCounts(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	a1 := mustCreateArticle(t, s, author.ID, "A1")
+	a2 := mustCreateArticle(t, s, author.ID, "A2")
+
+	draft := mustCreateArticle(t, s, author.ID, "Draft")
+	for _, a := range []*models.Article{a1, a2} {
+		if err := s.Articles.Publish(ctx, a.ID); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	// Черновики не попадают в счетчики публичного списка тегов
+	mustSetTags(t, s, a1.ID, "go", "db")
+	mustSetTags(t, s, a2.ID, "go")
+	mustSetTags(t, s, draft.ID, "go", "db")
+	if _, err := s.Tags.Create(ctx, "unused"); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	usages, err := s.Tags.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var got []string
+	for _, u := range usages {
+		got = append(got, fmt.Sprintf("%s:%d", u.Tag.Name, u.ArticleCount))
+	}
+	if want := []string{"go:2", "db:1", "unused:0"}; !slices.Equal(got, want) {
+		t.Fatalf("List = %v, want %v", got, want)
+	}
+}
+
+func testTagRename(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+	mustSetTags(t, s, article.ID, "golang", "db")
+
+	tag, err := s.Tags.GetByName(ctx, "golang")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+
+	renamed, err := s.Tags.Rename(ctx, tag.ID, "Go")
+	if err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if renamed.ID != tag.ID || renamed.Name != "Go" {
+		t.Fatalf("Rename = %+v", renamed)
+	}
+	if tags := mustArticleTags(t, s, article.ID); !slices.Equal(tags, []string{"db", "Go"}) {
+		t.Fatalf("tags after rename = %v", tags)
+	}
+
+	// Изменение только регистра — тот же тег
+	if _, err := s.Tags.Rename(ctx, tag.ID, "GO"); err != nil {
+		t.Fatalf("Rename case only: %v", err)
+	}
+	if _, err := s.Tags.Rename(ctx, tag.ID, "DB"); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("Rename to existing: err = %v, want ErrAlreadyExists", err)
+	}
+	if _, err := s.Tags.Rename(ctx, 999999, "x"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Rename missing: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testTagMerge(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	onlySource := mustCreateArticle(t, s, author.ID, "A1")
+	both := mustCreateArticle(t, s, author.ID, "A2")
+	onlyTarget := mustCreateArticle(t, s, author.ID, "A3")
+
+	mustSetTags(t, s, onlySource.ID, "golang")
+	mustSetTags(t, s, both.ID, "golang", "go")
+	mustSetTags(t, s, onlyTarget.ID, "go")
+	for _, a := range []*models.Article{onlySource, both, onlyTarget} {
+		if err := s.Articles.Publish(ctx, a.ID); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	source, err := s.Tags.GetByName(ctx, "golang")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	target, err := s.Tags.GetByName(ctx, "go")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+
+	if err := s.Tags.Merge(ctx, source.ID, target.ID); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if _, err := s.Tags.GetByName(ctx, "golang"); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("source tag survived merge: err = %v", err)
+	}
+	for _, a := range []*models.Article{onlySource, both, onlyTarget} {
+		if tags := mustArticleTags(t, s, a.ID); !slices.Equal(tags, []string{"go"}) {
+			t.Fatalf("article %d tags after merge = %v", a.ID, tags)
+		}
+	}
+
+	usages, err := s.Tags.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(usages) != 1 || usages[0].ArticleCount != 3 {
+		t.Fatalf("List after merge = %+v", usages)
+	}
+
+	if err := s.Tags.Merge(ctx, target.ID, target.ID); !errors.Is(err, repository.ErrInvalidInput) {
+		t.Fatalf("Merge into itself: err = %v, want ErrInvalidInput", err)
+	}
+	if err := s.Tags.Merge(ctx, source.ID, target.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Merge missing source: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testTagDelete(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+	mustSetTags(t, s, article.ID, "go", "db")
+
+	tag, err := s.Tags.GetByName(ctx, "go")
+	if err != nil {
+		t.Fatalf("GetByName: %v", err)
+	}
+	if err := s.Tags.Delete(ctx, tag.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if tags := mustArticleTags(t, s, article.ID); !slices.Equal(tags, []string{"db"}) {
+		t.Fatalf("tags after delete = %v", tags)
+	}
+	if _, err := s.Articles.GetByID(ctx, article.ID); err != nil {
+		t.Fatalf("deleting a tag removed the article: %v", err)
+	}
+	if err := s.Tags.Delete(ctx, tag.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("second Delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testTagArticleDeleteCascades(t *testing.T, s Stores) {
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice@example.com")
+	bob := mustCreateUser(t, s, "bob@example.com")
+	a1 := mustCreateArticle(t, s, alice.ID, "A1")
+	b1 := mustCreateArticle(t, s, bob.ID, "B1")
+	mustSetTags(t, s, a1.ID, "go")
+	mustSetTags(t, s, b1.ID, "go")
+
+	if err := s.Articles.Delete(ctx, a1.ID); err != nil {
+		t.Fatalf("Delete article: %v", err)
+	}
+	if err := s.Users.Delete(ctx, bob.ID); err != nil {
+		t.Fatalf("Delete user: %v", err)
+	}
+
+	usages, err := s.Tags.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(usages) != 1 || usages[0].ArticleCount != 0 {
+		t.Fatalf("List after cascades = %+v", usages)
+	}
+}
+
+func mustSetTags(t *testing.T, s Stores, articleID int, names ...string) {
+	t.Helper()
+	if _, err := s.Tags.SetArticleTags(context.Background(), articleID, names); err != nil {
+		t.Fatalf("SetArticleTags(%d, %v): %v", articleID, names, err)
+	}
+}
+
+func mustArticleTags(t *testing.T, s Stores, articleID int) []string {
+	t.Helper()
+	tags, err := s.Tags.GetArticleTags(context.Background(), articleID)
+	if err != nil {
+		t.Fatalf("GetArticleTags: %v", err)
+	}
+	return tagNames(tags)
+}
+
+func tagNames(tags []*models.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
@@ -0,0 +1,323 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"slices"
+	"testing"
+	"time"
+)
+
+func runTrashTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"ArticleHiddenFromReads", testTrashArticleHidden},
+		{"ArticleRestore", testTrashArticleRestore},
+		{"UserRestoreBringsBackItsArticles", testTrashUserRestore},
+		{"TrashedUserFreesEmail", testTrashedUserFreesEmail},
+		{"RestoreArticleOfTrashedAuthor", testTrashRestoreArticleOfTrashedAuthor},
+		{"TrashedAuthorCannotWrite", testTrashedAuthorCannotWrite},
+		{"PurgeRetention", testTrashPurgeRetention},
+		{"PurgeUserRemovesArticles", testTrashPurgeUser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+// mustPurge окончательно удаляет все содержимое корзины
+func mustPurge(t *testing.T, s Stores) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := s.Articles.Purge(ctx, 0); err != nil {
+		t.Fatalf("Purge articles: %v", err)
+	}
+	if _, err := s.Users.Purge(ctx, 0); err != nil {
+		t.Fatalf("Purge users: %v", err)
+	}
+}
+
+func testTrashArticleHidden(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+	mustTransition(t, s, ctx, article.ID, models.ArticlePublished)
+	if _, err := s.Tags.SetArticleTags(ctx, article.ID, []string{"go"}); err != nil {
+		t.Fatalf("SetArticleTags: %v", err)
+	}
+
+	if err := s.Articles.Delete(ctx, article.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, err := s.Articles.GetByID(ctx, article.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByID: err = %v, want ErrNotFound", err)
+	}
+	if got := mustGetPublished(t, s); len(got) != 0 {
+		t.Fatalf("GetPublished = %d articles, want 0", len(got))
+	}
+	byAuthor, err := s.Articles.GetByAuthorID(ctx, author.ID)
+	if err != nil {
+		t.Fatalf("GetByAuthorID: %v", err)
+	}
+	if len(byAuthor) != 0 {
+		t.Fatalf("GetByAuthorID = %d articles, want 0", len(byAuthor))
+	}
+	found, err := s.Articles.Find(ctx, repository.ArticleQuery{})
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if found.Total != 0 {
+		t.Fatalf("Find total = %d, want 0", found.Total)
+	}
+	byTag, err := s.Tags.GetArticlesByTags(ctx, []string{"go"}, repository.TagMatchAny)
+	if err != nil {
+		t.Fatalf("GetArticlesByTags: %v", err)
+	}
+	if len(byTag) != 0 {
+		t.Fatalf("GetArticlesByTags = %d articles, want 0", len(byTag))
+	}
+
+	// Запись в статью из корзины ведет себя как запись в отсутствующую
+	if err := s.Articles.Update(ctx, article); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Update: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Articles.IncrementViews(ctx, article.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("IncrementViews: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Articles.Transition(ctx, article.ID, models.ArticleArchived); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Transition: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Articles.ListRevisions(ctx, article.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("ListRevisions: err = %v, want ErrNotFound", err)
+	}
+	comment := &models.Comment{ArticleID: article.ID, AuthorID: author.ID, Body: "hi"}
+	if err := s.Comments.Create(ctx, comment); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Create comment: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Articles.Delete(ctx, article.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("second Delete: err = %v, want ErrNotFound", err)
+	}
+
+	trash, err := s.Articles.ListTrash(ctx)
+	if err != nil {
+		t.Fatalf("ListTrash: %v", err)
+	}
+	if len(trash) != 1 || trash[0].ID != article.ID || trash[0].DeletedAt == nil {
+		t.Fatalf("ListTrash = %+v", trash)
+	}
+}
+
+func testTrashArticleRestore(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+	mustTransition(t, s, ctx, article.ID, models.ArticlePublished)
+
+	if err := s.Articles.Restore(ctx, article.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Restore live article: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Articles.Delete(ctx, article.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Articles.Restore(ctx, article.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	got, err := s.Articles.GetByID(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("GetByID after Restore: %v", err)
+	}
+	if !got.IsPublished() || got.DeletedAt != nil {
+		t.Fatalf("restored article = %+v", got)
+	}
+	trash, err := s.Articles.ListTrash(ctx)
+	if err != nil {
+		t.Fatalf("ListTrash: %v", err)
+	}
+	if len(trash) != 0 {
+		t.Fatalf("ListTrash after Restore = %d articles", len(trash))
+	}
+	if err := s.Articles.Restore(ctx, 999999); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Restore missing: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testTrashUserRestore(t *testing.T, s Stores) {
+	ctx := context.Background()
+	alice := mustCreateUser(t, s, "alice@example.com")
+	keep := mustCreateArticle(t, s, alice.ID, "keep")
+	removed := mustCreateArticle(t, s, alice.ID, "removed earlier")
+
+	if err := s.Articles.Delete(ctx, removed.ID); err != nil {
+		t.Fatalf("Delete article: %v", err)
+	}
+	if err := s.Users.Delete(ctx, alice.ID); err != nil {
+		t.Fatalf("Delete user: %v", err)
+	}
+
+	if _, err := s.Users.GetByEmail(ctx, alice.Email); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("GetByEmail of trashed user: err = %v, want ErrNotFound", err)
+	}
+	users, err := s.Users.ListTrash(ctx)
+	if err != nil {
+		t.Fatalf("ListTrash users: %v", err)
+	}
+	if len(users) != 1 || users[0].ID != alice.ID || users[0].DeletedAt == nil {
+		t.Fatalf("users in trash = %+v", users)
+	}
+	articles, err := s.Articles.ListTrash(ctx)
+	if err != nil {
+		t.Fatalf("ListTrash articles: %v", err)
+	}
+	if ids := articleIDs(articles); !slices.Equal(ids, []int{keep.ID, removed.ID}) {
+		t.Fatalf("articles in trash = %v, want %v", ids, []int{keep.ID, removed.ID})
+	}
+
+	if err := s.Users.Restore(ctx, alice.ID); err != nil {
+		t.Fatalf("Restore user: %v", err)
+	}
+	if _, err := s.Users.GetByID(ctx, alice.ID); err != nil {
+		t.Fatalf("GetByID after Restore: %v", err)
+	}
+	// Возвращаются только статьи, удаленные вместе с пользователем
+	if _, err := s.Articles.GetByID(ctx, keep.ID); err != nil {
+		t.Fatalf("article trashed with its author was not restored: %v", err)
+	}
+	if _, err := s.Articles.GetByID(ctx, removed.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("article deleted on its own was restored: err = %v", err)
+	}
+	if err := s.Users.Restore(ctx, alice.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("second Restore: err = %v, want ErrNotFound", err)
+	}
+}
+
+// Адрес пользователя из корзины можно занять снова; вернуть такого
+// пользователя нельзя, пока адрес занят
+func testTrashedUserFreesEmail(t *testing.T, s Stores) {
+	ctx := context.Background()
+	old := mustCreateUser(t, s, "alice@example.com")
+	if err := s.Users.Delete(ctx, old.ID); err != nil {
+		t.Fatalf("Delete user: %v", err)
+	}
+
+	fresh := mustCreateUser(t, s, "alice@example.com")
+	bob := mustCreateUser(t, s, "bob@example.com")
+	bob.Email = old.Email
+	if err := s.Users.Update(ctx, bob); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("Update to a taken email: err = %v, want ErrAlreadyExists", err)
+	}
+	if err := s.Users.Restore(ctx, old.ID); !errors.Is(err, repository.ErrAlreadyExists) {
+		t.Fatalf("Restore with a taken email: err = %v, want ErrAlreadyExists", err)
+	}
+
+	if err := s.Users.Delete(ctx, fresh.ID); err != nil {
+		t.Fatalf("Delete user: %v", err)
+	}
+	if err := s.Users.Restore(ctx, old.ID); err != nil {
+		t.Fatalf("Restore after the email was freed: %v", err)
+	}
+	if got, err := s.Users.GetByEmail(ctx, old.Email); err != nil || got.ID != old.ID {
+		t.Fatalf("GetByEmail = %+v, %v, want user %d", got, err, old.ID)
+	}
+}
+
+func testTrashRestoreArticleOfTrashedAuthor(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+	if err := s.Users.Delete(ctx, author.ID); err != nil {
+		t.Fatalf("Delete user: %v", err)
+	}
+
+	if err := s.Articles.Restore(ctx, article.ID); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("Restore article of trashed author: err = %v, want ErrConflict", err)
+	}
+}
+
+func testTrashedAuthorCannotWrite(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	other := mustCreateUser(t, s, "bob@example.com")
+	article := mustCreateArticle(t, s, other.ID, "T")
+	if err := s.Users.Delete(ctx, author.ID); err != nil {
+		t.Fatalf("Delete user: %v", err)
+	}
+
+	if err := s.Articles.Create(ctx, &models.Article{Title: "T", Content: "C", AuthorID: author.ID}); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("Create article by trashed author: err = %v, want ErrConflict", err)
+	}
+	article.AuthorID = author.ID
+	if err := s.Articles.Update(ctx, article); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("Update to trashed author: err = %v, want ErrConflict", err)
+	}
+	comment := &models.Comment{ArticleID: article.ID, AuthorID: author.ID, Body: "hi"}
+	if err := s.Comments.Create(ctx, comment); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("Create comment by trashed author: err = %v, want ErrConflict", err)
+	}
+	if err := s.Users.Update(ctx, &models.User{ID: author.ID, Email: "new@example.com", Name: "New"}); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Update trashed user: err = %v, want ErrNotFound", err)
+	}
+}
+
+func testTrashPurgeRetention(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+	if err := s.Articles.Delete(ctx, article.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	n, err := s.Articles.Purge(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("Purge(1h) removed %d fresh articles", n)
+	}
+
+	n, err = s.Articles.Purge(ctx, 0)
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("Purge(0) removed %d articles, want 1", n)
+	}
+	if err := s.Articles.Restore(ctx, article.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Restore purged article: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Articles.Purge(ctx, -time.Second); !errors.Is(err, repository.ErrInvalidInput) {
+		t.Fatalf("Purge negative retention: err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func testTrashPurgeUser(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	mustCreateArticle(t, s, author.ID, "T")
+	if err := s.Users.Delete(ctx, author.ID); err != nil {
+		t.Fatalf("Delete user: %v", err)
+	}
+
+	n, err := s.Users.Purge(ctx, 0)
+	if err != nil {
+		t.Fatalf("Purge users: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("Purge removed %d users, want 1", n)
+	}
+	articles, err := s.Articles.ListTrash(ctx)
+	if err != nil {
+		t.Fatalf("ListTrash: %v", err)
+	}
+	if len(articles) != 0 {
+		t.Fatalf("articles of a purged user left in trash: %d", len(articles))
+	}
+	if err := s.Users.Restore(ctx, author.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Restore purged user: err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,230 @@
+package storetest
+
+import (
+	"context"
+	"errors"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"testing"
+)
+
+func runWorkflowTests(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"ReviewAndPublish", testWorkflowReviewAndPublish},
+		{"ForbiddenTransitions", testWorkflowForbidden},
+		{"UnknownStatus", testWorkflowUnknownStatus},
+		{"ArchiveHidesFromPublished", testWorkflowArchive},
+		{"UnknownActorRollsBack", testWorkflowUnknownActor},
+		{"Missing", testWorkflowMissing},
+		{"Cascades", testWorkflowCascades},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, factory(t)) })
+	}
+}
+
+func mustTransition(t *testing.T, s Stores, ctx context.Context, id int, to models.ArticleStatus) *models.Article {
+	t.Helper()
+	article, err := s.Articles.Transition(ctx, id, to)
+	if err != nil {
+		t.Fatalf("Transition(%d, %s): %v", id, to, err)
+	}
+	return article
+}
+
+func testWorkflowReviewAndPublish(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	editor := mustCreateUser(t, s, "bob@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	review := mustTransition(t, s, repository.WithActor(context.Background(), author.ID), article.ID, models.ArticleInReview)
+	if review.Status != models.ArticleInReview || review.PublishedAt != nil {
+		t.Fatalf("after submit: status=%s published_at=%v", review.Status, review.PublishedAt)
+	}
+
+	published := mustTransition(t, s, repository.WithActor(context.Background(), editor.ID), article.ID, models.ArticlePublished)
+	if !published.IsPublished() || published.PublishedAt == nil {
+		t.Fatalf("after publish: %+v", published)
+	}
+	if published.Title != "T" || published.AuthorID != author.ID {
+		t.Fatalf("Transition returned %+v", published)
+	}
+
+	got, err := s.Articles.GetByID(context.Background(), article.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Status != models.ArticlePublished || got.PublishedAt == nil || !got.PublishedAt.Equal(*published.PublishedAt) {
+		t.Fatalf("stored article = %+v", got)
+	}
+
+	transitions, err := s.Articles.ListTransitions(context.Background(), article.ID)
+	if err != nil {
+		t.Fatalf("ListTransitions: %v", err)
+	}
+	if len(transitions) != 2 {
+		t.Fatalf("got %d transitions, want 2", len(transitions))
+	}
+	first, second := transitions[0], transitions[1]
+	if first.FromStatus != models.ArticleDraft || first.ToStatus != models.ArticleInReview {
+		t.Fatalf("first transition = %s -> %s", first.FromStatus, first.ToStatus)
+	}
+	if first.ActorID == nil || *first.ActorID != author.ID {
+		t.Fatalf("first transition actor = %v, want %d", first.ActorID, author.ID)
+	}
+	if second.FromStatus != models.ArticleInReview || second.ToStatus != models.ArticlePublished {
+		t.Fatalf("second transition = %s -> %s", second.FromStatus, second.ToStatus)
+	}
+	if second.ActorID == nil || *second.ActorID != editor.ID {
+		t.Fatalf("second transition actor = %v, want %d", second.ActorID, editor.ID)
+	}
+	if second.CreatedAt.Before(first.CreatedAt) {
+		t.Fatal("transitions are not ordered oldest first")
+	}
+}
+
+func testWorkflowForbidden(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	// Из черновика нельзя сразу в архив, в тот же статус переходить тоже нельзя
+	for _, to := range []models.ArticleStatus{models.ArticleArchived, models.ArticleDraft} {
+		if _, err := s.Articles.Transition(ctx, article.ID, to); !errors.Is(err, repository.ErrConflict) {
+			t.Fatalf("draft -> %s: err = %v, want ErrConflict", to, err)
+		}
+	}
+
+	mustTransition(t, s, ctx, article.ID, models.ArticlePublished)
+	if _, err := s.Articles.Transition(ctx, article.ID, models.ArticlePublished); !errors.Is(err, repository.ErrAlreadyPublished) {
+		t.Fatalf("published -> published: err = %v, want ErrAlreadyPublished", err)
+	}
+	if _, err := s.Articles.Transition(ctx, article.ID, models.ArticleInReview); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("published -> in_review: err = %v, want ErrConflict", err)
+	}
+
+	// Отклоненные переходы не попадают в историю
+	transitions, err := s.Articles.ListTransitions(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("ListTransitions: %v", err)
+	}
+	if len(transitions) != 1 {
+		t.Fatalf("got %d transitions, want 1", len(transitions))
+	}
+}
+
+func testWorkflowUnknownStatus(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	if _, err := s.Articles.Transition(context.Background(), article.ID, "deleted"); !errors.Is(err, repository.ErrInvalidInput) {
+		t.Fatalf("Transition to unknown status: err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func testWorkflowArchive(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	published := mustTransition(t, s, ctx, article.ID, models.ArticlePublished)
+	if got := mustGetPublished(t, s); len(got) != 1 {
+		t.Fatalf("GetPublished after publish = %d articles, want 1", len(got))
+	}
+
+	archived := mustTransition(t, s, ctx, article.ID, models.ArticleArchived)
+	if got := mustGetPublished(t, s); len(got) != 0 {
+		t.Fatalf("GetPublished after archive = %d articles, want 0", len(got))
+	}
+	// Время последней публикации сохраняется после снятия с публикации
+	if archived.PublishedAt == nil || !archived.PublishedAt.Equal(*published.PublishedAt) {
+		t.Fatalf("published_at after archive = %v, want %v", archived.PublishedAt, published.PublishedAt)
+	}
+
+	republished := mustTransition(t, s, ctx, article.ID, models.ArticlePublished)
+	if republished.PublishedAt == nil || republished.PublishedAt.Before(*published.PublishedAt) {
+		t.Fatalf("published_at after republish = %v", republished.PublishedAt)
+	}
+	mustTransition(t, s, ctx, article.ID, models.ArticleDraft)
+	if got := mustGetPublished(t, s); len(got) != 0 {
+		t.Fatalf("GetPublished after unpublish = %d articles, want 0", len(got))
+	}
+}
+
+func testWorkflowUnknownActor(t *testing.T, s Stores) {
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+
+	ctx := repository.WithActor(context.Background(), 999999)
+	if _, err := s.Articles.Transition(ctx, article.ID, models.ArticlePublished); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("Transition with unknown actor: err = %v, want ErrConflict", err)
+	}
+
+	got, err := s.Articles.GetByID(context.Background(), article.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Status != models.ArticleDraft || got.PublishedAt != nil {
+		t.Fatalf("status changed after failed transition: %+v", got)
+	}
+}
+
+func testWorkflowMissing(t *testing.T, s Stores) {
+	ctx := context.Background()
+	if _, err := s.Articles.Transition(ctx, 999999, models.ArticlePublished); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("Transition missing: err = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Articles.ListTransitions(ctx, 999999); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("ListTransitions missing: err = %v, want ErrNotFound", err)
+	}
+
+	author := mustCreateUser(t, s, "alice@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+	transitions, err := s.Articles.ListTransitions(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("ListTransitions: %v", err)
+	}
+	if transitions == nil || len(transitions) != 0 {
+		t.Fatalf("ListTransitions for new article = %v, want empty slice", transitions)
+	}
+}
+
+func testWorkflowCascades(t *testing.T, s Stores) {
+	ctx := context.Background()
+	author := mustCreateUser(t, s, "alice@example.com")
+	editor := mustCreateUser(t, s, "bob@example.com")
+	article := mustCreateArticle(t, s, author.ID, "T")
+	mustTransition(t, s, repository.WithActor(ctx, editor.ID), article.ID, models.ArticlePublished)
+
+	// Окончательное удаление пользователя сохраняет историю, но обнуляет ссылку на него
+	if err := s.Users.Delete(ctx, editor.ID); err != nil {
+		t.Fatalf("Delete user: %v", err)
+	}
+	mustPurge(t, s)
+	transitions, err := s.Articles.ListTransitions(ctx, article.ID)
+	if err != nil {
+		t.Fatalf("ListTransitions: %v", err)
+	}
+	if len(transitions) != 1 || transitions[0].ActorID != nil {
+		t.Fatalf("transitions after actor deletion = %+v", transitions)
+	}
+
+	if err := s.Articles.Delete(ctx, article.ID); err != nil {
+		t.Fatalf("Delete article: %v", err)
+	}
+	if _, err := s.Articles.ListTransitions(ctx, article.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("ListTransitions after article deletion: err = %v, want ErrNotFound", err)
+	}
+}
+
+func mustGetPublished(t *testing.T, s Stores) []*models.Article {
+	t.Helper()
+	articles, err := s.Articles.GetPublished(context.Background())
+	if err != nil {
+		t.Fatalf("GetPublished: %v", err)
+	}
+	return articles
+}
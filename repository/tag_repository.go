@@ -0,0 +1,372 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+)
+
+const MaxTagNameLength = 64
+
+// TagMatch задает, должна ли статья иметь любой из тегов или все сразу
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any"
+	TagMatchAll TagMatch = "all"
+)
+
+type TagUsage struct {
+	Tag          *models.Tag `json:"tag"`
+	ArticleCount int         `json:"article_count"`
+}
+
+type TagRepository struct {
+	db *sql.DB
+}
+
+func NewTagRepository(db *sql.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+// NormalizeTagName убирает лишние пробелы и проверяет длину имени тега.
+// Регистр сохраняется для отображения, сравнение имен регистронезависимо.
+func NormalizeTagName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", fmt.Errorf("%w: empty tag name", ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(name) > MaxTagNameLength {
+		return "", fmt.Errorf("%w: tag name %q exceeds %d characters", ErrInvalidInput, name, MaxTagNameLength)
+	}
+	return name, nil
+}
+
+// NormalizeTagNames нормализует имена и убирает дубликаты без учета регистра,
+// сохраняя порядок первого вхождения
+func NormalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, raw := range names {
+		name, err := NormalizeTagName(raw)
+		if err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, name)
+	}
+	return result, nil
+}
+
+func lowerAll(names []string) []string {
+	lower := make([]string, len(names))
+	for i, n := range names {
+		lower[i] = strings.ToLower(n)
+	}
+	return lower
+}
+
+func (r *TagRepository) Create(ctx context.Context, name string) (*models.Tag, error) {
+	name, err := NormalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &models.Tag{Name: name}
+	err = r.db.QueryRowContext(ctx,
+		`INSERT INTO tags (name) VALUES ($1) RETURNING id, created_at`, name,
+	).Scan(&tag.ID, &tag.CreatedAt)
+	if err != nil {
+		err = pgError(err)
+		if errors.Is(err, ErrAlreadyExists) {
+			return nil, fmt.Errorf("tag %q: %w", name, err)
+		}
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	return tag, nil
+}
+
+func (r *TagRepository) GetByName(ctx context.Context, name string) (*models.Tag, error) {
+	tag := &models.Tag{}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, name, created_at FROM tags WHERE lower(name) = lower($1)`, strings.TrimSpace(name),
+	).Scan(&tag.ID, &tag.Name, &tag.CreatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("tag")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return tag, nil
+}
+
+// List возвращает все теги с числом опубликованных статей, самые
+// используемые первыми
+func (r *TagRepository) List(ctx context.Context) ([]*TagUsage, error) {
+	query := `
+		SELECT t.id, t.name, t.created_at, COUNT(a.id)
+		FROM tags t
+		LEFT JOIN article_tags at ON at.tag_id = t.id
+		LEFT JOIN articles a ON a.id = at.article_id
+			AND a.status = 'published' AND a.deleted_at IS NULL
+		GROUP BY t.id
+		ORDER BY COUNT(a.id) DESC, lower(t.name)
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	usages := []*TagUsage{}
+	for rows.Next() {
+		u := &TagUsage{Tag: &models.Tag{}}
+		if err := rows.Scan(&u.Tag.ID, &u.Tag.Name, &u.Tag.CreatedAt, &u.ArticleCount); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		usages = append(usages, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return usages, nil
+}
+
+func (r *TagRepository) GetArticleTags(ctx context.Context, articleID int) ([]*models.Tag, error) {
+	query := `
+		SELECT t.id, t.name, t.created_at
+		FROM tags t
+		JOIN article_tags at ON at.tag_id = t.id
+		WHERE at.article_id = $1
+		ORDER BY lower(t.name)
+	`
+	return r.queryTags(ctx, query, articleID)
+}
+
+func (r *TagRepository) queryTags(ctx context.Context, query string, args ...any) ([]*models.Tag, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []*models.Tag{}
+	for rows.Next() {
+		tag := &models.Tag{}
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return tags, nil
+}
+
+// SetArticleTags заменяет набор тегов статьи. Отсутствующие теги создаются.
+// Все изменения выполняются в одной транзакции.
+func (r *TagRepository) SetArticleTags(ctx context.Context, articleID int, names []string) ([]*models.Tag, error) {
+	names, err := NormalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Блокируем статью, чтобы параллельные SetArticleTags не перемешали наборы
+	var id int
+	err = tx.QueryRowContext(ctx, `SELECT id FROM articles WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, articleID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("article")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock article: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tags (name)
+		SELECT unnest($1::varchar[])
+		ON CONFLICT ((lower(name))) DO NOTHING
+	`, pq.Array(names))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tags: %w", pgError(err))
+	}
+
+	lower := pq.Array(lowerAll(names))
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM article_tags
+		WHERE article_id = $1
+		  AND tag_id NOT IN (SELECT id FROM tags WHERE lower(name) = ANY($2::text[]))
+	`, articleID, lower)
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove article tags: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO article_tags (article_id, tag_id)
+		SELECT $1, id FROM tags WHERE lower(name) = ANY($2::text[])
+		ON CONFLICT DO NOTHING
+	`, articleID, lower)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add article tags: %w", pgError(err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", pgError(err))
+	}
+
+	return r.GetArticleTags(ctx, articleID)
+}
+
+// GetArticlesByTags возвращает статьи, у которых есть любой (TagMatchAny)
+// или каждый (TagMatchAll) из перечисленных тегов, новые первыми
+func (r *TagRepository) GetArticlesByTags(ctx context.Context, names []string, match TagMatch) ([]*models.Article, error) {
+	names, err := NormalizeTagNames(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return []*models.Article{}, nil
+	}
+
+	var having string
+	switch match {
+	case "", TagMatchAny:
+	case TagMatchAll:
+		having = ` HAVING COUNT(DISTINCT at.tag_id) = cardinality($1::text[])`
+	default:
+		return nil, fmt.Errorf("%w: unknown tag match %q", ErrInvalidQuery, match)
+	}
+
+	query := `
+		SELECT a.id, a.title, a.slug, a.content, a.author_id, a.status, a.published_at, a.publish_at, a.unpublish_at, a.views, a.created_at, a.updated_at
+		FROM articles a
+		JOIN article_tags at ON at.article_id = a.id
+		JOIN tags t ON t.id = at.tag_id
+		WHERE lower(t.name) = ANY($1::text[]) AND a.deleted_at IS NULL
+		GROUP BY a.id` + having + `
+		ORDER BY a.created_at DESC, a.id DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(lowerAll(names)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query articles: %w", err)
+	}
+	defer rows.Close()
+
+	articles, err := scanArticles(rows)
+	if err != nil {
+		return nil, err
+	}
+	if articles == nil {
+		articles = []*models.Article{}
+	}
+	return articles, nil
+}
+
+// Rename меняет имя тега; связи со статьями сохраняются. Если имя занято
+// другим тегом, возвращается ErrAlreadyExists — такие теги нужно объединять через Merge.
+func (r *TagRepository) Rename(ctx context.Context, id int, newName string) (*models.Tag, error) {
+	newName, err := NormalizeTagName(newName)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &models.Tag{}
+	err = r.db.QueryRowContext(ctx,
+		`UPDATE tags SET name = $1 WHERE id = $2 RETURNING id, name, created_at`, newName, id,
+	).Scan(&tag.ID, &tag.Name, &tag.CreatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("tag")
+	}
+	if err != nil {
+		err = pgError(err)
+		if errors.Is(err, ErrAlreadyExists) {
+			return nil, fmt.Errorf("tag %q: %w", newName, err)
+		}
+		return nil, fmt.Errorf("failed to rename tag: %w", err)
+	}
+	return tag, nil
+}
+
+// Merge переносит все статьи тега sourceID на targetID и удаляет sourceID
+func (r *TagRepository) Merge(ctx context.Context, sourceID, targetID int) error {
+	if sourceID == targetID {
+		return fmt.Errorf("%w: cannot merge tag into itself", ErrInvalidInput)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Блокируем оба тега в порядке id, чтобы встречные Merge не дали deadlock
+	var locked int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM (
+			SELECT id FROM tags WHERE id IN ($1, $2) ORDER BY id FOR UPDATE
+		) t
+	`, sourceID, targetID).Scan(&locked)
+	if err != nil {
+		return fmt.Errorf("failed to lock tags: %w", pgError(err))
+	}
+	if locked != 2 {
+		return notFound("tag")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO article_tags (article_id, tag_id)
+		SELECT article_id, $2 FROM article_tags WHERE tag_id = $1
+		ON CONFLICT DO NOTHING
+	`, sourceID, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to move article tags: %w", pgError(err))
+	}
+
+	// Связи исходного тега удаляются каскадно
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, sourceID); err != nil {
+		return fmt.Errorf("failed to delete tag: %w", pgError(err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
+	}
+	return nil
+}
+
+func (r *TagRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return notFound("tag")
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"time"
+)
+
+// Удаление пользователей и статей мягкое: строка получает deleted_at и
+// перестает быть видна всем методам чтения. Из корзины ее можно восстановить
+// (Restore) или удалить окончательно очисткой по сроку хранения (Purge).
+
+// checkActiveUser не дает сослаться на пользователя из корзины: внешний ключ
+// такую ссылку пропускает. FOR SHARE не дает переместить пользователя в корзину
+// до конца транзакции. Отсутствующего пользователя отклоняет внешний ключ.
+func checkActiveUser(ctx context.Context, tx *sql.Tx, id int, role string) error {
+	var deleted bool
+	err := tx.QueryRowContext(ctx, `SELECT deleted_at IS NOT NULL FROM users WHERE id = $1 FOR SHARE`, id).Scan(&deleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check %s: %w", role, err)
+	}
+	if deleted {
+		return fmt.Errorf("%w: %s %d is in trash", ErrConflict, role, id)
+	}
+	return nil
+}
+
+// retentionSeconds переводит срок хранения в секунды для сравнения с NOW()
+// на стороне базы: deleted_at хранится без часового пояса
+func retentionSeconds(retention time.Duration) (float64, error) {
+	if retention < 0 {
+		return 0, fmt.Errorf("%w: negative retention", ErrInvalidInput)
+	}
+	return retention.Seconds(), nil
+}
+
+// Delete перемещает пользователя в корзину вместе со всеми его статьями
+func (r *UserRepository) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Остальные колонки не меняются, поэтому RETURNING дает снимок до удаления
+	before, err := scanUser(tx.QueryRowContext(ctx, `
+		UPDATE users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id, email, name, role, created_at, updated_at
+	`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound("user")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE articles SET deleted_at = NOW(), deleted_with_author = true
+		WHERE author_id = $1 AND deleted_at IS NULL
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete user articles: %w", err)
+	}
+	if err := recordAudit(ctx, tx, models.AuditDelete, models.AuditUserEntity, id, before, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
+	}
+	return nil
+}
+
+// Restore возвращает пользователя из корзины вместе со статьями, которые были
+// удалены вместе с ним. Статьи, удаленные отдельно до этого, остаются в корзине.
+func (r *UserRepository) Restore(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	after, err := scanUser(tx.QueryRowContext(ctx, `
+		UPDATE users SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING id, email, name, role, created_at, updated_at
+	`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound("deleted user")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore user: %w", pgError(err))
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE articles SET deleted_at = NULL, deleted_with_author = false
+		WHERE author_id = $1 AND deleted_with_author
+	`, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore user articles: %w", err)
+	}
+	if err := recordAudit(ctx, tx, models.AuditRestore, models.AuditUserEntity, id, nil, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
+	}
+	return nil
+}
+
+// ListTrash возвращает пользователей в корзине, недавно удаленные первыми
+func (r *UserRepository) ListTrash(ctx context.Context) ([]*models.User, error) {
+	query := `
+		SELECT id, email, name, role, created_at, updated_at, deleted_at
+		FROM users
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []*models.User{}
+	for rows.Next() {
+		user := &models.User{}
+		err := rows.Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return users, nil
+}
+
+// Purge окончательно удаляет пользователей, пролежавших в корзине дольше
+// retention; их статьи и комментарии удаляются каскадно
+func (r *UserRepository) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	seconds, err := retentionSeconds(retention)
+	if err != nil {
+		return 0, err
+	}
+	result, err := r.db.ExecContext(ctx, auditPurged(`
+		DELETE FROM users
+		WHERE deleted_at <= NOW() - make_interval(secs => $1)
+		RETURNING id
+	`, models.AuditUserEntity), purgeArgs(ctx, seconds)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge users: %w", pgError(err))
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(n), nil
+}
+
+// Delete перемещает статью в корзину
+func (r *ArticleRepository) Delete(ctx context.Context, id int) error {
+	query := `UPDATE articles SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := scanArticle(tx.QueryRowContext(ctx, query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound("article")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete article: %w", err)
+	}
+	if err := recordAudit(ctx, tx, models.AuditDelete, models.AuditArticleEntity, id, before, nil); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
+	}
+	return nil
+}
+
+// Restore возвращает статью из корзины. Статью автора, который сам в корзине,
+// восстановить нельзя: сначала восстанавливается пользователь.
+func (r *ArticleRepository) Restore(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var authorID int
+	err = tx.QueryRowContext(ctx, `
+		SELECT author_id FROM articles WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE
+	`, id).Scan(&authorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound("deleted article")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get article: %w", err)
+	}
+	if err := checkActiveUser(ctx, tx, authorID, "author"); err != nil {
+		return err
+	}
+
+	after, err := scanArticle(tx.QueryRowContext(ctx, `
+		UPDATE articles SET deleted_at = NULL, deleted_with_author = false WHERE id = $1
+		RETURNING id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at
+	`, id))
+	if err != nil {
+		return fmt.Errorf("failed to restore article: %w", err)
+	}
+	if err := recordAudit(ctx, tx, models.AuditRestore, models.AuditArticleEntity, id, nil, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
+	}
+	return nil
+}
+
+// ListTrash возвращает статьи в корзине, недавно удаленные первыми
+func (r *ArticleRepository) ListTrash(ctx context.Context) ([]*models.Article, error) {
+	query := `
+		SELECT id, title, slug, content, author_id, status, published_at, publish_at, unpublish_at, views, created_at, updated_at, deleted_at
+		FROM articles
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query articles: %w", err)
+	}
+	defer rows.Close()
+
+	articles := []*models.Article{}
+	for rows.Next() {
+		article := &models.Article{}
+		err := rows.Scan(
+			&article.ID,
+			&article.Title,
+			&article.Slug,
+			&article.Content,
+			&article.AuthorID,
+			&article.Status,
+			&article.PublishedAt,
+			&article.PublishAt,
+			&article.UnpublishAt,
+			&article.Views,
+			&article.CreatedAt,
+			&article.UpdatedAt,
+			&article.DeletedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan article: %w", err)
+		}
+		articles = append(articles, article)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return articles, nil
+}
+
+// Purge окончательно удаляет статьи, пролежавшие в корзине дольше retention,
+// вместе с комментариями, ревизиями и историей статусов
+func (r *ArticleRepository) Purge(ctx context.Context, retention time.Duration) (int, error) {
+	seconds, err := retentionSeconds(retention)
+	if err != nil {
+		return 0, err
+	}
+	result, err := r.db.ExecContext(ctx, auditPurged(`
+		DELETE FROM articles
+		WHERE deleted_at <= NOW() - make_interval(secs => $1)
+		RETURNING id
+	`, models.AuditArticleEntity), purgeArgs(ctx, seconds)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge articles: %w", pgError(err))
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(n), nil
+}
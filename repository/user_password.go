@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"go-articles-app/models"
+	"time"
+)
+
+// CreateWithPassword создает пользователя вместе с хэшем пароля
+func (r *UserRepository) CreateWithPassword(ctx context.Context, user *models.User, passwordHash string) error {
+	if err := defaultRole(user); err != nil {
+		return err
+	}
+	query := `INSERT INTO users (email, name, role, password_hash, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $5)
+	RETURNING id
+	`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	err = tx.QueryRowContext(ctx, query, user.Email, user.Name, user.Role, passwordHash, now).Scan(&user.ID)
+	if err != nil {
+		err = pgError(err)
+		if errors.Is(err, ErrAlreadyExists) {
+			return fmt.Errorf("user with email %s: %w", user.Email, err)
+		}
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	if err := recordAudit(ctx, tx, models.AuditCreate, models.AuditUserEntity, user.ID, nil, user); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
+	}
+	return nil
+}
+
+// GetCredentials возвращает пользователя и хэш его пароля одним запросом.
+// Пустой хэш означает, что пароль не задан.
+func (r *UserRepository) GetCredentials(ctx context.Context, email string) (*models.User, string, error) {
+	query := `
+		SELECT id, email, name, role, created_at, updated_at, COALESCE(password_hash, '')
+		FROM users
+		WHERE email = $1 AND deleted_at IS NULL
+	`
+
+	user := &models.User{}
+	var hash string
+	err := r.db.QueryRowContext(ctx, query, email).Scan(
+		&user.ID,
+		&user.Email,
+		&user.Name,
+		&user.Role,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&hash,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", notFound("user")
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get credentials: %w", err)
+	}
+	return user, hash, nil
+}
+
+// SetPasswordHash заменяет хэш пароля пользователя. В журнал аудита попадает
+// только факт смены, без хэшей.
+func (r *UserRepository) SetPasswordHash(ctx context.Context, id int, passwordHash string) error {
+	query := `UPDATE users SET password_hash = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, passwordHash, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to set password: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return notFound("user")
+	}
+	if err := recordAudit(ctx, tx, models.AuditSetPassword, models.AuditUserEntity, id, nil, nil); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
+	}
+	return nil
+}
@@ -3,9 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"go-articles-app/models"
-	"strings"
 	"time"
 )
 
@@ -18,38 +18,55 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 }
 
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
-	query := `INSERT INTO users (email, name, created_at, updated_at)
-	VALUES ($1, $2, $3, $4)
+	if err := defaultRole(user); err != nil {
+		return err
+	}
+	query := `INSERT INTO users (email, name, role, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5)
 	RETURNING id
 	`
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	now := time.Now()
-	err := r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx,
 		query,
 		user.Email,
 		user.Name,
+		user.Role,
 		now,
 		now,
 	).Scan(&user.ID)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") {
-			return fmt.Errorf("user with email %s already exists", user.Email)
+		err = pgError(err)
+		if errors.Is(err, ErrAlreadyExists) {
+			return fmt.Errorf("user with email %s: %w", user.Email, err)
 		}
 		return fmt.Errorf("failed to create user: %w", err)
 	}
 
 	user.CreatedAt = now
 	user.UpdatedAt = now
+	if err := recordAudit(ctx, tx, models.AuditCreate, models.AuditUserEntity, user.ID, nil, user); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
+	}
 	return nil
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
 	query := `
-		SELECT id, email, name, created_at, updated_at
+		SELECT id, email, name, role, created_at, updated_at
 		FROM users
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	user := &models.User{}
@@ -57,34 +74,36 @@ func (r *UserRepository) GetByID(ctx context.Context, id int) (*models.User, err
 		&user.ID,
 		&user.Email,
 		&user.Name,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
 
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("article not found")
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("user")
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get article: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
 	return user, nil
 }
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
-	query := `SELECT id, email, name, created_at, updated_at FROM users WHERE email = $1`
+	query := `SELECT id, email, name, role, created_at, updated_at FROM users WHERE email = $1 AND deleted_at IS NULL`
 	user := &models.User{}
 
 	err := r.db.QueryRowContext(ctx, query, email).Scan(
 		&user.ID,
 		&user.Email,
 		&user.Name,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
 
-	if err == sql.ErrNoRows {
-		return nil, nil 
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("user")
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
@@ -94,7 +113,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 }
 
 func (r *UserRepository) GetAll(ctx context.Context) ([]*models.User, error) {
-	query := `SELECT id, email, name, created_at, updated_at FROM users ORDER BY id`
+	query := `SELECT id, email, name, role, created_at, updated_at FROM users WHERE deleted_at IS NULL ORDER BY id`
 
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
@@ -109,6 +128,7 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]*models.User, error) {
 			&user.ID,
 			&user.Email,
 			&user.Name,
+			&user.Role,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -126,46 +146,119 @@ func (r *UserRepository) GetAll(ctx context.Context) ([]*models.User, error) {
 	return users, nil
 }
 
+// Update сохраняет email и имя пользователя; роль меняется только через SetRole
 func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
-	query := `UPDATE users SET email = $1, name = $2, updated_at = $3 WHERE id= $4`
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := lockUser(ctx, tx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE users SET email = $1, name = $2, updated_at = $3 WHERE id = $4`
 
 	user.UpdatedAt = time.Now()
 
-	result, err := r.db.ExecContext(ctx, query, user.Email, user.Name, user.UpdatedAt, user.ID)
+	_, err = tx.ExecContext(ctx, query, user.Email, user.Name, user.UpdatedAt, user.ID)
 
 	if err != nil {
+		err = pgError(err)
+		if errors.Is(err, ErrAlreadyExists) {
+			return fmt.Errorf("user with email %s: %w", user.Email, err)
+		}
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	after := *before
+	after.Email, after.Name, after.UpdatedAt = user.Email, user.Name, user.UpdatedAt
+	if err := recordAudit(ctx, tx, models.AuditUpdate, models.AuditUserEntity, user.ID, before, &after); err != nil {
+		return err
 	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("user not found")
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
 	}
-
 	return nil
 }
 
-func (r *UserRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM users WHERE id = $1`
+// lockUser читает пользователя не из корзины и блокирует строку до конца транзакции
+func lockUser(ctx context.Context, tx *sql.Tx, id int) (*models.User, error) {
+	user, err := scanUser(tx.QueryRowContext(ctx, `
+		SELECT id, email, name, role, created_at, updated_at
+		FROM users
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound("user")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
 
-	result, err := r.db.ExecContext(ctx, query, id)
+// scanUser читает пользователя из строки с колонками
+// id, email, name, role, created_at, updated_at
+func scanUser(row rowScanner) (*models.User, error) {
+	user := &models.User{}
+	err := row.Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
 
+// GetAllPage возвращает страницу пользователей в порядке (created_at, id)
+func (r *UserRepository) GetAllPage(ctx context.Context, page PageRequest) (*Page[*models.User], error) {
+	cursor, err := decodePageCursor(page)
 	if err != nil {
-		return fmt.Errorf("failed to delete user: %w", err)
+		return nil, err
+	}
+	size := page.PageSize()
+
+	query := `SELECT id, email, name, role, created_at, updated_at FROM users WHERE deleted_at IS NULL`
+	var args []any
+	if cursor != nil {
+		query += ` AND (created_at, id) > ($1, $2)`
+		args = append(args, cursor.CreatedAt, cursor.ID)
 	}
+	query += fmt.Sprintf(` ORDER BY created_at, id LIMIT $%d`, len(args)+1)
+	args = append(args, size+1)
 
-	rowsAffected, err := result.RowsAffected()
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return nil, fmt.Errorf("failed to query users: %w", err)
 	}
+	defer rows.Close()
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("user not found")
+	var users []*models.User
+	for rows.Next() {
+		user := &models.User{}
+		err := rows.Scan(
+			&user.ID,
+			&user.Email,
+			&user.Name,
+			&user.Role,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
 	}
 
-	return nil
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return NewPage(users, size, UserCursor), nil
+}
+
+func UserCursor(u *models.User) Cursor {
+	return Cursor{CreatedAt: u.CreatedAt, ID: u.ID}
 }
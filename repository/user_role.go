@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/models"
+	"time"
+)
+
+// defaultRole подставляет models.DefaultRole пустой роли и отклоняет неизвестную
+func defaultRole(user *models.User) error {
+	if user.Role == "" {
+		user.Role = models.DefaultRole
+	}
+	if !user.Role.Valid() {
+		return fmt.Errorf("%w: unknown role %q", ErrInvalidInput, user.Role)
+	}
+	return nil
+}
+
+// SetRole меняет роль пользователя. Update роль не трогает, чтобы ее нельзя
+// было поменять вместе с профилем.
+func (r *UserRepository) SetRole(ctx context.Context, id int, role models.Role) error {
+	if !role.Valid() {
+		return fmt.Errorf("%w: unknown role %q", ErrInvalidInput, role)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := lockUser(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE users SET role = $1, updated_at = $2 WHERE id = $3`
+	if _, err := tx.ExecContext(ctx, query, role, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to set role: %w", pgError(err))
+	}
+
+	err = recordAudit(ctx, tx, models.AuditSetRole, models.AuditUserEntity, id, AuditRole{before.Role}, AuditRole{role})
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", pgError(err))
+	}
+	return nil
+}
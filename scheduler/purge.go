@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	DefaultPurgeInterval  = time.Hour
+	DefaultTrashRetention = 30 * 24 * time.Hour
+)
+
+// TrashStore — часть repository.UserStore и repository.ArticleStore, нужная
+// для очистки корзины
+type TrashStore interface {
+	Purge(ctx context.Context, retention time.Duration) (int, error)
+}
+
+// Purger раз в Interval окончательно удаляет пользователей и статьи, пролежавшие
+// в корзине дольше retention, а с WithSessions — и давно завершенные сессии
+type Purger struct {
+	users     TrashStore
+	articles  TrashStore
+	sessions  TrashStore
+	interval  time.Duration
+	retention time.Duration
+}
+
+// NewPurger создает очистку корзины; нулевые interval и retention заменяются
+// значениями по умолчанию
+func NewPurger(users, articles TrashStore, interval, retention time.Duration) *Purger {
+	if interval <= 0 {
+		interval = DefaultPurgeInterval
+	}
+	if retention <= 0 {
+		retention = DefaultTrashRetention
+	}
+	return &Purger{users: users, articles: articles, interval: interval, retention: retention}
+}
+
+// WithSessions добавляет к очистке сессии, истекшие или отозванные дольше
+// retention назад
+func (p *Purger) WithSessions(sessions TrashStore) *Purger {
+	p.sessions = sessions
+	return p
+}
+
+// Run очищает корзину сразу и затем по таймеру, пока не отменен ctx
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		users, articles, err := p.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("purger: %v", err)
+		}
+		if users+articles > 0 {
+			log.Printf("purger: removed %d users, %d articles", users, articles)
+		}
+		if p.sessions != nil {
+			if n, err := p.sessions.Purge(ctx, p.retention); err != nil && ctx.Err() == nil {
+				log.Printf("purger: purge sessions: %v", err)
+			} else if n > 0 {
+				log.Printf("purger: removed %d sessions", n)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce удаляет сначала пользователей (их статьи уходят каскадом), затем
+// оставшиеся статьи
+func (p *Purger) RunOnce(ctx context.Context) (users, articles int, err error) {
+	users, err = p.users.Purge(ctx, p.retention)
+	if err != nil {
+		return 0, 0, fmt.Errorf("purge users: %w", err)
+	}
+	articles, err = p.articles.Purge(ctx, p.retention)
+	if err != nil {
+		return users, 0, fmt.Errorf("purge articles: %w", err)
+	}
+	return users, articles, nil
+}
@@ -0,0 +1,88 @@
+// Package scheduler выполняет фоновые задачи: наступившие отложенные публикации
+// и снятия статей с публикации, очистку корзины.
+package scheduler
+
+import (
+	"context"
+	"go-articles-app/repository"
+	"log"
+	"time"
+)
+
+const (
+	DefaultInterval  = 30 * time.Second
+	DefaultBatchSize = 100
+)
+
+// Store — часть repository.ArticleStore, нужная планировщику
+type Store interface {
+	ProcessDueSchedules(ctx context.Context, limit int) (repository.ScheduleRun, error)
+}
+
+// Scheduler раз в Interval обрабатывает расписание пачками по BatchSize строк.
+// Несколько экземпляров приложения могут работать одновременно: PostgreSQL-
+// реализация захватывает строки через FOR UPDATE SKIP LOCKED.
+type Scheduler struct {
+	store     Store
+	interval  time.Duration
+	batchSize int
+}
+
+// New создает планировщик; нулевые interval и batchSize заменяются значениями по
+// умолчанию. batchSize не больше repository.MaxPageSize: хранилище все равно
+// обрезает limit, и RunOnce принял бы полную пачку за последнюю.
+func New(store Store, interval time.Duration, batchSize int) *Scheduler {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	batchSize = min(batchSize, repository.MaxPageSize)
+	return &Scheduler{store: store, interval: interval, batchSize: batchSize}
+}
+
+// Run обрабатывает расписание сразу и затем по таймеру, пока не отменен ctx.
+// Ошибки прохода логируются, следующий проход выполняется по расписанию.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		run, err := s.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("scheduler: %v", err)
+		}
+		if run.Total() > 0 {
+			log.Printf("scheduler: published %d, unpublished %d, skipped %d", run.Published, run.Unpublished, run.Skipped)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce обрабатывает пачки, пока не останется наступивших строк, и возвращает
+// суммарный итог
+func (s *Scheduler) RunOnce(ctx context.Context) (repository.ScheduleRun, error) {
+	var total repository.ScheduleRun
+	for {
+		run, err := s.store.ProcessDueSchedules(ctx, s.batchSize)
+		if err != nil {
+			return total, err
+		}
+		total.Published += run.Published
+		total.Unpublished += run.Unpublished
+		total.Skipped += run.Skipped
+
+		if run.Total() < s.batchSize {
+			return total, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
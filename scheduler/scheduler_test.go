@@ -0,0 +1,130 @@
+package scheduler_test
+
+import (
+	"context"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"go-articles-app/repository/memory"
+	"go-articles-app/scheduler"
+	"testing"
+	"time"
+)
+
+func TestRunOnceDrainsBatches(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	author := &models.User{Name: "Alice", Email: "alice@example.com"}
+	if err := store.Users().Create(ctx, author); err != nil {
+		t.Fatalf("Create user: %v", err)
+	}
+
+	articles := store.Articles()
+	for i := 0; i < 5; i++ {
+		article := &models.Article{Title: "T", Content: "C", AuthorID: author.ID}
+		if err := articles.Create(ctx, article); err != nil {
+			t.Fatalf("Create article: %v", err)
+		}
+		if _, err := articles.Schedule(ctx, article.ID, repository.SchedulePublish, time.Now().Add(-time.Minute)); err != nil {
+			t.Fatalf("Schedule: %v", err)
+		}
+	}
+
+	run, err := scheduler.New(articles, time.Minute, 2).RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if run.Published != 5 {
+		t.Fatalf("published %d articles, want 5", run.Published)
+	}
+	published, err := articles.GetPublished(ctx)
+	if err != nil {
+		t.Fatalf("GetPublished: %v", err)
+	}
+	if len(published) != 5 {
+		t.Fatalf("GetPublished = %d articles, want 5", len(published))
+	}
+}
+
+// Пачка больше repository.MaxPageSize обрезается хранилищем; RunOnce не должен
+// принимать такую полную пачку за последнюю
+func TestRunOnceBatchAboveMaxPageSize(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	author := &models.User{Name: "Alice", Email: "alice@example.com"}
+	if err := store.Users().Create(ctx, author); err != nil {
+		t.Fatalf("Create user: %v", err)
+	}
+
+	articles := store.Articles()
+	due := repository.MaxPageSize + 5
+	for i := 0; i < due; i++ {
+		article := &models.Article{Title: "T", Content: "C", AuthorID: author.ID}
+		if err := articles.Create(ctx, article); err != nil {
+			t.Fatalf("Create article: %v", err)
+		}
+		if _, err := articles.Schedule(ctx, article.ID, repository.SchedulePublish, time.Now().Add(-time.Minute)); err != nil {
+			t.Fatalf("Schedule: %v", err)
+		}
+	}
+
+	run, err := scheduler.New(articles, time.Minute, 5*repository.MaxPageSize).RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if run.Published != due {
+		t.Fatalf("published %d articles, want %d", run.Published, due)
+	}
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		scheduler.New(memory.NewStore().Articles(), time.Hour, 0).Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
+
+func TestPurgerRunOnce(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users, articles := store.Users(), store.Articles()
+
+	kept := &models.User{Name: "Alice", Email: "alice@example.com"}
+	gone := &models.User{Name: "Bob", Email: "bob@example.com"}
+	for _, u := range []*models.User{kept, gone} {
+		if err := users.Create(ctx, u); err != nil {
+			t.Fatalf("Create user: %v", err)
+		}
+	}
+	for _, authorID := range []int{kept.ID, kept.ID, gone.ID} {
+		if err := articles.Create(ctx, &models.Article{Title: "T", Content: "C", AuthorID: authorID}); err != nil {
+			t.Fatalf("Create article: %v", err)
+		}
+	}
+	if err := users.Delete(ctx, gone.ID); err != nil {
+		t.Fatalf("Delete user: %v", err)
+	}
+	if err := articles.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete article: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	u, a, err := scheduler.NewPurger(users, articles, time.Hour, time.Nanosecond).RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if u != 1 || a != 1 {
+		t.Fatalf("purged %d users, %d articles, want 1, 1", u, a)
+	}
+	if trash, _ := articles.ListTrash(ctx); len(trash) != 0 {
+		t.Fatalf("ListTrash = %d articles after purge, want 0", len(trash))
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-articles-app/fake"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"io"
+	"time"
+)
+
+// Значения seed по умолчанию
+const (
+	defaultSeedUsers    = 100
+	defaultSeedArticles = 1000
+	defaultSeedBatch    = 1000
+)
+
+// runSeed: seed [-users N] [-articles M] [-seed S] [-until T] [-span D] [-batch B]
+// — генерирует пользователей и статьи пакетом fake и загружает их пачками
+// через COPY, не удаляя существующие данные. Одинаковые -seed, -until и
+// объемы дают одинаковые данные; без -until даты отсчитываются от текущего
+// момента.
+func runSeed(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlagSet("seed")
+	users := fs.Int("users", defaultSeedUsers, "users to create")
+	articles := fs.Int("articles", defaultSeedArticles, "articles to create")
+	seed := fs.Uint64("seed", 1, "random seed: the same seed, -until and volumes produce the same data")
+	until := fs.String("until", "", "latest timestamp, RFC 3339 or YYYY-MM-DD (default now)")
+	span := fs.Duration("span", fake.DefaultSpan, "how far back before -until timestamps go")
+	batch := fs.Int("batch", defaultSeedBatch, "rows per COPY batch")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("seed: unexpected argument %q", fs.Arg(0))
+	}
+	if *users < 0 || *articles < 0 || *batch <= 0 || *span <= 0 {
+		return errors.New("seed: -users and -articles must not be negative, -batch and -span must be positive")
+	}
+	if *articles > 0 && *users == 0 {
+		return errors.New("seed: articles need authors, set -users to at least 1")
+	}
+	end, err := parseUntil(*until)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	g := fake.New(fake.Options{Seed: *seed, Until: end, Span: *span})
+	err = inBatches(c.stderr, "users", *users, *batch, func(n int) error {
+		return c.bulk.InsertUsers(ctx, g.Users(n))
+	})
+	if errors.Is(err, repository.ErrAlreadyExists) {
+		return fmt.Errorf("seed: users of seed %d already exist, choose another -seed or reset the database: %w", *seed, err)
+	}
+	if err != nil {
+		return fmt.Errorf("seed: %w", err)
+	}
+
+	buf := make([]*models.Article, 0, min(*batch, *articles))
+	err = inBatches(c.stderr, "articles", *articles, *batch, func(n int) error {
+		buf = buf[:0]
+		for range n {
+			buf = append(buf, g.Article())
+		}
+		return c.bulk.InsertArticles(ctx, buf)
+	})
+	if err != nil {
+		return fmt.Errorf("seed: %w", err)
+	}
+
+	fmt.Fprintf(c.stdout, "Created %d users and %d articles in %s (seed %d)\n",
+		*users, *articles, time.Since(start).Round(time.Millisecond), *seed)
+	return nil
+}
+
+// parseUntil разбирает -until; пустая строка — текущий момент
+func parseUntil(s string) (time.Time, error) {
+	if s == "" {
+		return time.Now(), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("seed: -until %q is neither RFC 3339 nor YYYY-MM-DD", s)
+	}
+	return t, nil
+}
+
+// inBatches вызывает insert пачками по batch строк, пока не наберется total,
+// и примерно каждые 10% пишет прогресс в w
+func inBatches(w io.Writer, what string, total, batch int, insert func(n int) error) error {
+	for done := 0; done < total; {
+		n := min(batch, total-done)
+		if err := insert(n); err != nil {
+			return fmt.Errorf("%s %d-%d: %w", what, done+1, done+n, err)
+		}
+		prev := done
+		done += n
+		if done == total || done*10/total != prev*10/total {
+			fmt.Fprintf(w, "%s: %d/%d\n", what, done, total)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"go-articles-app/api"
+	"go-articles-app/auth"
+	"go-articles-app/repository"
+	"go-articles-app/scheduler"
+	"log"
+	"net/http"
+	"time"
+)
+
+// runServe запускает HTTP-сервер. Адрес и параметры сайта берутся из cfg,
+// флаги команды их переопределяют. Ключ подписи JWT флагом не передается,
+// чтобы не попасть в список процессов: он задается только через config.
+func runServe(ctx context.Context, c *cli, args []string) error {
+	database, cfg := c.db, c.cfg.Server
+	fs := c.newFlagSet("serve")
+	addr := fs.String("addr", cfg.Addr, "HTTP listen address")
+	scheduleInterval := fs.Duration("schedule-interval", scheduler.DefaultInterval, "how often to run scheduled publishing (0 disables)")
+	scheduleBatch := fs.Int("schedule-batch", scheduler.DefaultBatchSize, "articles claimed per scheduler batch (at most 100)")
+	purgeInterval := fs.Duration("purge-interval", scheduler.DefaultPurgeInterval, "how often to purge expired trash (0 disables)")
+	trashRetention := fs.Duration("trash-retention", scheduler.DefaultTrashRetention, "how long deleted users and articles stay restorable")
+	siteURL := fs.String("site-url", cmp.Or(cfg.SiteURL, api.DefaultSiteURL), "public site URL used in feed links and IDs")
+	siteTitle := fs.String("site-title", cmp.Or(cfg.SiteTitle, api.DefaultSiteTitle), "site title used in feeds")
+	sessionTTL := fs.Duration("session-ttl", auth.DefaultSessionTTL, "maximum session lifetime")
+	sessionIdle := fs.Duration("session-idle-timeout", auth.DefaultIdleTimeout, "session expiry after inactivity")
+	accessTTL := fs.Duration("access-ttl", auth.DefaultAccessTTL, "JWT access token lifetime (JWTs are issued when JWT_SECRET is set)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	users := repository.NewUserRepository(database)
+	articles := repository.NewArticleRepository(database)
+	sessions := repository.NewSessionRepository(database)
+	handler := api.NewServer(api.Stores{
+		Users:      users,
+		Articles:   articles,
+		Tags:       repository.NewTagRepository(database),
+		Categories: repository.NewCategoryRepository(database),
+		Comments:   repository.NewCommentRepository(database),
+		Sessions:   sessions,
+		Audit:      repository.NewAuditRepository(database),
+	}, api.Config{
+		SiteURL:   *siteURL,
+		SiteTitle: *siteTitle,
+		Sessions: auth.SessionConfig{
+			TTL:         *sessionTTL,
+			IdleTimeout: *sessionIdle,
+			JWTKey:      []byte(cfg.JWTSecret),
+			AccessTTL:   *accessTTL,
+			Issuer:      *siteURL,
+		},
+	})
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      15 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	if *scheduleInterval > 0 {
+		go scheduler.New(articles, *scheduleInterval, *scheduleBatch).Run(ctx)
+	}
+	if *purgeInterval > 0 {
+		go scheduler.NewPurger(users, articles, *purgeInterval, *trashRetention).WithSessions(sessions).Run(ctx)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("🚀 Listening on %s", *addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
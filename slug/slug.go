@@ -0,0 +1,109 @@
+// Package slug строит из заголовков статей человекочитаемые идентификаторы
+// для URL: кириллица транслитерируется латиницей, остальное сводится к
+// строчным латинским буквам, цифрам и дефисам.
+package slug
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MaxLength — предельная длина основы слага без числового суффикса
+const MaxLength = 80
+
+// Fallback — основа слага для заголовка без букв и цифр
+const Fallback = "article"
+
+// translit — транслитерация строчных букв русского алфавита (и нескольких
+// украинских) в духе таблиц для загранпаспортов, без диакритики
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// Make строит основу слага из заголовка: "Привет, мир!" → "privet-mir".
+// Буквы и цифры вне латиницы и кириллицы, пробелы и знаки препинания
+// становятся разделителями; длинные слаги обрезаются по границе слова.
+func Make(title string) string {
+	return MakeOr(title, Fallback)
+}
+
+// MakeOr — Make с другой основой для текста без букв и цифр
+func MakeOr(title, fallback string) string {
+	var b strings.Builder
+	pendingDash := false
+	for _, r := range strings.ToLower(title) {
+		var part string
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			part = string(r)
+		default:
+			tr, ok := translit[r]
+			if !ok {
+				pendingDash = true
+				continue
+			}
+			// Твердый и мягкий знаки пропадают, не разрывая слово
+			if tr == "" {
+				continue
+			}
+			part = tr
+		}
+		if pendingDash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingDash = false
+		b.WriteString(part)
+	}
+
+	s := b.String()
+	if len(s) > MaxLength {
+		s = s[:MaxLength]
+		if i := strings.LastIndexByte(s, '-'); i > 0 {
+			s = s[:i]
+		}
+	}
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+// Unique возвращает base, если он свободен, иначе первый свободный вариант
+// base-2, base-3, ...
+func Unique(base string, taken func(string) bool) string {
+	if !taken(base) {
+		return base
+	}
+	for n := 2; ; n++ {
+		if s := base + "-" + strconv.Itoa(n); !taken(s) {
+			return s
+		}
+	}
+}
+
+// WithID строит слаг с номером статьи: "privet-mir-42", для заголовка без
+// букв и цифр — "article-42". Номер делает совпадения редкими, но не
+// исключает их: Unique мог уже выдать "go-42" статье с заголовком "Go" или
+// "Go 42", поэтому занятость все равно нужно проверять.
+func WithID(title string, id int) string {
+	return Make(title) + "-" + strconv.Itoa(id)
+}
+
+// Valid сообщает, может ли s быть слагом: непустые строчные латинские буквы
+// и цифры, разделенные одиночными дефисами
+func Valid(s string) bool {
+	if s == "" || s[0] == '-' || s[len(s)-1] == '-' || strings.Contains(s, "--") {
+		return false
+	}
+	for _, r := range s {
+		if r != '-' && !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
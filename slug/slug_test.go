@@ -0,0 +1,73 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		title, want string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"Привет, мир!", "privet-mir"},
+		{"Щука и ёж: объявление", "shchuka-i-yozh-obyavlenie"},
+		{"Go 1.22 — что нового?", "go-1-22-chto-novogo"},
+		{"  --Trim__me--  ", "trim-me"},
+		{"Съешь же ещё этих мягких булок", "sesh-zhe-eshchyo-etikh-myagkikh-bulok"},
+		{"日本語", Fallback},
+		{"", Fallback},
+	}
+	for _, tt := range tests {
+		if got := Make(tt.title); got != tt.want {
+			t.Errorf("Make(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+		if got := Make(tt.title); !Valid(got) {
+			t.Errorf("Make(%q) = %q is not a valid slug", tt.title, got)
+		}
+	}
+}
+
+func TestMakeTruncatesAtWordBoundary(t *testing.T) {
+	got := Make(strings.Repeat("слово ", 30))
+	if len(got) > MaxLength {
+		t.Fatalf("len = %d, want <= %d", len(got), MaxLength)
+	}
+	if !strings.HasSuffix(got, "slovo") {
+		t.Fatalf("Make = %q, want to end with a whole word", got)
+	}
+}
+
+func TestUnique(t *testing.T) {
+	taken := map[string]bool{"go": true, "go-2": true, "go-4": true}
+	isTaken := func(s string) bool { return taken[s] }
+
+	if got := Unique("rust", isTaken); got != "rust" {
+		t.Fatalf("Unique(rust) = %q, want rust", got)
+	}
+	if got := Unique("go", isTaken); got != "go-3" {
+		t.Fatalf("Unique(go) = %q, want go-3", got)
+	}
+}
+
+func TestWithID(t *testing.T) {
+	if got := WithID("Привет, мир!", 42); got != "privet-mir-42" {
+		t.Fatalf("WithID = %q, want privet-mir-42", got)
+	}
+	if got := WithID("!!!", 7); got != Fallback+"-7" {
+		t.Fatalf("WithID = %q, want %s-7", got, Fallback)
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, s := range []string{"a", "go-1-22", "privet-mir"} {
+		if !Valid(s) {
+			t.Errorf("Valid(%q) = false", s)
+		}
+	}
+	for _, s := range []string{"", "-a", "a-", "a--b", "Go", "привет", "a_b", "a/b"} {
+		if Valid(s) {
+			t.Errorf("Valid(%q) = true", s)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go-articles-app/repository"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// runStats: stats [-o json] [-top N]
+func runStats(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlagSet("stats")
+	out := formatFlag(fs)
+	top := fs.Int("top", repository.DefaultTopAuthors, "number of top authors to show")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	stats, err := c.stats.Get(ctx, *top)
+	if err != nil {
+		return err
+	}
+	return c.print(*out, stats, func(w io.Writer) {
+		fmt.Fprintf(w, "Users:\t%d\t%s\n", stats.Users, breakdown(stats.UsersByRole))
+		fmt.Fprintf(w, "Articles:\t%d\t%s\n", stats.Articles, breakdown(stats.ArticlesByStatus))
+		fmt.Fprintf(w, "Views:\t%d\t\n", stats.Views)
+		fmt.Fprintf(w, "Comments:\t%d\t%s\n", sum(stats.CommentsByStatus), breakdown(stats.CommentsByStatus))
+		fmt.Fprintf(w, "Tags:\t%d\t\n", stats.Tags)
+		fmt.Fprintf(w, "Trash:\t%d\tusers %d, articles %d\n", stats.TrashedUsers+stats.TrashedArticles, stats.TrashedUsers, stats.TrashedArticles)
+		if len(stats.TopAuthors) == 0 {
+			return
+		}
+		fmt.Fprintln(w, "\nTOP AUTHORS")
+		fmt.Fprintln(w, "ID\tNAME\tARTICLES\tVIEWS")
+		for _, a := range stats.TopAuthors {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%d\n", a.ID, a.Name, a.Articles, a.Views)
+		}
+	})
+}
+
+// breakdown форматирует разбивку вида "draft 1, published 2" по алфавиту
+func breakdown[K ~string](counts map[K]int) string {
+	parts := make([]string, 0, len(counts))
+	for _, k := range slices.Sorted(maps.Keys(counts)) {
+		parts = append(parts, fmt.Sprintf("%s %d", k, counts[k]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sum[K comparable](counts map[K]int) int {
+	n := 0
+	for _, v := range counts {
+		n += v
+	}
+	return n
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-articles-app/auth"
+	"go-articles-app/models"
+	"go-articles-app/repository"
+	"io"
+	"net/mail"
+	"os"
+	"strings"
+)
+
+func runUsers(ctx context.Context, c *cli, args []string) error {
+	return subcommand(ctx, c, "users", args, map[string]func(context.Context, *cli, []string) error{
+		"list":   runUsersList,
+		"create": runUsersCreate,
+		"delete": runUsersDelete,
+	})
+}
+
+func printUsers(w io.Writer, users []*models.User) {
+	fmt.Fprintln(w, "ID\tEMAIL\tNAME\tROLE\tCREATED")
+	for _, u := range users {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", u.ID, u.Email, u.Name, u.Role, formatTime(u.CreatedAt))
+	}
+}
+
+// runUsersList: users list [-o json] [-limit N] [-cursor C] [-trash]
+func runUsersList(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlagSet("users list")
+	out := formatFlag(fs)
+	limit := fs.Int("limit", repository.DefaultPageSize, "users per page")
+	cursor := fs.String("cursor", "", "cursor from the previous page")
+	trash := fs.Bool("trash", false, "list users in the trash instead")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *trash {
+		users, err := c.users.ListTrash(ctx)
+		if err != nil {
+			return err
+		}
+		return c.print(*out, users, func(w io.Writer) { printUsers(w, users) })
+	}
+
+	page, err := c.users.GetAllPage(ctx, repository.PageRequest{Limit: *limit, Cursor: *cursor})
+	if err != nil {
+		return err
+	}
+	if err := c.print(*out, page, func(w io.Writer) { printUsers(w, page.Items) }); err != nil {
+		return err
+	}
+	if *out == formatTable && page.HasMore {
+		fmt.Fprintf(c.stderr, "more users: users list -cursor %s\n", page.NextCursor)
+	}
+	return nil
+}
+
+// runUsersCreate: users create -email E -name N [-role R] [-password-file F]
+func runUsersCreate(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlagSet("users create")
+	out := formatFlag(fs)
+	email := fs.String("email", "", "email (required)")
+	name := fs.String("name", "", "display name (required)")
+	role := fs.String("role", string(models.DefaultRole), "role: reader, author, editor or admin")
+	passwordFile := fs.String("password-file", "", "read the login password from this file (- for stdin)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	user := &models.User{
+		Email: strings.TrimSpace(*email),
+		Name:  strings.TrimSpace(*name),
+		Role:  models.Role(*role),
+	}
+	if user.Email == "" || user.Name == "" {
+		return errors.New("users create: -email and -name are required")
+	}
+	if addr, err := mail.ParseAddress(user.Email); err != nil || addr.Address != user.Email {
+		return fmt.Errorf("users create: invalid email %q", user.Email)
+	}
+	if !user.Role.Valid() {
+		return fmt.Errorf("users create: unknown role %q", user.Role)
+	}
+
+	if *passwordFile == "" {
+		if err := c.users.Create(ctx, user); err != nil {
+			return err
+		}
+	} else {
+		password, err := readPassword(c.stdin, *passwordFile)
+		if err != nil {
+			return err
+		}
+		if err := auth.NewService(c.users, auth.DefaultParams).Register(ctx, user, password); err != nil {
+			return err
+		}
+	}
+	return c.print(*out, user, func(w io.Writer) { printUsers(w, []*models.User{user}) })
+}
+
+// readPassword читает пароль из файла или stdin без завершающего перевода
+// строки. Флагом со значением пароль не передается, чтобы не попасть в
+// историю оболочки и список процессов.
+func readPassword(stdin io.Reader, path string) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// runUsersDelete: users delete ID — перемещает пользователя и его статьи в корзину
+func runUsersDelete(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlagSet("users delete")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	id, err := parseID(fs, "user")
+	if err != nil {
+		return err
+	}
+	if err := c.users.Delete(ctx, id); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "Moved user %d and their articles to the trash\n", id)
+	return nil
+}